@@ -1,10 +1,24 @@
 // Code generated by go-bindata.
 // sources:
 // shaders/.DS_Store
+// shaders/canvas.frag
+// shaders/canvas.frag.spv
+// shaders/canvas.vert
+// shaders/canvas.vert.spv
 // shaders/cube.frag
 // shaders/cube.frag.spv
 // shaders/cube.vert
 // shaders/cube.vert.spv
+// shaders/particles.comp
+// shaders/particles.comp.spv
+// shaders/particles.frag
+// shaders/particles.frag.spv
+// shaders/particles.vert
+// shaders/particles.vert.spv
+// shaders/present.frag
+// shaders/present.frag.spv
+// shaders/present.vert
+// shaders/present.vert.spv
 // textures/.DS_Store
 // textures/gopher.png
 // DO NOT EDIT!
@@ -94,7 +108,87 @@ func shadersDs_store() (*asset, error) {
 	return a, nil
 }
 
-var _shadersCubeFrag = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7c\x92\xcb\x6f\xe2\x48\x10\xc6\xef\xfe\x2b\x3e\x91\x0b\x44\x04\x1c\x94\xdd\x43\x50\x0e\x84\x3c\xd6\x0a\x02\x09\x93\x44\x39\xa1\xa6\x5d\xd8\xbd\x6b\x77\xf5\x76\xb7\x79\x68\x34\xff\xfb\xa8\x0d\x64\x12\xcd\x4c\x7c\x73\xd7\xaf\xbe\xfa\xea\xd1\x3f\x8f\x70\x8e\x31\x9b\xbd\x55\x79\xe1\xd1\x96\x1d\x0c\xe2\xcb\xbf\x2e\x06\xf1\xe5\xdf\x58\x14\x84\xa7\xc2\xb2\x66\x87\x47\xcb\xb5\x41\xa2\x65\xef\xab\x8c\x17\x51\x6e\x08\x63\xb6\x86\xad\xf0\x8a\xf5\x57\xf0\xa4\xd6\xc2\x3e\x76\x4f\xa2\x01\x9d\x28\x49\xda\x51\x86\x5a\x67\x64\xe1\x0b\xc2\xc8\x08\x59\xd0\x29\xd2\xc5\x0b\x59\xa7\x58\x63\xd0\x8b\xd1\x0e\x40\xeb\x18\x6a\x75\x86\x41\x62\xcf\x35\x2a\xb1\x87\x66\x8f\xda\x11\x7c\xa1\x1c\xd6\xaa\x24\xd0\x4e\x92\xf1\x50\x1a\x92\x2b\x53\x2a\xa1\x25\x61\xab\x7c\xd1\xd4\x39\xaa\x34\xed\xbd\x1d\x35\x78\xe5\x85\xd2\x10\x90\x6c\xf6\xe0\xf5\x47\x10\xc2\x1f\x4d\x87\xaf\xf0\xde\x5c\xf7\xfb\xdb\xed\xb6\x27\x1a\xc3\x3d\xb6\x79\xbf\x3c\xa0\xae\x3f\x49\xc6\xf7\xd3\xf4\xfe\x62\xd0\x8b\x8f\x49\xcf\xba\x24\xe7\x60\xe9\xff\x5a\x59\xca\xb0\xda\x43\x18\x53\x2a\x29\x56\x25\xa1\x14\x5b\xb0\x85\xc8\x2d\x51\x06\xcf\xc1\xf4\xd6\x2a\xaf\x74\xde\x85\xe3\xb5\xdf\x0a\x4b\x41\x26\x53\xce\x5b\xb5\xaa\xfd\xa7\x99\x9d\x2c\x2a\xf7\x09\x60\x0d\xa1\xd1\x1a\xa5\x48\xd2\x16\x6e\x47\x69\x92\x76\x83\xc8\x6b\xb2\xf8\x67\xf6\xbc\xc0\xeb\x68\x3e\x1f\x4d\x17\xc9\x7d\x8a\xd9\x1c\xe3\xd9\xf4\x2e\x59\x24\xb3\x69\x8a\xd9\x03\x46\xd3\x37\x3c\x25\xd3\xbb\x2e\x48\xf9\x82\x2c\x68\x67\x6c\xe8\x80\x2d\x54\x98\x26\x65\xcd\xe8\x52\xa2\x4f\x16\xd6\x7c\xb0\xe4\x0c\x49\xb5\x56\x12\xa5\xd0\x79\x2d\x72\x42\xce\x1b\xb2\x5a\xe9\x1c\x86\x6c\xa5\x5c\xd8\xaa\x83\xd0\x59\x90\x29\x55\xa5\x7c\x73\x41\xee\xd7\xbe\x42\xa1\x7e\x74\xb8\xdd\x07\x2b\xf2\x8a\xb4\x87\x2b\x44\xc0\x42\x3d\x59\xaf\x08\x19\x55\xdc\x70\x67\x9b\xe3\xc5\x5c\xc5\x71\x74\x46\x3b\x4f\xba\xf9\x7d\x9c\x2c\x47\xf3\xdb\xa5\x23\x23\xac\xf0\xb4\x3c\x08\x2c\x79\xf5\x2f\x49\xef\x70\x0d\xd2\x61\x17\xbf\x4b\x29\x44\xa6\x74\xbe\x3c\xb5\xb2\xbc\x1a\xc4\x46\xc8\xff\x7e\xe6\x94\x62\xcf\xb5\x47\x7b\xa5\x74\x20\x71\x83\xcb\x0e\x6a\xad\xd6\x6c\x2b\x38\x51\x99\x92\xec\xe0\x0e\x9e\x76\xc3\xe8\x1d\x2e\x59\x36\x1d\xe3\x06\x71\x27\x6c\x7c\x43\xf2\x2a\x30\x92\xd9\x66\xc3\x3f\x70\xe1\xa9\x01\xeb\x30\x8a\x31\x97\x6c\x87\xd1\x86\x55\x86\x4a\x28\xdd\xee\xe0\x5b\x04\x7c\x08\xe2\x26\x48\xfa\xda\x52\xdb\xd3\xae\xfb\xae\xdf\xdb\xed\x3b\xc3\xe8\x7b\xf4\x23\x00\x00\xff\xff\xab\x92\x34\xff\x13\x04\x00\x00")
+var _shadersCanvasFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xcf\xc1\x4e\xc3\x30\x0c\xc6\xf1\xbb\x9f\xe2\x93\xb8\xb4\xd5\xd4\x95\x69\x9c\xaa\x9d\x40\x3c\x01\x2f\x90\xb5\x59\xb1\xd4\xda\xc8\x71\x2b\x10\xe2\xdd\x51\x22\xd8\x2e\xcb\x31\xfa\xe5\x1f\x7b\xdf\x10\x1a\xbc\xb1\x78\x82\xbf\x47\x0c\xba\x45\x0b\x53\x44\x72\xb5\x38\x82\xa5\x5c\x5f\x54\x1c\xc1\xe7\x90\x5a\x42\xb3\xa7\x87\x2d\x5a\x62\x15\x1c\x9f\x3a\xa2\x39\x7c\xe9\xea\xa8\xce\x2c\x23\xcb\x84\x13\xba\x1a\xab\xf0\x45\x6d\x41\x0a\xcb\xc7\x1c\xed\xf0\x52\x22\xfd\x4d\xcf\x3a\x04\xcf\x8d\xc2\x59\xb0\xc5\xe1\x00\x8f\x9f\x83\xaa\x8d\xfd\x3d\xf7\xf8\xef\x8e\x70\x16\xbf\x6b\xba\x1a\xf9\x59\x41\xeb\xab\x85\xe9\x59\x67\xb5\x9e\x68\x53\x1e\xb1\x04\x96\xaa\xa6\x6f\xc2\xdf\xb9\x11\x9c\x4a\xb9\xca\xe5\xd6\xa6\xf3\xae\xfc\xd1\x06\x34\x79\x28\x5f\x2d\x56\x79\x83\xdd\x75\xc4\xba\xb5\xba\xa7\x1f\xfa\x1d\x00\x68\xf2\x80\x9c\x44\x01\x00\x00")
+
+func shadersCanvasFragBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersCanvasFrag,
+		"shaders/canvas.frag",
+	)
+}
+
+func shadersCanvasFrag() (*asset, error) {
+	bytes, err := shadersCanvasFragBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/canvas.frag", size: 324, mode: os.FileMode(420), modTime: time.Unix(1792393424, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersCanvasFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x93\xcd\xab\x12\x51\x18\xc6\x7f\x33\x67\x8e\xa3\x8d\xa9\x93\xa9\xa9\x7d\xf8\x51\x6d\x02\x69\x51\x11\x48\x41\x2b\x37\x42\x45\x50\xeb\xc8\x16\x2e\x52\xa8\x59\xf4\x0f\xdc\xff\xf9\x6e\x2e\x5c\xde\xf7\x3e\x03\x83\xe7\x5e\x18\x7e\xef\xe7\xf3\x9c\x83\x21\x5d\xe7\x90\x60\xe7\x25\x77\xa7\x24\xf5\x48\x41\xcb\xbf\xbb\xfd\xb7\xfd\xe6\x5f\x75\xd8\xbc\x79\xfb\xda\x0a\x7a\x04\xfb\x78\xae\x4f\x9b\x0c\x18\x02\x7f\x7e\x1e\x4f\x16\xbf\xef\x71\x28\x81\x01\xc1\x73\x39\x10\x89\x9e\xab\x7e\xff\xff\x75\x3e\xff\x3d\x58\x6d\x24\xf3\xda\xea\x78\xaa\x8c\x77\x64\x74\x80\x95\xb4\xd4\xbc\x6c\x70\x71\x91\x2f\x2e\xf2\xb6\xe3\x69\x83\xfb\xe2\x44\x5c\x8a\xed\x6f\x48\x4a\x0a\x8c\x09\xee\x6a\x01\x4c\xe9\xb8\xa7\xa0\x9e\xe6\xa9\x79\x46\x4a\x04\x26\x64\xb4\x54\x9b\x8a\x73\xb1\xcd\x58\x90\xd1\x56\x4f\xcd\xf7\xc4\x51\xdc\xd5\xdc\x96\xb8\x27\xce\xc5\x03\xcd\x33\x5e\x12\x78\xd0\xd8\x35\x56\xce\xf6\x8d\xc8\x78\x24\x0f\xf6\xff\x4a\x3c\x55\xde\x66\xcd\x34\xdb\xea\xe7\x04\x96\xd2\xb5\x95\xce\x8e\x7a\xb7\xd2\x59\x34\xb8\xab\xb7\x4d\xc4\x3d\xbd\x73\xcd\xa6\xb3\xd4\xec\x77\x44\xd7\x38\x54\xbf\x69\xbe\x26\xe5\x21\xf0\x41\x77\x34\x52\xff\x67\xda\xee\x63\xa2\xd8\x48\x3d\x89\x7c\x7e\x22\xba\xee\xb9\xea\xa7\x9a\x61\x7b\x1e\x2b\x6e\x6c\x3e\x9e\xc8\x83\x71\xd4\x3b\x17\x62\xbb\xdf\x67\xf2\xf0\x9d\xe8\xde\x57\xea\xb1\xba\x1f\x44\xd7\xb5\x50\xdc\x6a\xbf\x12\xfd\xee\xd6\x8d\x7b\xbd\x52\xec\xb9\xf6\x5b\xee\x8b\x7a\x5f\xc8\x87\xe5\x3e\x12\xfc\x37\x60\xb1\x1b\x12\xde\x93\x70\x3b\x00\xab\x22\xe8\x53\x70\x03\x00\x00")
+
+func shadersCanvasFragSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersCanvasFragSpv,
+		"shaders/canvas.frag.spv",
+	)
+}
+
+func shadersCanvasFragSpv() (*asset, error) {
+	bytes, err := shadersCanvasFragSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/canvas.frag.spv", size: 880, mode: os.FileMode(420), modTime: time.Unix(1792397634, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersCanvasVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x41\x6b\xe3\x30\x10\x85\xef\xf3\x2b\x1e\xec\x25\x0e\x89\xe3\x04\xef\x49\xf8\xb4\x7b\xd9\x5b\x60\x69\xaf\x41\xc8\x93\x46\xe0\x68\x54\x49\x36\x6e\x4b\xfe\x7b\xb1\xb1\xeb\x16\x12\x1d\x04\x7a\x7a\x9a\xef\xcd\x68\xb7\x26\xac\x71\xf8\x8b\x68\x02\xb3\xdb\x46\xaf\x0d\xe3\xb5\xd5\x75\xc4\x59\x02\x12\xf7\x09\xda\xd5\x78\xfa\xb7\x81\x97\x68\x93\x15\xc7\x35\xac\x83\xb7\x3d\x37\x31\x27\xac\x77\xf4\xab\xe3\x10\xad\x38\x94\xbf\x0b\xa2\x46\xbf\x49\x9b\xb0\xf2\x6d\xbc\x9c\x8c\xb8\x98\xb4\x4b\x19\x5a\x67\xcf\x12\xae\x38\xb6\xf1\xf2\x67\x52\x23\x3e\x08\xd3\xea\xd8\x1c\xa6\x1c\xff\xed\x3b\x2b\xba\xc1\x1b\xb5\x94\x6b\xc4\xe8\x01\x8f\x0a\x45\x36\x24\x18\x1f\x78\x89\xea\x9e\x65\xbf\x58\xda\xee\xae\xe3\x30\x3b\x4a\x18\x69\x24\x3c\x44\x0d\xd2\xc8\x4a\xdc\x1b\x91\x50\x3f\x02\x4e\xc6\x12\xc9\xba\xa4\x88\x86\xf3\x4b\x73\x3a\x72\x78\xe6\x90\xb8\xff\xd9\x6c\x39\xde\x4d\x33\x55\x74\x53\x44\x9d\xd8\x1a\x57\x6d\xdd\x2a\xa3\xc5\x3b\x63\x51\x8d\xad\x7c\xc9\xd6\x25\x54\x73\xf6\x59\xfd\x56\x13\xd5\x88\x59\x79\x89\xd8\xc1\x9b\x7c\x19\xee\xf0\xe9\x79\x81\x2d\xf6\x79\xb1\x41\x31\x6c\xfb\xbc\xc8\x14\xdd\xe8\x73\x00\x04\x3d\xf3\x1f\x13\x02\x00\x00")
+
+func shadersCanvasVertBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersCanvasVert,
+		"shaders/canvas.vert",
+	)
+}
+
+func shadersCanvasVert() (*asset, error) {
+	bytes, err := shadersCanvasVertBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/canvas.vert", size: 531, mode: os.FileMode(420), modTime: time.Unix(1792393424, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersCanvasVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x54\x4b\x4f\x13\x51\x14\xfe\x66\xe6\xce\x20\x50\xe8\xcb\x02\xbe\x6a\x79\x88\x0f\xb4\x2a\x16\x44\xf1\xb9\x82\x05\x0b\x08\xbf\xc0\x94\x2e\x9a\x68\x87\xb4\x83\x31\xae\x4c\xdc\xb8\xf2\xf7\xba\x31\x21\xdf\x99\xef\x26\x37\x4d\x27\x6d\x66\xbe\xc7\x3d\xe7\xdc\x73\xcf\x4c\x12\x6f\xce\x01\x11\x78\xbd\x43\x79\xd5\x11\x1b\xb3\x88\xcc\xee\x47\x27\xe7\x27\xdd\x49\x71\xd1\xed\xed\xbd\xa0\x61\x19\x09\x6f\xa6\x55\xb1\xc8\x47\xb4\x00\x7c\xfb\x32\x1c\xf1\x99\x4c\xc5\x7c\x40\x0d\x40\x03\x40\x13\x40\x86\x0c\xa9\x72\x4c\xfa\xe3\xc1\x60\x74\x3e\xfc\x39\x28\xf9\x39\xf1\x97\xf9\x64\x58\x0c\x73\x8b\xe3\x79\xe6\x29\x06\x3f\xfa\x79\x3e\xbe\x28\xf9\xd4\xf8\x98\xfc\x70\x54\x90\x4b\x91\x58\x25\x97\xf9\xc4\x9e\x99\xff\xea\x3b\x79\x67\x75\xf4\xf3\xaf\xf9\xd8\x7c\x29\x6a\x53\x79\x52\xa4\x68\x4c\xe5\xe0\xba\xe6\x54\xfc\x35\x00\xf9\x55\x81\x63\x8b\x42\x16\xd8\x54\xdd\xc7\xaa\x69\x16\x17\x89\xab\x05\x5c\x2c\xae\xc3\xfe\x22\xb1\x78\xe4\x8e\xe0\x6c\x1f\x6d\xc5\x20\xae\x08\x47\xc2\xcb\xc2\xde\x5f\x53\xcf\xbd\xbf\x31\xb5\xbe\x19\xac\x6f\x22\xb6\x75\x2b\x48\xec\x14\x99\x7f\x15\x0e\x0e\x30\x4c\xad\xad\x7a\x9c\xb4\x4c\x9a\x33\xad\xac\x3f\x13\xe6\xbd\x03\x87\x1b\x00\xe6\xad\x6f\x25\x5e\x50\x3e\x27\x7d\x49\xd8\xfb\xab\x8a\xe9\x71\x3d\xc8\xb1\x8e\x04\x37\x55\x0b\xb5\x55\xc0\x72\xf2\xdf\x82\xc3\x2d\xd5\xcd\xdf\x8e\xf0\x6d\xc5\xa7\xff\x8e\xbc\x2e\xd0\x3b\x41\xbc\x75\xe9\x59\xa0\x6f\x2b\x1e\xf5\xc7\xda\x8b\x5f\xcf\xbe\xd8\xe0\x03\x9f\x3c\xde\x35\xf8\xeb\xa3\xc7\x7b\x5a\x7f\x18\xf4\x62\x5e\x78\x41\xe7\x13\x05\xb8\x12\xe0\x25\xbd\x2b\x1e\x57\x35\x2b\x89\x70\x5d\xef\x90\xc7\x55\xbd\x4f\xc4\xfb\x48\xad\x4f\x2d\xe5\x67\xdf\xfe\x21\xc6\x8a\xbc\xec\xdd\x9a\x7a\xf7\x19\xa9\xf5\xe6\xae\x38\xf6\xec\xbd\xce\xfe\x9e\x78\xef\x69\xcf\xf0\xdc\x57\xdd\x1f\x90\xa0\x2d\x4c\x3f\xfb\xb9\x21\x7f\x47\xfe\x4c\xf3\xbd\x11\x78\xb6\x66\x78\x1e\x68\xef\x8c\xb9\x25\xec\xfd\x0f\xe5\xdf\x0e\xfc\x8f\xc4\xd3\xc3\x73\x7a\xa2\x3e\x7b\x0f\xeb\xdc\x11\xef\x3d\x4f\x67\x78\x9e\x89\xf7\xb8\xab\x33\xfa\x63\x5f\x0c\xe0\xb9\x38\xfa\xfe\x8a\x7b\x29\x9e\xb3\x70\x2a\xee\x95\x66\x81\xf3\xf0\x5b\x5c\x4f\x5e\x6a\xa7\xf6\x75\xe3\x39\x01\x3d\xcd\xc9\xae\x6a\xe3\x1e\x5f\x07\x7b\x64\x0f\x88\xf7\x55\x17\xcf\xec\x40\xfa\x19\x52\x8b\xf3\x46\x1c\xe4\xaf\x89\x3b\x53\xee\xb7\xd2\x23\xe9\x0d\x71\x7e\xfd\xa1\xf4\x58\x7a\x53\xdc\x7f\x44\x38\x40\x84\xeb\x01\x00\x04\x07\xe1\x2d\x10\x06\x00\x00")
+
+func shadersCanvasVertSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersCanvasVertSpv,
+		"shaders/canvas.vert.spv",
+	)
+}
+
+func shadersCanvasVertSpv() (*asset, error) {
+	bytes, err := shadersCanvasVertSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/canvas.vert.spv", size: 1552, mode: os.FileMode(420), modTime: time.Unix(1792397634, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersCubeFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x54\x51\x8f\xda\x46\x10\x7e\xe7\x57\x7c\xba\xbc\x98\xab\x63\x0c\xa1\x7d\x08\xba\x07\x72\x77\xb9\xa2\x5c\xa1\x3a\xc8\x45\x51\x55\xa1\xb5\x3d\xb6\xb7\xb5\x77\xdc\xdd\x35\x86\x54\xf9\xef\xd5\xda\x26\x1c\x29\x0a\x4f\x78\xe7\x9b\x6f\xbe\x6f\x66\x76\x47\xd7\x03\x5c\xe3\x96\xab\x83\x96\x59\x6e\xe1\xc5\x43\x4c\xc2\xf1\xcf\xaf\x27\xe1\xf8\x17\x6c\x72\xc2\x87\x5c\xb3\x62\x83\x07\xcd\x75\x85\x85\x8a\x83\x1f\x65\x3c\x8b\x62\x47\xb8\x65\x5d\xb1\x16\x56\xb2\xfa\x11\xf8\xb1\x56\x42\x3f\xf8\x47\x52\x07\x7d\x94\x31\x29\x43\x09\x6a\x95\x90\x86\xcd\x09\xf3\x4a\xc4\x39\x1d\x23\x3e\x9e\x49\x1b\xc9\x0a\x93\x20\x84\xe7\x00\x57\x7d\xe8\x6a\x38\x73\x14\x07\xae\x51\x8a\x03\x14\x5b\xd4\x86\x60\x73\x69\x90\xca\x82\x40\xfb\x98\x2a\x0b\xa9\x10\x73\x59\x15\x52\xa8\x98\xd0\x48\x9b\xc3\x9e\x0a\x38\x25\xf8\xdc\x73\x70\x64\x85\x54\x10\x88\xb9\x3a\x80\xd3\x97\x40\x08\xdb\x8b\x06\x80\xdc\xda\xea\xed\x68\xd4\x34\x4d\x20\x5a\xc1\x01\xeb\x6c\x54\x74\x50\x33\x7a\x5c\xdc\xde\x2f\xd7\xf7\xaf\x27\x41\xd8\x27\x7d\x54\x05\x19\x03\x4d\xff\xd4\x52\x53\x82\xe8\x00\x51\x55\x85\x8c\x45\x54\x10\x0a\xd1\x80\x35\x44\xa6\x89\x12\x58\x76\xa2\x1b\x2d\xad\x54\x99\x0f\xc3\xa9\x6d\x84\x26\xa7\x34\x91\xc6\x6a\x19\xd5\xf6\xac\x67\x47\x89\xd2\x9c\x01\x58\x41\x28\x5c\xcd\xd7\x58\xac\xaf\xf0\x6e\xbe\x5e\xac\x7d\x47\xf2\x69\xb1\xf9\x75\xf5\x71\x83\x4f\xf3\xa7\xa7\xf9\x72\xb3\xb8\x5f\x63\xf5\x84\xdb\xd5\xf2\x6e\xb1\x59\xac\x96\x6b\xac\xde\x63\xbe\xfc\x8c\x0f\x8b\xe5\x9d\x0f\x92\x36\x27\x0d\xda\x57\xda\x39\x60\x0d\xe9\xba\x49\x49\xdb\xba\x35\xd1\x99\x84\x94\xbb\x31\x9a\x8a\x62\x99\xca\x18\x85\x50\x59\x2d\x32\x42\xc6\x3b\xd2\x4a\xaa\x0c\x15\xe9\x52\x1a\x37\x55\x03\xa1\x12\x47\x53\xc8\x52\xda\x76\x83\xcc\xff\x7d\xb9\x42\xa3\x41\xb7\xbb\xef\xb5\xc8\x4a\x52\x16\x26\x17\x6e\x65\x5c\xbd\xb8\x8e\x08\x09\x95\xdc\xe2\x5e\xed\xfa\x8d\x99\x86\xe1\xe0\x15\xed\x2d\xa9\xf6\xf3\xe1\x71\x3b\x7f\x7a\xb7\x35\x54\x09\x2d\x2c\x6d\x3b\x82\x2d\x47\x7f\x51\x6c\x0d\xde\x82\x94\x9b\xc5\xa5\x94\x5c\x24\x52\x65\xdb\xa3\x95\xed\x74\x12\x56\x22\xfe\xfb\x94\x53\x88\x03\xd7\xd6\x33\x36\x19\x4f\x43\x1f\x91\x54\x2e\x01\x37\x08\x87\xa8\x95\x4c\x59\x97\x88\xea\x14\xff\x0e\xd0\xff\x4a\x61\xa7\xf8\xed\xf9\xf7\xd9\xf9\x49\xc9\x09\x15\xa7\xb3\x1d\xc5\x53\x14\xee\xaa\xde\x49\x3d\x03\x30\x1a\xc1\x72\x23\x74\x62\xda\x06\xb5\x21\x1f\x0d\x6e\x20\xca\x48\x92\xb2\x17\x52\x6f\xb9\x60\xfd\x1d\x67\xc5\x46\xba\x6e\xff\x31\x9e\x5c\xbf\xf9\xf3\xbb\xa0\xb0\x56\x5f\x0c\x28\xd6\xa5\x28\x8e\xa1\xaf\xa8\xa3\x3a\x9d\xf5\xe6\xe1\x9d\x5c\x8f\x4f\xae\x8d\x28\xab\x82\xf4\xe4\x0e\x96\xf6\xb3\xc1\x37\x70\xc1\x71\x3b\xee\xae\x47\x52\x75\x7a\x2d\xed\x63\x66\x9d\xcc\x2e\xe1\xc6\x47\xdc\x9b\x5e\xc8\x45\x54\x38\x84\x2b\xd0\xd2\xd5\x6e\x5b\x7a\xfb\x3b\x96\x09\x4a\x21\x95\x37\xec\xc6\xd0\x22\x62\x17\xc4\x8d\x13\x67\x6b\x4d\x9e\xa5\xbd\xff\x4d\x45\xb0\x3f\xb8\x37\x06\x48\x0b\x16\x16\x89\x4c\x53\xf7\xc2\xdc\xa0\x14\x7b\x2f\x61\xeb\x75\x32\xe4\x17\xea\xff\x0d\xfd\xb6\x25\xc1\x71\x62\xc1\xfe\xf0\x65\xe8\x23\x0c\xc2\x8e\xa7\xd5\xde\x06\x71\xd3\x1a\xf1\xce\xe1\xcd\x10\x3f\xbd\x60\x68\x95\x07\x3a\x8b\xda\x8b\xdf\x16\x6f\x69\x4e\xb6\x3a\x9a\xa9\x17\xbf\x40\xf6\x3b\xd1\x1d\x89\xe1\x6c\xf0\x75\xf0\xdf\x00\x00\x4e\x92\xd4\xf7\x05\x00\x00")
 
 func shadersCubeFragBytes() ([]byte, error) {
 	return bindataRead(
@@ -109,12 +203,12 @@ func shadersCubeFrag() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "shaders/cube.frag", size: 1043, mode: os.FileMode(420), modTime: time.Unix(1493752693, 0)}
+	info := bindataFileInfo{name: "shaders/cube.frag", size: 1527, mode: os.FileMode(420), modTime: time.Unix(1792397732, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _shadersCubeFragSpv = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x5c\x91\x5f\x8b\xd3\x50\x10\xc5\x7f\x37\x37\x69\x36\xd6\xdd\x76\x77\xfd\x83\x08\xb2\xab\xef\xa5\x48\x15\x41\x2a\xa8\x60\x5f\x0a\x82\x3e\xf8\x18\xae\x4d\x48\xab\xb5\x29\x49\x0a\x7e\x0c\x3f\xae\x2f\x82\xcc\x74\x0a\xed\x06\x2e\xf7\xce\x99\x33\x73\xe6\x4c\x7c\xf4\x22\x05\x87\xe3\x8c\x87\xec\xbf\x4b\x22\x1c\xd0\xa7\xa7\xf7\x6c\xfe\x75\x3e\x6a\xbb\x62\x34\x79\x35\x96\xfc\x05\x5e\x79\x92\x1b\x90\x12\x83\x9e\x5f\x61\xb5\x11\x3c\x03\x86\x7a\xbc\xe2\x29\xe0\xf1\x44\xc0\x1f\x27\xdc\x8c\xd9\x3c\x7f\xff\xe5\x43\xde\x96\xdb\xd0\x84\xae\xcc\xdb\x65\x28\xca\x26\xaf\xbf\xff\x28\x17\x5d\x7b\xca\x59\x86\x62\xb5\xa9\xf2\x75\xd8\x54\xbb\x50\x95\xf9\xe4\xe5\x78\x1b\x16\x3f\x49\x88\x4f\x74\x13\x12\xd5\xde\x7d\x6a\x42\xf5\xb1\x5e\xd7\x8d\x60\x9e\x73\xa0\x2b\x7f\x6b\x7e\xb8\x7f\x2f\xea\xba\x29\xa4\x66\xa6\x4a\xf0\xcc\xbc\x4b\x2c\xfc\xe7\x77\xe2\x5b\xf3\x2b\xf1\xf0\x88\x7f\x4d\xa4\xbe\x6e\xd5\x21\xfa\x7e\x84\xa7\x07\xdc\x00\x8f\x89\xd5\x7f\xcf\x76\x74\x43\xcc\x99\xee\x63\xbf\x97\xb7\x16\x67\x86\x3d\x21\xe3\x9e\xf1\x1d\xa7\xdf\x21\x7e\x8a\xa7\x0f\xca\x93\x7e\xf7\x0d\xef\x5b\x3f\x89\xcf\x0d\x93\xfc\xc0\x6a\x0f\x7a\x03\xfb\x3f\xce\xe6\xbb\x34\x3d\x99\xfd\x35\x89\xde\xb1\xd5\xcb\x4c\x7f\x89\x48\x80\x29\xb1\x6a\x5c\x58\xff\xa9\x79\xbb\xb2\x7e\x9f\x49\xb5\xd7\xb5\x61\x57\x47\x73\x7f\x23\x51\xee\x03\xab\x17\xce\x3b\xbc\xfa\x16\xec\x1f\x8e\x37\x38\xfe\x07\x00\x00\xff\xff\xa8\x9c\x79\x6d\x90\x02\x00\x00")
+var _shadersCubeFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x95\x5b\x4f\x13\x5b\x14\xc7\xff\x33\xb3\xf7\xb4\xa5\xe5\xd2\x53\xa0\x5c\x7b\xca\xe5\x9c\xc3\x01\x45\x94\x8b\x48\x05\x41\xd0\x56\x04\xa9\x31\xc1\x67\x22\x44\x9b\x14\xc6\x94\x3e\xf8\xa8\x0f\xbe\x1a\x1f\xfc\x00\x7e\x34\x3f\x86\x2f\x26\x66\xad\xfe\x47\x76\x26\x33\x61\x32\x5d\xbf\x75\xdd\x6b\xad\x6c\x02\x7f\x2e\x03\x78\x90\xa7\x89\xde\x53\x84\xaf\x24\x8f\x50\xbf\xf5\xa3\x57\x47\xcb\xd7\xdd\xf3\xe5\xb5\xf5\x15\x31\x18\x40\x20\x1f\xd5\x0d\x22\x0b\x03\x60\x12\xc0\xe5\x59\xeb\x4a\xf8\x08\x80\x32\x80\x71\x00\x43\x08\x54\x97\x01\x10\xc2\x20\xc7\x1c\xc7\xa7\x4d\x84\xb0\x2a\x4b\x9c\xcb\xe8\xfc\xa2\xad\x36\xa1\x32\x1f\x40\xbb\xf5\xf6\x5d\xf7\xa0\xd5\x81\xc3\x83\x98\xef\x47\xed\xa8\x73\xc3\xa5\x86\xf7\xd1\x75\xab\xdb\x8a\xb4\x86\x38\xb6\x05\x70\xd6\xed\x32\x46\x8f\x85\x00\xae\xa2\xce\xe5\x59\x5b\xf4\x56\xeb\xed\x5e\x7c\x78\x13\x45\x9d\x73\xb1\xb3\x30\x28\x3b\x36\x75\x18\x64\xe9\x37\x04\xa0\xc1\xd8\xf2\x37\xc7\x6f\xc3\x39\x9b\x4d\xd8\x64\x12\x7e\x1e\xfd\x76\x1d\x3f\x2f\xe1\xe7\xa5\xf8\xf9\xf4\xfb\xe8\xb0\x80\xec\xab\xc3\x0c\xd9\x77\x87\x59\xb2\x1f\xfe\x0d\x0b\xc9\xaa\x16\xa8\x23\x40\x3f\x73\x88\xbe\x3f\x71\x3e\xe9\xc1\x20\x80\xd9\x84\x3c\xe3\xc8\xc5\x84\xbe\x48\xbd\x47\x7d\x29\xa1\x2f\x25\xf4\x32\x87\x8a\xa3\x2f\x53\x8e\xf5\xe3\x8e\xbe\x04\x5f\x6b\x1d\x45\xa0\xdb\x58\xd5\x9d\x33\xba\x8b\x01\x7b\x30\x06\x03\xcb\xdf\xf2\x8e\xc0\xe8\x0c\xab\x8c\xb1\x44\x59\xfa\x3c\x0f\x60\x8a\x73\x36\x64\x15\xe4\xfe\xf4\xce\x8d\x93\x75\xde\x71\xe4\xd0\xc7\x9c\x1e\xe3\xc6\x4f\x2c\x4f\xc0\x47\x9e\xf5\x15\x68\x2b\x35\x56\xd8\x73\xc9\x51\x85\xc1\x00\xfb\xdf\x4f\x79\x88\xfe\x7d\x94\xff\xa2\x9c\xa7\x3c\xcc\x1c\x86\xf2\x28\xe5\x02\xe5\x31\xe6\x11\xfd\x0c\x02\x4c\x30\x7e\x19\x06\x15\xea\xa4\x87\x93\x08\x30\xe7\xe4\xf9\x8f\x3c\xe7\xf4\x68\x21\xd1\xb3\x45\xda\x88\xfd\x12\x7f\x1b\xea\x25\xee\x6a\xc2\x7e\x8b\xf9\xc4\xbe\x46\x7b\xb1\xab\xf1\xdc\x83\x64\x35\x9e\xbb\x48\xff\x1a\xcf\x5d\x72\xe4\x61\xde\x31\x1e\xe5\x51\xde\x37\xb1\x3c\xc6\xbb\x47\xe2\x6f\xc0\xf2\x8c\xf1\x2c\x80\x9f\xf0\x31\x05\x60\x9b\xfb\x32\xcd\x78\x27\xc8\x68\x5f\xfe\x26\x9b\x66\x4e\x8f\xb6\x7d\xdc\x9d\x22\xe5\x3c\xf7\x57\x6a\x3b\x85\xd5\x1e\xce\x6b\x0f\x7b\xfc\xb5\xde\x24\xbd\x9d\x9f\x67\xdc\x6d\xee\xc0\x3f\xac\xb9\x80\x50\xe5\x7f\x99\xe7\x09\x75\x7b\xb0\x3a\x87\xff\xd9\x9b\x05\x32\xe9\xf5\x2d\xf2\x45\xc6\x93\x1c\xb7\xc9\x63\xbf\xe5\x14\xbf\x3b\xe4\xae\xdf\x0a\xf9\x09\xb2\x5a\xc7\x5d\xb2\x15\xe7\xec\xd2\xbf\x6f\xb0\x3a\xbf\x7b\xac\x55\xec\x0a\xc8\x28\x5b\xa3\xdd\x02\xf5\xab\x4e\x1d\xeb\x29\x75\x6c\x90\xbb\x75\xdc\x27\x8f\xfd\x36\x53\xfc\x1e\x90\x2f\x92\xc9\x1e\x3d\x24\xdf\x62\x2c\xa9\x67\x9b\x3c\x8e\xb5\x93\x12\xeb\x11\xb9\x1b\x6b\x97\xdc\x8d\xb5\x47\xde\xe4\x9c\x1e\x93\xdd\xbc\xbd\x1c\xfb\x29\x39\x0e\xc8\xe3\x78\x86\xf3\x3d\x70\xe6\xfb\x34\xc5\xaf\x4e\xee\xfa\x35\xc8\xe3\x39\x3d\x23\x6b\x24\xe6\xf4\x05\x56\xe7\x78\x48\x1b\x99\xcd\x27\xb2\xe7\xac\xff\xd0\x89\x73\xc4\xfd\x9c\x4d\xc4\xf9\x4c\x9f\x63\xda\x88\xef\x4b\xee\xc0\x0b\xda\x4b\x7f\x9a\xdc\xf1\x13\xda\x8a\x6e\x07\x81\xfe\xef\x17\xf6\x0b\x1e\x36\xe1\xe1\xf7\x00\x3a\x5a\x22\xda\x68\x08\x00\x00")
 
 func shadersCubeFragSpvBytes() ([]byte, error) {
 	return bindataRead(
@@ -129,12 +223,12 @@ func shadersCubeFragSpv() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "shaders/cube.frag.spv", size: 656, mode: os.FileMode(420), modTime: time.Unix(1494028127, 0)}
+	info := bindataFileInfo{name: "shaders/cube.frag.spv", size: 2152, mode: os.FileMode(420), modTime: time.Unix(1792397736, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _shadersCubeVert = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7c\x52\x4d\x8f\xdb\x36\x14\xbc\xeb\x57\x0c\x36\x17\xaf\xe1\xd8\xb2\xeb\xf6\x10\x23\x07\xc7\xd9\x6e\x85\x38\xf6\xc2\x72\x1c\x04\x45\x21\x50\xd4\x93\xc4\x56\x22\x59\x92\xf2\x07\x82\xfd\xef\x05\x25\x39\x8d\x9b\x62\x79\x92\xf8\x66\xe6\xcd\x1b\xbe\xc9\x30\xc0\x10\x2b\xa5\x2f\x46\x14\xa5\xc3\x80\xdf\x63\x16\x4e\x7f\x7e\x3d\x0b\xa7\xbf\x60\x5f\x12\x3e\x94\x46\x49\x65\xf1\x68\x54\xa3\x11\x49\x3e\x7e\x89\x71\x60\xd5\x91\xb0\x52\x46\x2b\xc3\x9c\x50\xf2\x25\xf0\xba\x91\xcc\x3c\x8e\xae\xa2\x1e\xba\x16\x9c\xa4\xa5\x0c\x8d\xcc\xc8\xc0\x95\x84\xa5\x66\xbc\xa4\x6b\x65\x84\x03\x19\x2b\x94\xc4\x6c\x1c\x62\xe0\x01\x77\x7d\xe9\xee\x7e\xe1\x25\x2e\xaa\x41\xcd\x2e\x90\xca\xa1\xb1\x04\x57\x0a\x8b\x5c\x54\x04\x3a\x73\xd2\x0e\x42\x82\xab\x5a\x57\x82\x49\x4e\x38\x09\x57\xb6\x7d\x7a\x95\x76\xbc\x2f\xbd\x86\x4a\x1d\x13\x12\x0c\x5c\xe9\x0b\x54\xfe\x3d\x10\xcc\xf5\xa6\xfd\x29\x9d\xd3\x6f\x26\x93\xd3\xe9\x34\x66\xad\xe1\xb1\x32\xc5\xa4\xea\xa0\x76\xb2\x8e\x56\x0f\x9b\xf8\xe1\xf5\x6c\x1c\xf6\xa4\x4f\xb2\x22\x6b\x61\xe8\xef\x46\x18\xca\x90\x5e\xc0\xb4\xae\x04\x67\x69\x45\xa8\xd8\x09\xca\x80\x15\x86\x28\x83\x53\xde\xf4\xc9\x08\x27\x64\x31\x82\x55\xb9\x3b\x31\x43\x5e\x26\x13\xd6\x19\x91\x36\xee\x26\xb3\xab\x45\x61\x6f\x00\x4a\x82\x49\xdc\x2d\x63\x44\xf1\x1d\xde\x2d\xe3\x28\x1e\x79\x91\xcf\xd1\xfe\xb7\xed\xa7\x3d\x3e\x2f\x77\xbb\xe5\x66\x1f\x3d\xc4\xd8\xee\xb0\xda\x6e\xde\x47\xfb\x68\xbb\x89\xb1\xfd\x15\xcb\xcd\x17\x7c\x88\x36\xef\x47\x20\xe1\x4a\x32\xa0\xb3\x36\x7e\x02\x65\x20\x7c\x9a\x94\xb5\xd1\xc5\x44\x37\x16\x72\xd5\x59\xb2\x9a\xb8\xc8\x05\x47\xc5\x64\xd1\xb0\x82\x50\xa8\x23\x19\x29\x64\x01\x4d\xa6\x16\xd6\xbf\xaa\x05\x93\x99\x97\xa9\x44\x2d\x5c\xbb\x41\xf6\xc7\xb9\x7c\xa3\x49\xd0\xed\xee\x81\x8c\xa3\x33\x6c\xc9\x3c\xa8\xb1\x5d\x94\xab\x26\x25\x64\x54\xab\x0e\xfa\xea\xd8\x2f\xcd\x3c\x0c\x83\x57\x74\x76\x24\xdb\xdf\xc7\x75\xb2\xdc\xbd\x4b\x2c\x69\x66\x98\xa3\xa4\x53\x49\x54\xfa\x27\x71\x67\xf1\x06\x24\xfd\x73\xfc\x1f\xa5\x64\x99\x90\x45\x72\x9d\x26\x99\xcf\x42\xcd\xf8\x5f\xff\x72\x2a\x76\x51\x8d\x1b\x58\x97\x4d\xe7\xe1\x08\xa9\x90\x9e\x80\xb7\x08\xef\xd1\x48\x91\x2b\x53\x23\x6d\x72\x7c\x0d\xd0\x9f\x9a\xb9\x39\x3e\x1e\x9e\x16\xdf\x6e\x8e\xc4\xe7\xd0\xca\x0a\x1f\xc4\xef\xd3\xd9\xf0\xa7\x3f\xfe\x53\x64\xce\x99\x6b\xe1\x19\x4d\xda\xe4\x8b\xa0\x6f\x8d\x41\xa5\x78\x1b\x61\xd7\xd4\x5f\xb5\x1c\x47\x67\xae\x94\xc9\x16\x41\xe0\xef\x8a\x2a\x79\x22\xd3\xe7\xf8\xf5\x56\xde\xd7\xfa\xf6\x8b\xe0\x79\x11\x04\x47\x25\x32\xd4\x4c\xc8\xc1\x3d\x82\x16\x7c\x55\xc3\xdb\xb6\xfd\xb8\x75\x54\x54\x49\x27\x18\xc9\x8c\xce\x9d\xe9\xef\xb4\xae\xd8\x8f\x87\x27\x0c\xbb\xcf\x6f\x53\xfe\x40\x7d\x0e\xfe\x09\x00\x00\xff\xff\x6f\xdd\xdf\xeb\xa6\x04\x00\x00")
+var _shadersCubeVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x53\x5d\x6f\xdb\x46\x10\x7c\xe7\xaf\x18\x38\x2f\x92\xa1\x48\x94\xac\xf6\x21\x82\x1f\x14\xd9\x75\x89\x38\x92\x61\x29\x0a\x82\xa2\x10\x8e\xe4\x8a\xbc\xf6\x78\xcb\xde\x1d\xf5\xd1\xc2\xff\xbd\x38\x92\x8a\x63\x47\xb0\xde\x74\x3b\x33\x3b\xb3\xbb\x1c\x5c\x06\xb8\xc4\x8c\xcb\xa3\x91\x59\xee\xd0\x49\xba\x18\x85\xc3\x5f\xde\x8f\xc2\xe1\xaf\x58\xe5\x84\x4f\xb9\x61\xcd\x16\x77\x86\xab\x12\x91\x4e\xfa\x6f\x31\xd6\x42\xed\x08\x33\x36\x25\x1b\xe1\x24\xeb\xb7\xc0\xf7\x95\x16\xe6\xae\x77\x12\xf5\xd0\x7b\x99\x90\xb6\x94\xa2\xd2\x29\x19\xb8\x9c\x30\x2d\x45\x92\xd3\xa9\xd2\xc3\x9a\x8c\x95\xac\x31\xea\x87\xe8\x78\xc0\x45\x5b\xba\xe8\x4e\xbc\xc4\x91\x2b\x14\xe2\x08\xcd\x0e\x95\x25\xb8\x5c\x5a\x6c\xa5\x22\xd0\x21\xa1\xd2\x41\x6a\x24\x5c\x94\x4a\x0a\x9d\x10\xf6\xd2\xe5\x70\xcf\x0d\xbc\x13\x7c\x6b\x35\x38\x76\x42\x6a\x08\x24\x5c\x1e\xc1\xdb\x1f\x81\x10\xae\x35\x0d\x00\xb9\x73\xe5\x87\xc1\x60\xbf\xdf\xf7\x45\x6d\xb8\xcf\x26\x1b\xa8\x06\x6a\x07\xf7\xd1\xec\x76\xbe\xbc\x7d\x3f\xea\x87\x2d\xe9\x8b\x56\x64\x2d\x0c\xfd\x53\x49\x43\x29\xe2\x23\x44\x59\x2a\x99\x88\x58\x11\x94\xd8\x83\x0d\x44\x66\x88\x52\x38\xf6\xa6\xf7\x46\x3a\xa9\xb3\x1e\x2c\x6f\xdd\x5e\x18\xf2\x4e\x53\x69\x9d\x91\x71\xe5\x5e\xcc\xec\x64\x51\xda\x17\x00\xd6\x10\x1a\x17\xd3\x25\xa2\xe5\x05\x3e\x4e\x97\xd1\xb2\xe7\x45\xbe\x46\xab\xdf\x17\x5f\x56\xf8\x3a\x7d\x7c\x9c\xce\x57\xd1\xed\x12\x8b\x47\xcc\x16\xf3\x9b\x68\x15\x2d\xe6\x4b\x2c\x7e\xc3\x74\xfe\x0d\x9f\xa2\xf9\x4d\x0f\x24\x5d\x4e\x06\x74\x28\x8d\x4f\xc0\x06\xd2\x4f\x93\xd2\x7a\x74\x4b\xa2\x17\x16\xb6\xdc\xac\xd1\x96\x94\xc8\xad\x4c\xa0\x84\xce\x2a\x91\x11\x32\xde\x91\xd1\x52\x67\x28\xc9\x14\xd2\xfa\xad\x5a\x08\x9d\x7a\x19\x25\x0b\xe9\xea\x0b\xb2\x3f\xe7\xf2\x8d\x06\x41\x73\xbb\x6b\x32\x8e\x0e\xb0\xb9\xf0\x07\x53\xf9\xdb\x89\x8f\x98\x55\x31\x21\xa5\x82\x1b\xe8\xbb\x5d\x7b\x34\xe3\x30\x0c\xde\xd1\xc1\x91\xae\xff\xde\xdd\x6f\xa6\x8f\x1f\x37\x96\x4a\x61\x84\xa3\x4d\xa3\xb2\xe1\xf8\x2f\x4a\x9c\xc5\x07\x90\xf6\xeb\x38\x47\xc9\x45\x2a\x75\xb6\x39\xa5\xd9\x8c\x47\x61\x29\x92\xbf\x9f\x39\x4a\x1c\xb9\x72\x1d\xeb\xd2\xe1\x38\xec\x21\x96\xda\x13\x70\x8d\xb0\x8b\x4a\xcb\x2d\x9b\x02\x71\xb5\xc5\x7f\x01\xda\x5f\x21\xdc\x18\x9f\xd7\x0f\x93\x97\x2f\x05\xa7\xa4\x9e\xdf\x76\x94\x8c\xa1\xfc\xd7\x7a\x23\xcd\xb9\xe7\x19\x2b\x7e\x5d\x28\xd9\x4a\x3f\xcc\x3f\x86\xa3\xcb\xab\x3f\x5f\x15\x85\x73\xe6\x6c\x41\xb3\x29\x84\x3a\x95\x9e\x50\xc5\xd5\x76\x12\xb4\xc9\xd0\x51\x9c\xd4\x1b\x6a\x32\xf9\xa7\x9a\xe5\xe8\x90\x30\x9b\x74\x72\x0e\x38\xfc\x0e\xbc\x6a\xe5\x27\x41\xe0\x5f\x32\xb5\x79\x20\xd3\x6e\xf3\x79\x26\xb5\xa2\xaf\xb5\x01\x26\xc1\xd3\x24\x08\x76\x2c\x53\x14\x42\xea\x4e\x17\x41\x0d\x3e\x35\xc5\x75\xed\xb2\x5f\x67\xca\xd4\xa6\x11\x8c\x74\x4a\x87\x26\x76\xd3\x14\xd7\x7e\xb6\x57\x9d\x1a\x5b\x0f\xb8\x8b\xcb\x86\xd9\x86\x7e\xc5\xed\x1f\x8e\xff\xd6\xfc\x1f\xbc\x9c\x7a\x7d\x5e\x3f\x9c\xc8\xdf\xe7\xfc\x53\xeb\xa7\xe0\xff\x01\x00\x67\x09\x99\x5a\x6c\x05\x00\x00")
 
 func shadersCubeVertBytes() ([]byte, error) {
 	return bindataRead(
@@ -149,12 +243,12 @@ func shadersCubeVert() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "shaders/cube.vert", size: 1190, mode: os.FileMode(420), modTime: time.Unix(1493752703, 0)}
+	info := bindataFileInfo{name: "shaders/cube.vert", size: 1388, mode: os.FileMode(420), modTime: time.Unix(1792397732, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _shadersCubeVertSpv = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x5c\x53\x5d\x6f\xd3\x40\x10\x1c\xdb\x77\x4e\x9b\xd2\x26\x4d\xbf\xdb\x24\x4d\x9a\x50\x24\x90\xaa\x0a\x15\x84\x84\x8a\x28\x2f\x01\x29\x48\x15\x48\x7d\xb5\xdc\xe4\x48\x03\x25\x89\x6c\x47\xca\x23\x3f\x01\xfe\x2d\x2f\x48\xe8\xf6\x26\xc6\xc5\x2f\xf6\xcc\xec\xee\xec\x9e\xf7\x02\xbf\x53\x02\x3c\x78\x58\xc1\x13\xb8\x67\x13\x3e\x3c\x00\x6b\x08\xe5\xdd\xeb\x7f\xee\x9f\xa5\xd9\xf0\xec\xe2\xc5\xb9\xd5\x37\x10\x48\x9c\xd5\x2a\x58\x91\x6f\x05\xe0\x7b\x3c\x9e\xd8\xef\x55\x00\x3b\x00\xea\x00\x02\x04\xf0\x01\xfc\xf4\x6c\xcc\x2a\x7a\xfd\xe8\xea\xd3\xbb\x28\x35\xb3\x38\x89\x33\x13\xa5\x77\xf1\xd0\x24\xd1\xf4\xf6\xab\x19\x64\xe9\xc3\x98\xbb\x78\x38\x9e\x8c\xa2\xfb\x78\x32\x9a\xc7\x23\x13\x5d\x3c\x3f\x9f\xc5\x83\x6f\xd0\x50\x0f\xfc\x34\xb4\x78\x66\x66\x31\x98\x4e\x93\xa1\xe3\x02\x54\x00\xdc\xce\xbf\x20\x84\x92\x6f\xfb\x7c\xbc\xb9\x46\x88\x50\xb0\xed\x7f\x36\x4d\xc7\xd9\x78\x2a\x75\x42\x68\xe1\x6d\xbf\x71\x96\x25\xae\x8e\xc2\x26\x80\xb9\x2d\x24\x38\x94\xd9\x46\xf7\xd1\x8d\x49\x32\xb3\xf8\x30\x19\x9a\x85\xe3\x0f\x1d\x7f\x6d\x12\x27\xb9\x9a\x8e\x07\xb5\xa5\x9d\xed\xaf\x4e\xbe\x27\x53\x03\xcd\x02\x5e\x97\x5c\xa0\x4a\xbc\x51\xc0\xef\x0b\xf3\x68\xc1\x3a\xc7\x1d\xbe\x8b\x5c\x29\xcf\xd3\xf9\xdc\x36\xee\x6d\x81\xf3\xc9\xfd\xf0\xad\x5f\x90\x73\x3d\xce\x7f\x52\xe8\xcd\xe2\x76\x01\xef\xc8\xae\x00\x4f\x59\x6f\x39\xef\x5a\x1e\x13\x08\x67\xeb\x6d\xc1\x97\x77\x5b\x36\xc3\x71\xbb\x08\x64\xb6\x16\x80\x3d\x28\xe9\x37\xe4\x4e\xb5\xa0\x64\xc3\x02\xce\xf1\x9a\x78\x95\xdc\x3e\x14\xca\xd4\x94\xec\x9d\x12\xdf\x16\xbd\x9f\x11\x3f\x02\xd0\x95\x9d\x74\x67\x5b\x22\x57\xe7\xd9\x2e\x71\x93\xe7\x61\x6b\xae\xcb\xae\xbb\x1e\xaa\xec\xb5\xc2\x1e\xaa\x72\x4f\x1c\x67\x3d\x6b\xf4\xf4\xe8\x59\x93\x59\x9d\x6e\xf3\xb7\xa9\xd5\x98\xbf\xcd\x3b\xe2\x51\xdf\x63\x6c\x49\x7a\x70\xe7\x55\xa2\x76\xc4\x59\x0f\x99\x7b\x94\xdf\xad\x7f\x5e\x0d\xce\x6b\xe3\x9b\xac\x55\x2e\xe8\x6d\x7a\xbd\x84\x16\x4d\x31\xde\xd6\xf8\x0d\x5f\xf6\xe8\x92\xb1\xbb\xec\xed\x0a\xa1\xf4\xb5\xcf\x59\xb7\xa8\x5d\xf2\x1f\x1d\x50\x7b\x83\x40\xfe\xc7\x81\xe4\x68\xf1\x3f\x66\x4e\x83\xf1\x65\x9e\xcf\x71\xc1\xe7\xe4\x3f\x9f\x0e\x73\xda\xd4\x96\x3e\x5d\x6a\xbf\xa0\x05\x3f\x66\xad\x2e\xfd\xec\x3e\x9c\xf2\x4c\x1a\xec\xe7\x94\x71\x7f\xe0\xe1\x15\x3c\xfc\x0d\x00\x00\xff\xff\xcb\x5f\x21\xe8\xe8\x04\x00\x00")
+var _shadersCubeVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x96\x7f\x57\x14\x65\x14\xc7\xbf\xbb\x3b\xb3\x02\x1b\x22\x2d\xac\x14\x48\x6b\x6b\x59\x58\x68\xa6\x66\x12\x1a\x69\x82\x89\x21\x5a\x96\x69\x16\xb9\x9b\x6c\x2e\x8c\x2d\x23\x51\xfd\xd3\x4b\xa8\x77\xd0\x4b\xeb\x65\xf4\x4f\xe7\x74\xee\xec\xe7\xea\xc3\x34\x71\xe4\xec\xdc\xcf\x73\xef\xf7\xfe\x78\xee\xac\x54\xca\xad\x03\x52\x49\xf6\xf3\xab\x06\x3f\xe3\x2a\x67\xa4\xa6\x6a\xf6\xb9\xbc\x7a\x7b\x75\x7e\x27\x6d\xcf\x9f\x39\x7b\xca\x1c\x0e\xaa\x62\x1f\xd9\xd9\x98\x86\xed\x51\x53\x92\xb6\x36\xba\xdb\xf6\x3c\x2e\xa9\x2e\x69\x42\x52\x43\x52\x55\x11\x5e\xd2\x8d\x3b\x37\x55\x55\x9c\xd9\x16\xbf\x95\xb4\x3b\xbd\xcc\xa7\x9a\xb1\xb2\xa4\x5e\xf7\xd1\x66\x7a\xa5\xdb\x57\xc0\x2b\xce\x2f\x27\xbd\xa4\xff\x9c\x47\x92\x9e\x24\x3b\xdd\xb4\x9b\x64\xb9\x5d\x3b\x96\xb4\x91\xa6\x68\x0c\x58\x55\xd2\x76\xd2\xdf\xda\xe8\xd9\x73\x55\x35\x6a\xda\x1f\x3f\xe0\x56\x5b\xda\xd9\x7b\x98\x24\xfd\xb6\x6b\xd4\xa8\xcf\x35\x62\xc5\x59\xaf\xbb\x9d\x7e\xda\xd9\xbb\xb6\xdd\xee\xec\x65\xac\x9e\xd3\x34\x36\x91\xd3\x8b\x15\xa9\xb1\x4f\xab\xa2\x69\x49\xc9\xd3\x54\xcb\x8a\x34\x44\xbd\x87\x24\xad\xd0\x93\xfd\x6b\xf1\xb9\x12\xcc\x34\xce\xf9\x1c\xc8\xc5\x95\x88\xfb\x30\x88\x2b\xe5\xe2\x4a\x05\x71\x65\xe2\x7e\x0b\x58\x05\xf6\x7b\xc0\x22\xd8\x9f\x01\x8b\x61\x7f\x95\x9f\xb3\x2a\xac\x19\x0f\x58\xed\x3f\x3d\xc5\xcf\x66\xdf\x0a\x6a\xf1\xb9\x1b\x6b\xda\x3e\xaa\xa2\x51\x98\x9d\x8f\xe6\x74\x6c\x7e\x63\x92\x5e\xcd\xd9\x47\x03\xdb\xee\xcd\x74\xe7\xb0\xeb\xd8\x7e\x6e\xf7\x35\x1b\xd8\x0d\x6c\xab\xad\xae\x72\x96\xbb\xa1\x4a\xf6\x26\x58\x4d\x87\x15\x29\x62\x3e\xf6\x39\xa5\x48\x31\xcf\xf6\x3b\xa9\x28\xbb\xcf\x26\x9a\x27\xb0\x6d\xe6\xc7\x24\xcd\x70\xe7\x11\x6c\x56\xc3\xcf\xe6\x18\xea\x0c\x05\xbf\x96\x73\x84\x9c\x96\x77\x96\x59\xb9\xef\x08\x75\xbc\xc0\xf3\xc0\x67\x30\x3b\xd3\x6e\x2a\xd2\x41\xe6\x38\x8a\x7d\x88\x1e\xab\xd8\x2f\x12\x17\x61\x4f\x62\x9b\xf6\x51\x55\x74\x98\x78\x3b\x7b\x99\xda\x6b\x41\x7f\x33\xe8\xd9\xf9\x11\xce\x5d\xab\x49\xec\x70\xe0\xef\x77\xe4\x76\x8b\xfe\xcd\xff\x18\xfe\x43\xd8\x73\xd8\xae\x77\x06\x3b\x0e\xe2\x2f\xc0\xec\x7c\x81\xfc\x23\xc1\xf9\x1a\xbd\xba\xbd\x89\xde\x02\xb3\x19\x23\x7e\x81\xd9\x8c\xd3\xcf\x02\xb3\xa9\x33\x0f\xb7\x27\x02\x7b\x92\xef\x3f\xb3\xcf\x29\xce\xea\x98\xa2\x3f\x9b\xdb\xdf\x2a\xeb\x25\x7c\x6d\x76\xd3\xd4\xb7\xa4\x58\x47\xd8\xb7\x69\x66\xb8\xc8\x7e\xbd\x02\x37\x9f\x26\x3b\xee\xbb\x6d\xcc\x66\xf4\x1a\xbc\x45\x9c\xcd\xeb\x75\xf8\x22\x7d\x1e\xa7\x17\xd7\x79\xa3\x40\xe7\x4d\x78\x0b\x66\xf3\x3e\x01\x3f\x8e\x96\xcd\xfe\x2d\xb8\x6b\xbd\x5d\xa0\x35\x0f\xf7\x9a\xac\x86\x93\x41\x0d\xa6\x7d\x0a\xbf\x93\x81\xf6\x3b\x70\x9f\xc9\xe9\x60\x26\x17\x55\xd1\x69\x7c\x3c\xf7\xbb\xb9\xdc\xb6\x13\x67\xe1\x3e\x47\xdb\x8f\x73\x70\x8f\x7b\xaf\x20\xee\x3c\x3c\x8c\x7b\x1f\x6e\x71\xb6\x4f\x1f\x50\xcf\x05\x7c\x6c\xb7\x16\xe1\xae\x7d\xb1\x40\xfb\x12\x7c\x06\x36\xc7\xf7\xf3\x25\xfc\xbc\xff\x25\xf8\x9a\x86\xb2\x77\xf8\x23\xd8\x12\x7b\x54\x62\x3f\x3d\xd7\xe5\x82\x5c\x57\xe0\x61\xae\x8f\xe1\xde\x9b\xe5\xba\x0a\xf7\x5c\xcb\xb0\xab\xff\x93\x6b\xa5\x20\xd7\x35\x78\x98\xeb\x13\xb8\xcf\xc8\x72\x5d\x87\x7b\xae\x55\xd8\xf5\x5c\x2e\xfb\x6b\xc1\xbe\xbf\x6e\xd0\xfb\x32\xbe\x5e\xc3\xa7\xb9\x1a\x6c\xff\x6f\xc2\xd7\xc8\x67\xfb\xbf\x0e\xf7\xdd\xbb\x95\xdb\xff\xdb\x05\x3a\x9f\xc1\xd7\x60\xd6\xcb\xe7\xf0\x5b\x68\x59\x2f\x77\xe0\xae\xf5\x45\x81\xd6\x97\x70\xaf\xc9\x6a\xb8\x9b\xdb\xff\xaf\xf0\xbb\x1b\x68\xdf\x83\xfb\x9c\xee\xc3\xee\xe5\xe6\xf4\x87\xe2\x6c\x3f\xbe\x66\x56\xf7\xd1\xb5\x1d\x7d\x10\xec\xa8\xbd\x33\x0f\xf0\xf3\x77\xea\x1b\xce\xc3\xbd\xfb\x16\xee\x3d\x6d\xe4\x7a\xb2\xf7\xea\x3b\xb8\xc7\xd9\xfb\xf1\x10\xee\x71\xed\x82\xb8\x0e\x3c\x8c\xfb\x1e\xee\x71\x8f\x72\x71\x76\xaf\x5d\xf8\x26\x71\x76\xaf\x3f\xc0\x7d\xa6\x8f\x73\xf7\xda\x2b\xb8\xd7\x2d\xf8\x26\xcc\xee\x75\x1b\xfe\x18\x2d\x9b\x41\x02\x77\xad\x27\x05\x5a\x3f\xc2\xbd\x26\xab\xa1\x1f\xd4\x60\xda\x3b\xf8\xf5\x03\xed\x14\x6e\xf7\x66\xf6\x53\x66\x90\x32\x03\xbb\x97\xdd\xe0\x5e\xec\xde\x76\xf1\x33\x0d\xfb\x3f\xf6\x27\xce\xd7\xd1\xd8\x83\x09\xff\x3a\xcc\xcf\x7f\xe6\xbc\xc4\xf9\x04\x6c\x9d\xdd\xf9\x85\xf3\x32\xe7\x0d\xd8\x3f\x2a\xe9\xbc\x4a\xfa\x77\x00\xa7\x42\xac\x85\x2c\x0c\x00\x00")
 
 func shadersCubeVertSpvBytes() ([]byte, error) {
 	return bindataRead(
@@ -169,7 +263,207 @@ func shadersCubeVertSpv() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "shaders/cube.vert.spv", size: 1256, mode: os.FileMode(420), modTime: time.Unix(1494028127, 0)}
+	info := bindataFileInfo{name: "shaders/cube.vert.spv", size: 3116, mode: os.FileMode(420), modTime: time.Unix(1792397736, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesComp = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x56\x5f\x6f\xea\xb8\x13\x7d\xcf\xa7\x38\xd2\xef\x25\xd0\x34\xa4\x2d\xb4\xb7\x3f\x6e\x2a\xad\xee\xfe\xd1\x7d\xab\x56\xda\x7d\xa9\x7a\x2b\x13\x1b\xf0\xae\x63\x23\xdb\x40\xe8\x5e\xbe\xfb\xca\x4e\x42\xfe\x10\x7a\x37\xbc\xc0\x78\xe6\xcc\x99\xc9\x99\x31\x93\x71\x80\x31\x9e\x89\xb6\x3c\x13\x0c\x86\xe7\x5b\x41\x2c\x57\xf2\xff\xe0\xd2\xb2\x95\x26\x96\x19\x08\xbe\x63\xd8\x54\x4e\x26\x02\x59\x31\x03\xbb\x66\x39\x88\xa4\xd0\xcc\x6c\xc8\x5e\x1a\x87\x44\x19\xa1\x30\x42\x59\x83\xfd\x9a\x0b\xe6\xbc\xa0\xf6\x92\xcb\x15\x58\xce\xad\x65\x1a\xc6\x72\x21\xb0\x26\x06\x3e\x0e\x8b\x2d\x5d\x31\x0b\xbb\xe6\x06\x4b\x4d\x72\x16\x07\x18\x4f\x82\xff\xed\x98\x36\x5c\x49\x4c\x67\x49\x10\x08\x72\x50\x5b\x8b\x50\xa8\x8c\x88\x37\xc3\xdf\xd9\x5b\x81\x14\xb7\xb3\xfb\x11\xb8\x9c\x07\x81\xb1\x7a\x9b\xd9\xa6\x94\x7f\x02\x54\xcf\x8e\x65\x53\x6c\x94\xe1\xae\xae\x39\x26\x13\x14\x87\xf7\x08\x7b\xa4\x58\x70\x21\x16\x8a\x68\x0a\x87\xd8\x8d\xd8\x31\xa1\x32\x6e\x0f\xdd\x08\xcd\x72\xc2\x7d\x39\x82\x2f\x19\xb8\x84\x61\x99\x92\xd4\x74\x83\x33\x25\x94\x9e\x07\xc7\x86\xd8\x2f\x55\xf5\x17\x79\xa1\x93\xa7\xce\x0e\xb3\xd1\x8c\xd0\x4b\xd4\xba\x41\x8e\x92\xe5\xf9\x65\x5a\x2b\x4d\x76\xae\xa4\xae\xd5\x58\xa2\xed\x97\x92\x71\xe7\x80\x49\x3a\x64\x76\xbd\x9a\x57\xbd\x72\xe9\x91\x96\x10\xbe\x89\x11\x0e\x48\xc1\x64\xaf\xa5\x5b\x2e\x2d\x96\x5c\x1b\xdb\x80\x79\x5b\xa6\xb6\xb2\x65\x73\x26\x2f\x8b\x9e\xdb\x86\xd0\xb2\x9d\xb5\x10\x8c\xa5\xd3\xbb\x24\xc2\x82\x4b\xea\x5e\x47\x8a\x64\x84\xc5\x76\xb9\x64\xfa\x24\x02\xd3\x52\xc1\x49\x18\x27\x1d\xbf\xbc\xfe\x08\xf1\xe6\x84\xf8\xab\xd3\x65\x0b\x2d\x27\x76\x8a\x1d\x67\xfb\x67\xad\xfe\x6a\xa8\xfa\xae\x65\x24\xff\x9d\xaf\xd6\xf6\xdc\xfc\xc7\xa6\xb1\x2d\x85\x22\x16\xb4\xe5\x55\x5a\xdc\xfb\x6b\x6c\xbe\x76\xc3\x18\xed\x99\xaa\x59\xfa\xd2\x6d\x5e\xad\xb1\xea\xb4\xac\xb0\x9c\xa9\x79\x10\xf8\xc0\x35\x31\xeb\xd0\x7f\x2b\x46\x41\x53\x4f\x81\x6f\x29\x0a\x3c\x3d\xe1\xe6\xbe\xc1\x2b\x30\x4e\x91\x14\x0f\x4b\xb6\xb8\x9b\xdd\xd2\xed\x7c\xc8\x7f\x76\xe6\xff\x69\x7a\x9f\x91\xfb\x4f\x8b\xed\xfc\x07\xf8\x9a\xd9\xad\x96\x28\xe6\xc1\x31\x08\xca\xf2\x35\x91\x34\xe4\xd2\xcd\xba\x67\x69\x2c\xb1\xac\xcd\xd4\x1b\x90\xba\xfd\xb1\x0e\xfd\x8f\xd1\x19\xa0\x87\xaa\x0e\x31\xc1\xf4\xf6\x71\xfa\x78\xff\x70\xfb\x38\x8b\x13\x9f\x6a\xa7\x38\x85\x9b\xe3\xb0\x8d\xec\xf3\x71\xa4\x58\x89\xb7\xdf\x84\x5a\x10\xf1\x55\xee\x54\xe6\xd7\xe1\xd7\x9f\xe3\xa2\x49\xe3\x3d\x19\x52\x24\x8d\x6d\xa9\x34\xc2\x39\x18\x3e\x57\x4b\xac\xf3\x8a\xc0\xae\xae\x46\x2d\xfd\xd4\x1f\xbe\x44\xc8\xfb\x21\xe6\x85\xbd\xc6\x7e\x56\x70\x35\x70\xe2\x27\x66\x08\xac\x7e\x16\x9a\x91\xbf\xe7\x41\xff\xec\x18\x9c\x7f\x73\xf9\x19\xd2\xb4\x9b\xc6\xcb\x0a\xdf\xbf\xe3\x03\x6a\x43\x04\xca\xf6\x37\x99\x8f\xc1\xc0\xf8\x21\x6d\xae\x92\x17\xfe\xda\x78\xfb\xe1\x61\xf9\x73\xb5\x11\xd1\x23\xe5\x6b\x3f\xad\xcb\x7e\xd4\x9f\xf5\xbe\x1c\x8a\x3a\xed\xcb\x86\x8f\x2b\x7c\x73\x3a\x88\xf7\x78\x42\x12\x27\x43\x45\x75\xbc\xae\x6b\xf8\xf6\xe0\x0e\x78\x16\x87\x77\x5c\x0d\x51\xa9\x36\xb0\x77\x18\x7f\x88\x55\x97\x5a\x63\xf5\xd0\x87\x82\xbb\xef\xb5\x15\xb0\xc7\xe7\xf4\x52\x7d\xce\x95\x58\x95\xf3\xec\x27\x4a\xc3\x73\xc6\x7e\x1b\x47\xb8\xbe\x19\x79\x90\x21\x88\x01\xd2\xee\x3a\x4a\xe2\xd6\x7c\xf4\x9f\x4d\xec\xef\x48\x7f\xd5\x65\xd3\xd0\x91\xfb\xc0\xb9\x25\x18\xa7\x9f\xcb\x9e\x7d\x09\x9e\xb7\xa6\x7e\x9a\xf5\x72\xd2\x8c\x5b\xb4\xf8\x56\xae\x16\x3e\x40\x67\xc7\xb2\x3b\x50\x5e\x91\xbe\x0b\xfd\xb2\x2a\xd7\x4c\x84\x4b\x3f\x46\x18\xe3\x36\x4e\x70\x8d\x9b\xa1\x7e\xb8\xf6\x53\x65\x43\xca\x75\xe4\xb0\x47\x97\x95\x58\x3f\x25\x03\xa9\x74\x4e\x04\x7f\x67\x2e\x74\xf4\x5f\x2a\xee\x69\x2a\x6d\x0d\x9b\x33\x9c\x43\xf4\x24\x97\xb6\xe6\xcc\x1b\xae\x1c\x61\x8c\xdb\x38\x7b\x8c\xdb\xc5\x7f\x88\xb9\xef\x22\xba\xd0\x9c\x17\x61\x12\x3f\xcc\x22\xd7\xac\x6e\x1b\x07\xd7\x4a\x75\x67\x22\x75\xfe\xb8\x46\x26\x48\xbe\xe9\x2a\x7f\xd2\xc9\x11\xb9\xe6\x7a\xf4\x16\x60\x4f\xb7\x8e\xc4\xc0\x1c\xf0\x77\x16\x17\x11\x2e\x9c\x1c\x22\xd8\x0e\x64\x2d\xef\x0b\x68\xa7\xff\x5c\x43\x88\xf5\x1f\xaf\x1e\xe6\xd9\x14\x1c\x83\x7f\x07\x00\x62\xee\x51\x0e\xc1\x0b\x00\x00")
+
+func shadersParticlesCompBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesComp,
+		"shaders/particles.comp",
+	)
+}
+
+func shadersParticlesComp() (*asset, error) {
+	bytes, err := shadersParticlesCompBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.comp", size: 3009, mode: os.FileMode(420), modTime: time.Unix(1792393320, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesCompSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x9a\x07\x7c\x54\xd5\x12\xc6\x67\x76\x37\x85\x12\x84\x87\x20\xb6\x00\x62\x43\xaa\x0a\x48\x91\x66\x10\x14\x29\x51\x6c\xd8\x30\x26\x11\x16\xd2\x4c\x96\x2e\x8a\x02\x82\xd8\x7b\xc1\x5e\x10\xbb\x22\xf6\x8e\x58\x41\xb0\xf7\x2e\x2a\x76\xb1\xa1\x28\xe4\xfd\xe6\xce\x37\xec\xe4\x72\xf4\xb1\xbf\xe4\xfc\xcf\xcc\x77\x66\xce\xcc\xb9\x7b\xef\xcd\x4b\x26\x3a\xe4\x11\x31\xc9\x7f\xf9\x09\x8a\xfe\x6b\x41\x89\x88\x34\xa1\xdc\xe8\xe7\xb0\x11\x63\x46\x74\xad\xcb\x94\x75\xed\xd1\xb3\xbb\xcc\x37\xa3\xa4\xfc\x88\xe6\xb6\xa1\x5c\xca\x21\xa2\xfe\x44\x54\x59\x92\xae\x12\xbe\x1d\x11\x35\xa7\xdc\x88\xb5\x80\x21\xc3\x3e\x17\xf6\xf2\xaf\xa6\xba\x2e\x9d\x49\x57\x57\x91\xe3\x62\x33\xa5\xbc\xa2\xba\x34\x9d\x99\xae\x3c\x27\xe2\x12\x5a\x69\x75\x45\x75\x2d\x6c\xf3\xff\x43\x23\xff\x3f\x34\xf2\xa1\x31\xbe\xb6\x64\x8a\x48\x9b\xad\x64\x52\x97\x29\xa9\xcd\x14\xa9\xb8\xf1\x14\x11\x95\x57\x95\x81\x66\x35\x24\x96\xba\xf4\x8c\x72\xcf\x72\x89\xe8\xe4\x74\x6d\x5d\xc6\xb1\xbc\x28\xde\xc9\x55\x9e\xc9\xa7\xae\xa6\x64\xaa\x04\x9b\x4b\xa9\xc8\xae\x91\xe4\x50\x52\x16\xc5\xde\xd8\x72\x2a\xa9\xcd\xa4\x4b\x2b\xca\xeb\x90\x53\x01\xf8\x94\x74\xf9\xd4\xe2\xda\xea\x89\x96\x6b\x01\x72\x2d\x2d\xa9\x3c\x2c\x3d\x7e\x42\xc6\x72\x2d\x40\xae\xa5\x25\x95\x47\xd4\x60\xad\x02\xe4\x5a\x96\xc9\xda\x48\x8e\x99\x74\xe5\x96\x5c\x0a\x2c\xbf\xf2\xf2\x32\x65\x79\x54\x80\xfc\xca\x2b\xd3\x99\x4c\x79\x6d\x11\x52\xda\xb2\x7e\x5e\x76\x4e\xc2\xa5\x1c\x4a\x46\xf5\x1f\x9f\x2e\x8b\x7e\xdf\x41\xe2\x06\xdf\x89\x88\xa6\x45\xbf\xa7\xa8\x87\xec\x45\xa6\x24\x53\x8e\xb9\x81\xa2\x83\xdf\x8b\x64\x0f\x60\x37\x24\x66\x37\x94\x88\xca\xd2\xb5\x34\x8c\x52\xd4\x08\xb1\x49\x53\x0e\xa3\x24\x35\x46\x8e\x32\xd7\x14\x73\x35\x98\xb3\xfc\x0f\x42\x3f\xc9\xbf\x0e\xf8\x69\x8c\xc1\x9a\x3b\x96\x00\x6b\x07\x96\x1f\xf0\xcd\x0f\xf8\xe6\xff\x87\x6f\x12\xbe\xdd\x9d\x6f\x0a\xbe\x83\x1c\xcb\x01\x2b\x76\x2c\x17\xec\x44\xc7\xf2\xc0\xca\x1c\xcb\x07\x9b\xe0\x58\x23\xb0\x0a\xb0\xc6\x81\x3c\x0a\xb6\x62\xa9\x2d\x2c\x27\x66\x93\xe7\x72\xb5\x3e\xf4\x39\x58\x0f\xfa\x1c\xac\x06\x3e\x07\xeb\x43\x9f\x83\xf5\xa1\xcf\xc1\xfa\xd0\xe7\x60\xfd\xd7\x61\x4b\x9d\x53\xd4\x92\x88\x76\x41\x8c\x36\x6e\xef\xc6\xad\x62\xf3\xad\x30\xcf\x98\x97\xde\x6d\x42\x14\xf5\x6a\x4b\x4a\x44\x39\xb4\xa6\x64\x74\xc5\x93\x3a\x6e\x47\x29\x4a\xa1\x8e\xf2\xb3\x10\x7d\x92\x72\x9f\x56\x94\x8a\x7a\xaf\x1d\xd6\x91\x71\x1e\xc6\xb2\x4e\x21\x35\xa5\xfc\x98\x8f\xff\xe4\xe2\x93\x87\x9f\x3b\x53\x32\xea\x75\xd9\x93\x42\xf4\xb9\x8c\xdb\x50\x8a\x9a\x38\x3f\xb1\x6b\x8a\xfa\x17\x52\xe3\xa8\x76\x7e\x3e\x89\x8f\xe9\x8b\x6d\x3b\x4a\x51\x33\xb7\x96\xe4\xb7\x0d\x7e\x17\xdb\x6d\x29\x41\xcd\xc1\x5b\x80\x25\xe1\xf7\x3f\xd4\xb8\x31\xc6\xdb\x62\x5c\x80\x71\x6b\xe4\x2b\x7a\xed\x29\x45\x6d\xa0\x2b\x6b\x77\xc2\x1e\x15\xa2\x8f\x6c\x2c\xb5\xec\xd2\xf3\xfb\xd3\x6d\xbc\x3b\xfc\x6d\xdc\x89\x88\xce\x59\x5c\x31\x57\xf4\xf6\x41\x2c\xcd\x30\x2f\x71\xf5\x95\x0d\xa7\xd9\xa3\xdb\x53\x92\xf6\x47\x3c\x12\xcb\x20\xe4\xd8\x1c\xe3\x03\x30\xce\xc1\xf8\x40\x8c\x5b\xb8\xb5\x86\xa1\x7e\x32\x7f\x10\x72\xf1\xb1\x17\x63\x2c\xf3\x87\x62\x2d\x3f\x7f\x0c\x34\x65\xfe\x58\xcc\xdb\x9e\x8f\xc3\x38\xdf\xd9\xcf\xc2\x1a\x1d\x29\x19\xed\xc9\x15\xb0\xbd\x06\xb6\x8d\x30\xbe\x1e\x63\x8b\xfd\x2e\x8c\x53\x18\x3f\x83\x75\x53\x4e\x7b\x85\xab\xdb\xf3\x98\x4f\x62\x5e\x7e\xae\x74\xb9\xbe\x0d\x3d\x9b\x17\xff\x9f\xc0\x6c\xdc\x93\xb5\xcf\x64\xbd\x5e\xac\x73\x79\x6e\x7e\x7f\x96\x33\xaa\x63\xe1\xfd\x99\xa8\xbe\xbe\xbe\xde\xc6\x03\xe5\x20\x38\xfb\x23\x59\xcf\xbc\xc5\x53\xa2\xf3\x83\x6c\x5c\x1a\x8d\x67\x0f\xb4\xf1\x75\xd1\x78\xd0\x40\xf3\xdf\xc0\xda\xe3\xfd\xd0\x97\x2d\x11\x6f\x3f\xf4\x65\x2b\x37\x6e\x8d\x7b\x14\x91\xe8\x45\x39\x91\xff\xf6\xc8\xbf\x0d\x11\xed\x47\xc9\x88\xc9\x77\xd7\x5f\x94\xa0\x1d\xe1\xd7\x0c\xd7\x05\x89\x7f\x00\xbe\xcf\xc4\xa6\x3f\x62\xd8\x19\xf3\xcb\xa1\xd9\x16\xac\xd0\xd9\xb4\x83\xcd\x0b\xb0\x69\x0f\xd6\xd6\x69\xb6\x77\x9a\xbb\x40\x73\x2e\xec\x77\x05\xeb\xe0\xec\x77\x75\xf6\xbb\xc5\x62\xd8\x03\x6c\x77\x67\xb3\x67\x2c\x86\x8e\x60\x7b\x38\xcd\x8e\x4e\x73\xaf\x58\x0c\x9d\xc1\x3a\x39\xfb\xce\xce\xbe\x4b\x2c\x86\xae\x60\x7e\x1f\xba\xc5\x62\xe8\x0e\xd6\xd5\x69\x76\x77\x9a\x7b\xc3\x7e\x33\x25\xa2\xdf\x7b\x13\x47\xb5\x93\x5e\xd8\x17\xb5\xdb\x07\xb5\x93\x3a\xf5\x40\xed\x7a\x3a\x8d\x5e\xe0\x7d\xb0\xe6\x7e\xa8\x7b\x2f\xac\xd9\x03\xcc\xec\x7b\xc3\xbe\x06\x3d\xd7\x07\x6c\x01\xd6\xed\x07\xd6\x17\x71\xf5\x73\x71\x25\x70\x4f\x2c\xff\xdb\x1f\xb1\x0c\x70\x7d\x34\x10\x7d\xd4\x0f\xd7\xa6\xc1\x6e\x2c\xd7\xa6\x22\x37\x96\x7c\x86\xb8\xb1\x5c\xab\x86\x62\x3c\x00\xf7\x4e\x72\xad\x1a\x4c\x39\xd1\x99\x3b\x18\xfd\x3d\xcc\xe5\x32\x1c\xfc\x6f\x4a\xd0\x21\x88\x47\x7e\xfe\x49\x29\x1a\x45\x44\x23\x11\xab\xcc\x8f\xc0\xfc\x08\xe7\x3f\x1a\x31\xcb\x1a\x72\xad\x3b\x0c\x67\xaa\xd8\xd9\x8c\x01\x37\x9b\xc3\x03\x36\x47\x80\xdf\x47\x39\xd1\x35\xee\x48\x68\x0b\xdf\x40\x49\x1a\x8b\x38\x36\x52\x2a\x9a\x3b\x8a\x88\x8e\x46\x3c\x47\x21\xbe\xb1\x88\xef\x68\x8c\x47\x61\x3c\x16\x6b\xcb\xb5\xf6\x38\xac\x7d\x8c\x5b\xfb\x78\x97\x83\xd8\x9c\xe0\x6c\xc4\x6f\x1c\xee\x4b\x4e\x80\x6d\x7f\xdc\xa3\x97\x80\x9b\xdf\x49\x01\xbf\x52\xf0\xe3\xc1\x0e\xc5\xfd\x4c\x69\x2c\xff\x72\x70\xd3\x3a\x39\xa0\x35\x1e\xdc\x6b\x4d\x00\xf7\x5a\x69\x70\xd3\x9a\xe8\xb4\xcc\x66\x52\x2c\xe7\x8a\x40\xce\x95\xe0\x93\xe0\x27\x39\x57\x81\x9b\x5f\x75\xc0\xaf\x06\x7c\x12\x98\xc4\x79\x0a\xb8\x8f\xa1\x16\xdc\xb4\xea\x02\x5a\x19\x70\xaf\x35\x19\xdc\x6b\x4d\x01\x9f\x8d\x33\x3c\x15\xfb\x30\xc5\xf5\xd4\x34\xf4\xfb\x54\xf4\xd4\x4c\xd7\x53\xd3\x88\x48\x1e\x2c\x67\xa0\x67\xa6\xc7\x7a\x68\x06\xc6\x33\x31\x9e\x89\xf1\x48\x8c\x47\xba\x58\x4e\xc5\xde\x5a\x2c\xa7\x81\xcd\x72\xe7\xf2\xb4\xd8\x99\x1b\xe5\xfc\x4f\x77\xb5\x91\x7c\x67\x63\x5f\x7c\x8d\xcf\x00\x37\x9b\x33\x03\x36\x73\xc0\xef\x40\xfe\x73\xa1\x2d\x7c\x09\xa5\x22\x36\x0f\x5c\xf6\x63\x81\xdb\x0f\xe1\x67\x11\xd1\x7c\xc4\x77\x16\xd6\x92\x3a\x9d\x8d\xb5\xfc\xfe\x2f\x74\x31\x8b\xcd\x39\x81\x5a\x9e\x0b\xbe\x10\x7e\xd2\x4f\xe7\x81\x9b\xdf\xf9\x01\xbf\x0b\xc0\x17\x82\x49\xbe\x17\x82\xfb\x7c\x2f\x02\x37\xad\x8b\x03\x5a\x97\x80\x7b\xad\x4b\xc1\xbd\xd6\x65\xe0\xd6\x3b\x97\xa3\x77\x2e\x43\x0d\x07\x83\x49\x0d\x17\xa0\x86\xf3\xdd\xdc\x15\xb1\xb9\x05\xd0\x16\xad\x2b\x71\x5d\x97\x3d\x5f\xe4\xf6\x5c\xf8\x55\x44\x74\x35\x7c\xe4\xf7\x4d\xc4\xd1\xef\x57\x43\x6f\x11\xf4\x16\x21\x7e\xb9\x07\xbc\x16\xf7\x35\x76\xad\x17\x76\x5d\x8c\xc9\xbd\xe1\x0d\xe0\xc3\x11\x8b\xdc\x57\xdd\x08\x6e\x7e\x37\x05\xfc\x6e\x06\xf7\x7e\xb7\x80\x0f\xc0\x73\xf9\x2d\xb0\x97\x7d\xbf\xd5\xed\xbb\xed\xe7\xe2\x58\x7f\xdc\x16\xa8\xcd\x12\xf0\xc5\xf0\x93\xfe\xb8\x1d\xdc\xfc\xee\x08\xf8\xdd\x09\xbe\x18\x4c\xee\x7b\xef\x06\xb7\xef\x3a\xb9\x07\xbc\x07\xdc\xb4\xee\x0d\x68\xdd\x07\xee\xb5\xee\x07\xf7\x5a\x4b\xc1\x4d\xeb\x81\x40\xce\xcb\x62\x39\x3f\x18\x58\xef\x21\xf0\x65\xf0\x93\x9c\x1f\x06\x37\xbf\x47\x02\x7e\x8f\x82\x2f\x03\x93\x38\x1f\x03\x9f\x05\x2d\x89\xf3\x71\x70\xd3\x7a\x22\xa0\xf5\x24\xb8\xd7\x7a\x0a\xdc\x6b\x3d\x0d\x2e\x36\xf2\x2c\xf1\x2c\xee\x47\xbc\xcd\x72\x70\xb3\x79\xce\xd9\x08\x93\x67\x8c\x17\xc0\x57\xc0\x4f\xee\xd5\x5e\x04\x37\xbf\x97\x02\x7e\x2f\x83\x7b\xbf\x57\xc0\x1f\xc3\x39\x5d\x05\xb6\x12\xe7\x6b\x8d\x3b\x5f\x32\xf7\x2a\x11\xad\xc6\x19\x7a\x15\x67\x48\xd6\x7b\x2d\x90\xcb\xeb\xe0\x66\xf3\x46\x20\xa6\x37\xc1\x7d\x4c\x6f\x81\x8b\x9f\x3c\x33\xbd\x83\x3d\xf7\x36\xef\x82\x9b\xcd\x7b\x01\x9b\xf7\xc1\x6d\xfd\x0f\x02\xeb\x7f\x08\xee\xfd\x3e\x02\x9f\x83\xfb\xd1\x8f\xc1\xde\x77\x5a\x9f\x04\xb4\x3e\x05\x5f\x81\x73\xfd\x29\x7c\xcd\xe7\xb3\xc0\x1e\x7d\x0e\x6e\x36\x5f\x04\x6c\xbe\x04\x37\x9b\xb5\x01\x9b\xaf\xc0\x47\x53\x3e\xb5\x20\xa2\xaf\xc1\xbe\x42\xfd\x18\xcf\x69\xd6\xc7\xdf\x04\xce\xdb\xba\xd8\x79\xfb\x36\xd0\xeb\xdf\x81\xaf\x83\x9f\x9c\xb7\xef\xc1\xcd\xef\x87\x80\xdf\x8f\xe0\xeb\xc0\xe4\x8c\xfc\x0c\xfe\x13\xb4\xa4\xff\x7f\x01\x37\xad\xf5\x01\xad\x5f\xc1\xbd\xd6\x6f\xe0\x5e\xeb\x77\x70\xdb\x93\x3f\xc0\x7e\x0f\xec\x89\xf4\xd0\x9f\x81\x1e\xda\x00\x6e\x36\x7f\x05\x6c\xfe\x06\x3f\x9f\x72\xa2\xbd\xdf\x88\xb5\x84\x9f\x01\xf6\x0f\x6a\xb2\x11\x5a\x52\xc7\x7f\x03\x75\xdc\x04\x6e\x36\x9b\x03\x7d\x56\x0f\xee\x63\x90\x17\x1c\xf5\xce\x8f\x79\x6b\xbf\x84\xfc\xbd\x80\x1b\xfa\x25\xe5\x1d\x03\xcb\xfd\x6f\x4e\xb4\x7e\x8a\x89\xfe\x01\x37\xad\x1c\xa7\x25\x7d\x2d\xe3\x94\x9b\xcf\xc5\xbc\xbf\xbe\xe7\xb1\x72\xb3\xc9\x0f\xd8\x34\x92\x77\x1d\xce\xa6\x71\xc0\xa6\x09\x13\x35\xe6\x6c\x0d\x9b\x32\x51\x13\x7c\xe2\x35\x94\x75\x0a\x78\xeb\x3d\x6d\xc6\xca\xcd\x66\x9b\x80\x4d\x73\x56\x6e\xeb\xb4\x60\xa2\xe6\xf8\xc4\xd7\x91\x3e\xf8\x1f\x6f\xdd\x07\x2d\x59\xb9\xd9\x6c\x1b\xb0\x69\xc5\xca\xad\x57\x5a\xb3\xae\xd5\x8a\xb3\xbd\xb2\x1d\x6b\x9e\xad\x5d\xcc\x6d\x02\x7b\xb3\x3d\x2b\x37\x9b\x1d\x9c\x8d\xd5\x7c\x47\x56\xee\x63\xd8\x89\x95\x9b\xdf\xce\x01\xbf\x42\x56\xee\xfd\xda\x32\x51\xa1\xeb\x95\x76\xac\xb1\xb6\x75\x5a\xed\x9d\x96\xf4\x8a\x8c\xc5\x4e\xee\xbd\xd6\xe0\xde\x6b\x35\x35\x1c\xaf\xc1\xba\xe2\xbf\x4b\xa0\x36\x1d\x58\xb9\xd9\xec\xea\x6c\x2c\xde\xdd\x58\xb9\x8f\x77\x77\x56\x6e\x7e\x7b\x04\xfc\xf6\x64\xe5\xde\xaf\x23\x2b\x7f\x02\xdf\x89\x7b\xb1\x32\xfb\x4e\xec\x82\x9e\x90\x67\x69\x99\xeb\xc4\x44\x9d\x59\x73\xe9\x84\xf5\xe4\xda\xd5\x95\xb7\xbe\xc6\x76\xe3\x86\xd7\xd8\xee\xbc\xf5\xf5\x6d\x6f\x56\xde\x8d\xb3\xd7\xd8\x7d\x98\x68\x6f\xa7\xbd\x6f\xc0\xaf\x07\x2b\xef\x06\x3b\x79\x7f\xb8\x1f\x2b\xef\x09\x2d\x79\xb7\xd1\x1b\xdc\xb4\xfa\x04\xb4\xfa\xb2\x72\xaf\xd5\x8f\x89\xfa\x42\xeb\x3b\xca\x8b\xde\xab\x0c\x00\x97\xeb\x8c\xbc\x9b\x94\xf7\x91\x0f\x60\xdf\x06\xb1\xce\xcb\x3b\x49\xd9\xb7\x22\xb7\x6f\x32\x37\x98\x89\x0e\xc0\xbe\x0d\xc6\xbe\x49\x9d\x86\x04\x7a\xfd\x40\x56\x6e\x36\x43\x9d\x8d\xd5\x72\x18\x2b\xf7\xb5\x3c\x88\x95\x9b\xdf\xc1\x01\xbf\xe1\xac\xdc\xbe\xb7\x87\xa3\xd6\xe6\x73\x08\x7c\xfc\x77\xcb\x08\x56\x5e\x4c\x79\x51\x7c\x23\xe1\xe3\x3f\xe6\x3f\xca\xf9\x8b\xbe\x8c\x47\x22\x26\x79\x66\x18\xcd\x0d\x9f\x19\x84\x15\xc7\x98\x3c\x7f\x1c\x2a\x67\x8f\x1b\x3e\x47\x1c\xc6\xca\x6d\x3c\x06\x6b\x99\xce\xe1\x01\x9d\x23\x58\xf9\x70\xc4\x23\xe3\x31\x9c\x7d\x56\x3a\x00\xe7\xb5\x08\xb5\x29\x42\x6d\xe4\x59\xef\x28\xf4\xca\x91\x58\x53\x9e\x4d\x8e\x66\xe5\x66\x33\x36\x60\x73\x0c\x2b\xb7\x77\x84\xc7\xb2\xbe\x23\x1c\xee\xde\x55\x1e\xc7\x6a\x27\x73\x12\xd7\x10\xb0\x3e\xb8\x0f\x3b\x5e\xfa\x1b\xef\xec\x8c\x9d\x10\x60\xe3\x1c\x2b\xa6\xdc\xe8\xfb\xf7\x44\x56\x7f\xb1\x1f\xe7\xae\xbf\x27\xb1\xce\x95\x70\xd6\xb6\x8c\xf5\xbd\xb8\x7d\xe6\xc0\xb6\x9c\xd5\xbe\x0c\xf1\x0d\x05\xeb\x8f\xbf\xe1\x9c\x2c\xfd\x87\xda\xc8\x78\x3c\xc6\x97\x22\xae\x09\xac\x36\xe3\x39\x7b\xbf\x9d\x66\xa2\x09\xe8\x1d\x39\x23\x15\xee\x8c\xc8\xdc\x44\x26\x9a\x84\x3a\x4c\x74\x6b\x55\x42\xbb\x29\x62\xae\xc2\xff\x17\xe0\x40\xcc\x59\x7c\x55\xa8\x65\x05\x6a\x39\x29\x36\xae\x40\xdd\xa4\x4f\xab\x03\xe7\xae\x86\x95\xdb\xf7\xe2\x29\xac\xcf\x6e\x4b\x03\xf7\x50\xa2\x51\x1b\xd0\xa8\x63\xe5\x66\x93\x09\x9c\xc1\xc9\xac\xdc\x9f\xdd\x29\x4c\x34\xd9\x9d\xdd\xa9\x01\xbf\x69\xac\xdc\xfb\x4d\x67\xa2\x69\xee\x7b\x6a\x06\x6b\xdc\xd3\x9d\xd6\x4c\xa7\x25\x7b\x25\xe3\x19\xae\xd7\x4e\x75\x3d\x64\x3e\xb3\x02\xdf\x4d\xa7\xc9\xb5\xcf\xed\xcf\xe9\xac\xcf\x79\x4f\xc7\xf6\xc7\xea\x36\x1b\x75\xb3\x6b\xef\x19\x81\x6b\xef\x99\x72\x2f\xc0\x0d\x9f\xa1\xe7\x30\xd1\x99\x9c\xcd\x5d\xee\x2f\xe6\xb2\x72\x9f\xfb\x3c\x56\x6e\xfd\x7d\x16\xeb\x9a\xf3\x1c\x9b\xcf\xca\x4f\x75\xf7\x1c\x0b\x58\x63\x9f\xef\xf6\xe8\xec\x40\xbe\x0b\x59\xb9\xd9\x9c\xe3\x6c\xac\x26\xe7\xb2\x72\x1f\xd7\x79\xac\xdc\xfc\xce\x0f\xf8\x5d\x20\x31\xc6\xfc\x2e\x64\xa2\x0b\x5c\x2d\x2f\x62\x8d\xf5\x42\xa7\x75\xb1\xd3\x92\x5a\xca\xf8\x22\x37\x7f\x49\x20\x8f\x4b\x59\xb9\xd9\x5c\x16\x88\xe7\x72\x56\xee\xe3\xb9\x82\x89\x2e\x87\x9f\xd4\xef\xca\x40\xfd\xae\x62\xe5\xfe\x7d\xc0\xd5\x4c\x74\x15\xbe\x27\xad\x7e\x8b\x58\xb9\xd7\xbf\x86\x95\x5b\x1f\x5e\xeb\xfa\x50\xfe\xba\x2d\xec\x7a\x9c\xf5\xae\xf8\x7b\x9d\x5c\xa3\xc4\x6e\x1e\x7c\x6e\x60\xd5\xb9\xde\xe5\x77\x63\x20\xbf\x9b\x58\xb9\x7d\xef\xc9\xf8\x06\x5c\x1f\xba\xe0\xfa\xd0\x39\x36\xee\xe2\x34\x6f\x0e\xec\xeb\x2d\xac\xdc\x6c\x6e\x0d\xac\xbb\x98\x95\xfb\xbc\x6f\x93\x7e\x77\x7e\x4b\x02\x7e\xb7\x33\xd1\x92\x98\xdf\x1d\x4c\x74\x3b\xfc\xa4\x1e\x77\x06\xea\x71\x17\x2b\xf7\xf5\xb8\x9b\x89\xee\x8a\xd5\xe3\x1e\x56\xee\xf5\xef\x65\xe5\xf6\xb7\xa9\xfb\x58\xd7\xbc\x97\xb3\xf5\xb8\x1f\xf5\xe8\x84\xf9\x95\xf8\xbb\xaa\xbd\x3f\x58\x8a\x1a\xdd\xef\xf2\x7b\x20\x70\x9d\x5c\x26\xf7\x4e\xce\xe6\x41\x67\x63\x7b\xf0\x10\x13\x3d\x18\xdb\x83\x87\x59\xb9\xed\xc1\x23\x6e\x0f\xec\xfb\xf7\xd1\xd8\x7d\xe7\x63\x81\x7d\x7a\x5c\xbe\x97\x58\x6d\xed\xbe\xf3\x09\x26\x7a\xdc\xdd\x77\x3e\x19\xf0\x7b\x8a\x95\x3f\x0a\x3b\xd9\xdf\xa7\x99\xe8\x29\xce\xde\x03\xc8\xd9\x7d\x46\xae\x8d\x4e\xeb\xd9\x80\xd6\x72\x26\x7a\x36\xa6\xf5\x1c\x13\x2d\x87\x96\xd5\x6a\x05\x2b\xb7\xfd\x93\x7d\x78\x5e\xf6\x05\x36\x12\xeb\x0b\x01\xfd\x17\x59\xb9\xd7\x7f\x89\x89\x5e\x8c\xe9\xbf\xcc\xca\xbd\xfe\x2b\xac\xdc\xf4\x57\x06\xf6\x79\x55\x6c\x9f\x5f\x0d\xc4\xb0\x9a\x95\xaf\x72\xfb\xbc\x86\x89\x56\xbb\x7d\x7e\x2d\xe0\xf7\x3a\x2b\x5f\x05\x3b\x89\xfd\x0d\x26\x7a\x3d\xb6\xcf\x6f\xb2\x72\xd3\x7a\x2b\xa0\xf5\x36\x2b\xf7\x5a\xef\x30\xd1\xdb\xb1\x7d\x78\x97\x95\xdb\x19\x97\x7d\x78\x8f\x95\x9b\xfe\xfb\x01\xfd\x0f\x58\xb9\xd7\xff\x90\x89\x3e\x88\xe9\x7f\xc4\xca\xbd\xfe\xc7\xac\xdc\xce\xd7\x27\xee\x7a\x27\x35\x90\xf9\xa5\xd0\x90\xb3\xf4\x69\xe0\x9c\x7c\xc6\xca\xed\xda\x26\xe3\x4f\x9c\xcf\xe7\x81\x7b\xfa\x2f\x58\xb9\xe5\xf5\x65\xa0\xbe\x6b\x63\xf5\xfd\x2a\x90\xfb\xd7\xac\x7c\xad\xab\xef\x37\x4c\xf4\xb5\xab\xef\xba\x80\xdf\xb7\xac\x7c\x2d\xec\x64\xcf\xbe\x63\xa2\x6f\xdd\x79\x97\xfa\x7e\xcf\xca\x4d\xeb\x87\x80\xd6\x8f\xac\xdc\x6b\xfd\xc4\x44\x3f\xc6\xb4\x7e\x66\xe5\xa6\xf5\x4b\x20\xe7\xf5\xb1\x9c\x7f\x0d\xac\xf7\x1b\x2b\x5f\xef\x72\xfe\x9d\x89\x7e\x73\x39\xff\x11\xf0\xfb\x93\x95\xaf\x87\x9d\xc4\xf9\x17\x2b\xdf\x00\x2d\x89\xf3\x6f\xf9\x3e\x72\x5a\x1b\x03\x5a\xff\x30\xd1\xc6\x98\xd6\xbf\xf2\x3e\x2b\xa6\xb5\x89\x95\xdb\xb3\xdb\x66\xf4\x93\xff\x48\xef\xc9\x5c\xbd\xeb\x3d\xd9\x2b\xf1\xdd\x0c\x7d\xe9\x23\x4a\x64\xfb\x48\xfa\x4c\xc6\xf5\x98\x97\xe7\x2f\x4e\x34\x7c\xfe\x12\x96\x88\x31\x79\xb6\x4b\x26\x94\xfb\x67\xbb\x54\x42\xb9\x8d\x73\xb0\x96\xe9\xe4\x06\x74\xf2\x12\xca\xed\xd9\x4e\xc6\xe2\xb7\x89\x98\x7a\x13\xd3\xff\x07\x00\x83\xf2\x34\xbe\x98\x2c\x00\x00")
+
+func shadersParticlesCompSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesCompSpv,
+		"shaders/particles.comp.spv",
+	)
+}
+
+func shadersParticlesCompSpv() (*asset, error) {
+	bytes, err := shadersParticlesCompSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.comp.spv", size: 11416, mode: os.FileMode(420), modTime: time.Unix(1792397626, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8f\x41\x6a\xc3\x30\x10\x45\xf7\x73\x8a\x0f\xdd\x58\x22\x75\xe4\x92\xae\x8c\xbb\x29\xf4\x02\x3d\x81\xaa\x91\xc3\x80\xa2\x29\xb2\x6c\x28\x25\x77\x2f\xa9\x93\x78\x93\xbf\x91\xf8\xbc\x79\xcc\xec\x2d\xc1\xe2\x53\xc7\x8a\xa2\x73\x66\x4c\xdf\x45\x6a\xdc\xe1\x14\x7d\xae\x18\xb5\xc0\x33\x4b\x95\x25\xe2\x2b\xc5\xcc\x92\x8f\x2d\xc1\xee\xe9\x69\x89\x65\x12\xcd\x38\xbc\x3a\xa2\xe4\x7f\x74\xae\x68\x92\x06\x5f\x2f\xed\x00\x67\x20\x19\x4b\x0c\x07\x04\x4d\x5a\xfa\x47\x50\x77\x83\x5e\x10\xb4\xe4\xf8\x98\x72\x06\x97\xea\xdf\x35\x7f\x14\x7f\x7c\x5f\x85\xb4\xa8\x30\x4e\x5e\x72\x63\xe8\x97\x70\xcd\x98\xd4\x57\x30\x06\xb0\xd6\x66\xf5\xee\xae\x7e\xd3\xdf\x31\x19\xd1\x30\xde\xd0\xb5\xce\x60\x9b\xbe\x85\x65\x0a\xbe\xf0\xc6\x9f\xef\xbf\x6d\x07\x0c\xeb\x71\xb0\xeb\xdb\x7a\x58\x34\x5d\xeb\xf0\x0c\x36\x3d\x9d\xe9\x6f\x00\x63\xc8\xa2\x00\x62\x01\x00\x00")
+
+func shadersParticlesFragBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesFrag,
+		"shaders/particles.frag",
+	)
+}
+
+func shadersParticlesFrag() (*asset, error) {
+	bytes, err := shadersParticlesFragBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.frag", size: 354, mode: os.FileMode(420), modTime: time.Unix(1792393320, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x92\xcd\x4a\x23\x51\x10\x85\xbf\xee\xbe\x37\xbf\x93\xbf\xc9\xdf\x64\x66\x12\x13\x5c\x0a\xc1\x85\x8a\x10\xa2\xb8\xca\x26\x3b\x9f\x40\xd4\x85\xa0\x09\x44\x1f\x40\x70\xef\xca\xa7\xf1\xc5\x8c\xa2\x20\x55\x39\x42\x9a\x86\xea\x73\xea\x54\xd5\xa9\xbe\x37\x4b\x77\xf3\x90\x60\xcf\x88\xcd\xd3\x20\x75\xa6\x4c\xce\xe3\x6c\x7e\x3e\x1f\xdf\x3f\x5c\x8d\x0f\x0e\xf7\x4d\x50\x25\xb3\xe0\xb9\x1a\x05\x02\x50\x01\xee\x2e\x6e\x16\xc6\xe7\x81\xa2\xd7\x43\x9d\xcc\x73\xc6\x45\x82\xc7\xcb\xe5\xed\x72\x25\x5c\x74\xbc\x5a\x5c\xaf\x60\xa6\xfc\x40\x3e\x66\xca\x0f\x34\xcb\x70\x59\xd8\xde\x26\x29\x29\xd0\x21\x73\x47\x43\xa0\x4b\x70\x3f\x86\x83\x70\x14\x36\xed\x90\x40\x4e\xfd\x82\x70\x41\x38\x0a\x97\xb6\xea\x47\x64\xfc\x52\xed\x1e\xc1\xf9\xdf\x36\x9c\xc7\xd3\x16\x29\x4d\xa0\x4d\xe0\x8f\xe6\x23\x9d\xe1\x9e\xfa\x58\xcf\xbf\x9a\x61\xf5\x13\x79\xc8\x8b\x9b\xc8\x43\x71\x0b\x97\xf4\xff\x4c\x7f\x44\xf4\xf9\x15\xf5\x37\x3f\x6b\x52\xaa\xc0\x54\xfb\xd5\x54\xff\x83\xeb\xc2\x2f\x44\xf7\xd0\x90\xc6\xf8\x57\xa2\xfb\x6e\x89\xb7\x7d\xde\xc8\xe8\xaa\xff\x07\xc1\x73\x6d\xff\xb7\x9b\x59\xf6\xfd\x49\xe2\xdf\x1d\xe0\x9d\xd4\xf5\x6b\xc5\x33\xa2\xef\xf8\x4f\x7b\xf5\xe4\xc5\x66\xff\x17\x3f\xd5\xd9\xf4\xa5\x79\xf6\x1b\xb0\x39\xcf\xbe\x74\x4f\xf2\xbb\x23\x5f\x8d\x2d\xdd\x50\x67\x6f\xb9\x13\x32\xbf\x5f\xc6\x7d\x91\x70\x4c\xc2\xf7\x00\xdf\x3d\xb6\x7d\xcc\x02\x00\x00")
+
+func shadersParticlesFragSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesFragSpv,
+		"shaders/particles.frag.spv",
+	)
+}
+
+func shadersParticlesFragSpv() (*asset, error) {
+	bytes, err := shadersParticlesFragSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.frag.spv", size: 716, mode: os.FileMode(420), modTime: time.Unix(1792397626, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x53\xc1\x8e\xda\x30\x10\xbd\xfb\x2b\x9e\xd4\x0b\x64\x21\xc0\x2e\xed\x25\xca\xb1\x95\xf6\x86\x2a\x6d\x2f\x08\x21\xe3\x4c\xa8\x2b\x63\xa7\xb6\x09\x09\x15\xff\x5e\x39\x21\x21\x74\x91\xba\x39\x44\xf6\x9b\x99\xa7\x37\x6f\xc6\xb3\x88\x21\xc2\xd7\xaa\xe0\x3a\x73\xa0\x92\x6c\x8d\x82\x5b\x2f\x85\x22\x48\xed\x3c\xd7\x22\x1c\xbc\x01\x87\xe0\x07\xb2\x7c\x9a\x73\x21\xf5\x1e\xbf\x8f\x3c\x8b\x19\xa2\x19\xfb\x54\x92\x75\xd2\x68\x2c\x3f\xcf\x19\x73\xde\x1e\x85\xc7\xaa\x63\xf9\xc3\x70\xfd\x4a\x12\x4b\x14\xc6\x49\x2f\x8d\x4e\x30\x9b\xa1\xaa\xcf\x13\x9c\x90\x62\x27\x95\xda\x19\x6e\x33\x38\x79\xa6\xfb\x8a\x92\x94\x11\xd2\xd7\xf7\x15\x96\x0e\x5c\xea\x20\x44\xc9\x3c\x48\x84\x23\x61\x74\xe6\xee\x8b\x85\x51\xc6\x26\xec\x92\x30\xa6\x78\x6d\x8e\x1e\x23\xe7\xb3\xe5\xcb\x7c\x82\x9d\xd4\x59\xa8\x4f\x31\x1f\xc3\x12\xcf\x8c\x56\x35\x76\xc7\x3c\x27\xdb\xcb\x77\x03\xfd\x7d\x4b\x9d\x43\x6e\xbd\xf9\x1f\xf5\xe2\x3d\xf5\x37\xcb\x0f\x43\x5b\x0e\xdc\x2f\x51\x4a\x3a\xad\xac\xf9\x95\xf4\x70\xe3\x96\xe0\x87\xef\x72\xff\xd3\xbf\x87\xdf\x8a\x84\x5d\x90\x07\xae\x81\x00\x65\x04\x0f\xee\xb6\x4d\x05\x68\xe8\xc2\x83\xac\x45\x9f\xf5\x0c\x61\xac\x26\x9b\x30\x26\x8c\x76\x77\x98\x5b\x7f\xd9\x20\x6d\x90\xf5\x66\x34\xd4\xf2\x3c\x9a\x2e\xe2\xf9\x04\xe1\x3f\x9e\xb4\xc8\x63\xa0\xb9\x7f\xa8\x74\x70\x9f\xf6\x00\x1b\x27\x8c\x05\xa9\x7b\xb5\x5d\x91\xfd\x41\xd6\x53\x35\x70\xb1\xe9\x33\xc4\xba\xfd\x6a\x06\x53\x1a\x99\x21\x2c\xca\x68\xcc\x1e\x0d\x12\x69\xbf\xee\x6e\xbd\x57\xdb\xd7\xeb\xca\xbf\xea\x8c\xaa\xcd\xcd\xf5\xd6\x1a\xa4\xbd\x1f\x7b\xb5\x6d\x25\xfc\x9b\x59\x92\x78\x81\xc9\x73\x47\x1e\x69\x3b\x9f\xb8\x9b\x62\x5c\xd5\x67\x44\x57\x8e\xb8\xc2\xd3\x2d\xfe\x56\xdc\x07\xeb\x1b\xe3\xa0\xa9\x9e\xb1\x5b\x17\x44\xc1\xb7\xe5\xa8\x88\xbb\x77\xd5\xd0\x3c\x75\x0a\x22\x0c\x22\xa7\xd6\xc9\x1b\x73\xf3\x38\x82\x05\xb1\x30\xca\xd8\x84\x5d\xd8\xdf\x01\x00\x99\x80\xf3\x2d\x11\x04\x00\x00")
+
+func shadersParticlesVertBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesVert,
+		"shaders/particles.vert",
+	)
+}
+
+func shadersParticlesVert() (*asset, error) {
+	bytes, err := shadersParticlesVertBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.vert", size: 1041, mode: os.FileMode(420), modTime: time.Unix(1792393320, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x96\xfb\x5b\x54\x65\x10\xc7\x67\xf7\x9c\xb3\xcb\xb2\x86\x10\xca\x45\x04\x41\xd2\x4c\x0a\xad\xc4\x28\x54\xd4\x32\x34\x29\x41\xd3\xae\x56\xdb\xb2\xe1\x1a\xec\xd2\xee\x0a\x68\x17\x2f\xa5\x99\x15\xa5\xdd\xfe\x9c\xfe\xad\x7e\xe9\x79\x7a\xe6\xdd\xcf\xac\x6f\xc7\x23\x0f\x3e\xcb\x7c\x66\xe6\xfb\xce\x3b\x33\xe7\xac\x41\x7a\x34\x2b\x92\x12\xfd\xf9\x4b\x9a\x3f\x5d\x92\x76\x24\x2f\x19\xf7\x39\x33\x7b\x6e\x76\xbc\xde\x58\x18\x3f\x30\xb1\x5f\x03\x3a\x24\xd0\x0f\xe7\xdb\x2c\xed\xfa\xa7\x0c\x8a\xc8\x72\xa1\x5c\xd1\xbf\xb7\x8a\x48\x8f\x88\xf4\x89\x48\xbf\x88\x0c\x88\x48\x46\x32\x12\xa1\xbf\x52\xad\x97\x1b\xe5\x6a\x45\x3c\xae\x5a\xab\xa5\xa5\x6a\xb1\xdc\xb8\xda\xe4\x91\xe3\x69\x11\x29\x56\x97\xaa\x35\x62\xb3\xa6\x51\xa8\x35\xca\xc5\xa5\x52\x1d\x9e\x83\xaf\x96\x4b\x6b\x73\xb5\xea\x65\xd3\xce\xa1\x5d\x2c\x2c\x9f\x2d\x2f\x5e\x6a\x98\x76\xce\xb4\x0b\xcb\xe7\x57\x88\xcd\x27\xd6\x17\x49\x9e\xfa\x1e\xd6\xd1\x64\x2e\xbf\x5a\xab\x94\x6a\xe2\xaa\xd5\x7b\xaf\x96\x6a\x8d\xd2\xfa\xa9\xca\x42\x69\x5d\x22\xc9\xb8\x3e\x94\x2b\xf5\x46\xa1\x52\x2c\x35\x69\x33\xb6\x2f\x76\x4e\x24\xa1\xf4\x7b\x67\xa8\x3d\xf0\x3f\xfd\x50\x86\x5b\x76\x5d\x22\x09\x64\xa7\x88\x54\xaf\x34\x64\x46\x42\xc9\xb8\x5a\x45\x74\x40\x33\x12\xb8\x3e\xe9\x94\xd4\xd7\x81\xaf\x4d\x44\x4e\xd2\x57\xfd\x1d\xe5\xd3\x58\x0a\xd6\xe9\xb1\x34\x6c\x18\x96\x4d\xc8\xcd\x3d\xc2\xc2\x16\x8b\x62\x31\x59\x4f\xdf\x66\xa3\x79\x47\x3d\x66\x67\xce\xc1\xf2\x8f\xe8\x3f\x9c\x89\x5f\x6f\x3e\x56\xaf\xde\x5d\x7d\x3b\xc9\x33\x7b\xa4\x65\x07\xd2\xc9\x9e\x6a\xcf\xba\xe8\x99\x6a\x75\xc5\xce\xd4\xdc\xee\x98\x56\x37\x5a\x29\xb4\xba\x5b\x5a\xa1\xdb\x05\xad\x67\x2f\x76\x0f\xf6\x18\x76\x1f\xb6\x69\xe9\xec\x87\x3c\x7b\x00\x5b\xb5\xbb\x25\xed\xee\xd5\x23\x81\xab\x4f\xef\xd6\x2b\xa1\x84\xd4\xab\x9f\x43\xcc\x2b\xf4\xfe\x0d\x4a\xe0\xe6\xae\x33\x18\x62\x27\xd4\xee\x93\x50\xda\xbc\xb8\x21\xfa\xee\x33\xd5\x6f\x47\x3f\x8d\x7e\xde\xd3\x57\xdf\x56\x09\x65\x13\xf5\xe8\xef\x18\xf6\x13\x9c\xb3\x9d\xdd\x6b\x87\x0d\x4b\x28\x9b\xd1\xcb\x52\x93\xf6\x39\x87\xef\x49\x7c\x5d\xd8\x5b\xb8\xff\x26\xec\x5e\xef\xbe\x6a\x6f\xc3\x56\xfd\x11\x09\x64\x3b\xf9\xea\xdb\xc1\x19\x1d\xd8\x23\xd8\x79\xea\x0c\x5a\xb3\xbd\xf1\xb7\xd9\xbb\x9a\xf6\xb4\xdd\xe3\x59\xee\xa5\xf9\xcf\xa1\x9d\xc1\x7e\x1e\x3b\xf2\xee\x3d\x09\x53\xff\xcb\x9c\xd7\x8e\xfd\x2a\xbe\x9c\x17\x7f\x82\xfb\xa9\xff\x75\xfc\xd6\xfb\x59\xee\x16\xe0\x9f\x47\xcf\xec\x32\xb6\xf5\x62\x99\xfc\x36\x4f\xff\x96\x17\xff\x2d\x7e\xcd\x9f\x62\x0e\x9d\xb0\x29\x7a\xdf\xed\xd9\x5b\x78\x8f\xa7\x3c\xbb\xc7\xb3\x7b\xd9\x75\xd3\xeb\xe5\x5d\x6f\xf6\x36\xde\xfb\x6a\x1f\x94\xc8\x9d\x3d\x48\x3f\x75\x4e\xff\x48\x5a\x86\x88\xdd\xc1\x0e\x65\xb1\x47\x78\xce\xd4\x9e\x93\xc8\x7d\xc3\x3c\xc5\xbc\x46\x3d\xb6\x9b\x99\xf9\xec\x69\xd8\x2e\x8f\xed\x49\xc8\x7d\x26\x21\x6e\x2f\x71\xcd\xdc\x9c\xdb\xdd\x31\xce\xde\x8d\xf6\x1e\x72\x35\xf6\x88\x04\x6e\xf7\x35\xe6\x98\x44\x6e\x47\xc6\xe9\xab\xee\xce\x61\xe6\xb0\x8f\xde\x59\xcc\x7e\x2f\x46\x99\xee\xd2\x0b\xf0\x7d\xe4\xe9\x5e\xbd\x08\xb7\xbc\x03\x09\xda\x13\x9e\xb6\xea\x1c\x24\x6e\xc2\xd3\x79\x09\xae\x3a\xba\x97\xaf\xd0\xdf\x49\x62\xb4\x47\x53\x70\xd3\x3d\xc4\xfc\x2d\xe7\x30\x33\x3a\xe4\xe5\x1c\x81\x5b\xce\x74\x2c\xe7\x28\x39\xd3\x5e\xce\x31\xb8\xc5\x1c\xf7\x6a\xd1\x7e\x1e\x27\x46\xfd\xfa\xcc\xbc\xc6\x5e\x5a\xaf\xf4\x39\x99\x81\x9f\x40\x57\x9f\x81\x93\x70\xcb\x3b\x95\x90\xf7\x06\xdc\xcf\x3b\x0d\x3f\x23\x6d\xee\x99\x7b\x13\x76\x9a\x5d\x4d\xf1\x4c\x58\xbd\x6f\x25\xf4\xee\x0c\xdc\x62\xe6\xbc\x18\x65\xfa\xec\x9e\x85\xdb\xec\xf4\xb9\x38\x07\xb7\xbc\xb7\x13\xf2\xce\xc3\xfd\xbc\x0b\xf0\x0d\x89\x5c\xcd\xef\x50\xf7\x05\xf2\xf4\xfe\xef\x26\xdc\xff\x3d\xb8\xd5\xae\xf7\x7f\x1f\x6e\x79\x1f\x24\xe4\x7d\x08\xf7\xf3\x2e\xc2\xad\x6f\x1f\xc1\x2e\x3e\xa6\x6f\x1f\x27\xf4\xed\x13\xb8\xc5\x14\x12\xee\xff\x29\xdc\x66\xa6\xf7\x2f\xc2\x2d\x6f\x21\x21\xaf\x04\xf7\xf3\x3e\x83\x5b\xdf\x16\xa9\x5b\xf9\x4d\xd8\x25\xfa\xb9\x88\x96\xbe\x67\x2f\xa3\x6f\x33\xd0\xfb\x7f\x0e\xb7\xbe\x2d\xc5\xfa\xa6\xef\xe3\x0a\xdc\xf2\xf4\xdd\x5c\x85\x5b\xde\x4a\x42\xde\x17\x70\x3f\xaf\x06\xb7\x77\x41\x3d\xe1\x5d\xd0\x88\xbd\x0b\xae\x10\xd7\x80\xe9\xb3\xb3\x0a\xb7\x3c\xbd\xcb\x1a\xdc\x66\xb9\x0e\x5b\x4b\x98\xa5\x9e\x7d\x35\xe1\xec\x6b\xb1\xb3\xbf\x24\xee\x1a\x4c\xcf\xfe\x0a\xee\x9f\xfd\x35\xdc\xb4\xbf\x49\xd0\xbe\x1e\xd3\xbe\x41\xdc\x75\x98\x6a\xdf\x84\x5b\x1f\xf5\xfb\xee\x3b\xf8\x2d\xb4\x74\x07\x6e\xc3\x6d\x07\xee\x30\xf3\xdb\xde\x0e\x7c\x4f\x0f\xee\xf0\xdd\xa0\x75\xde\x85\xeb\x77\xc3\x7d\xd8\x0f\xcc\xe5\x2e\x73\xd1\x5d\xb9\xe7\xed\x8a\xbe\xcf\xee\x11\x67\xfe\x1f\xf1\xdb\x5e\xaa\xf6\x4f\x70\xeb\xc1\xcf\x09\x3d\xd8\x88\xf5\xe0\x17\xe2\x36\x60\xda\x83\x5f\xe1\xfe\x73\x7a\x1f\x6e\xe7\x3f\xf0\xce\xd7\xfa\x1e\x10\xa3\xf1\xfa\x7f\xa3\xdf\xf0\xcf\x73\xc7\xdf\x61\x42\x7c\x1f\xcc\xfc\x7f\xe0\x4f\xe1\xef\x87\xcd\xf3\x9d\xfa\x27\xfe\x34\xfe\x01\xd8\xbf\x92\x92\x49\x49\xc9\x7f\x03\x00\xf8\xc0\xf4\x12\x80\x0e\x00\x00")
+
+func shadersParticlesVertSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesVertSpv,
+		"shaders/particles.vert.spv",
+	)
+}
+
+func shadersParticlesVertSpv() (*asset, error) {
+	bytes, err := shadersParticlesVertSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.vert.spv", size: 3712, mode: os.FileMode(420), modTime: time.Unix(1792397626, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersPresentFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x8e\xc1\x4a\xc3\x50\x10\x45\xf7\xf3\x15\x17\xdc\x24\x41\x6c\x29\x75\x15\xba\xaa\xf8\x1f\x63\xde\x34\x1d\xc8\x9b\x09\x93\x97\xa8\x88\xff\x2e\x89\x62\x57\x9d\xe5\xe1\x70\xe6\xee\x1a\x42\x83\xb3\x8f\x2a\x13\xca\x55\x10\x62\x49\x42\x12\x2e\xc1\x59\xa0\x56\x7c\xe3\xd3\x3b\x8f\xdd\x95\xd5\xa0\x99\x7b\x79\x22\x34\x3b\x7a\x58\x24\x26\x75\xc3\xf1\x79\x4f\x34\xf0\xa7\xcf\x05\xd5\x9b\x5a\x52\xeb\x71\xc2\xbe\xc6\x6c\x7a\xf1\xc8\x98\x38\x8f\x83\xc4\xe1\xe5\x37\xdc\xde\xf4\xc1\x3b\x2e\x6b\x64\xf3\xd5\xb0\x48\x77\x40\x91\x8f\xce\x3d\x52\x7b\xc7\x5b\xd1\x22\xdd\x11\xf3\x6b\x70\x7f\xf6\xc1\xa3\x25\x5a\x5c\x13\x32\xab\x55\x35\x7d\x11\xfe\xee\xa6\xe0\xb4\x86\xcb\x1c\x52\x6d\x33\x1e\xff\xff\xd4\x2d\x7d\xd3\xcf\x00\xd9\x6a\x6d\x0a\x0e\x01\x00\x00")
+
+func shadersPresentFragBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersPresentFrag,
+		"shaders/present.frag",
+	)
+}
+
+func shadersPresentFrag() (*asset, error) {
+	bytes, err := shadersPresentFragBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/present.frag", size: 270, mode: os.FileMode(420), modTime: time.Unix(1792398159, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersPresentFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x91\xbf\x4b\x33\x41\x10\x86\x9f\xdb\xbd\xcd\x7d\xf7\x5d\xbc\x24\x46\x13\x7f\x80\x24\xda\x07\x0b\x15\x21\xc4\x36\x4d\x3a\x41\x6b\x31\x16\x16\x1a\xd0\x14\xfe\xe9\x36\x82\xcc\xf8\x1e\x1c\xd9\xe6\x78\x66\x67\x9e\x7d\x77\x2f\x86\x8b\x02\x32\x6c\x1d\xf3\xb7\x06\x04\xaf\x54\x74\xfc\xbb\x5c\xdd\xaf\x66\x9f\xdb\xf5\xec\xea\xfa\xd2\x1a\x6a\xa2\x7d\x7c\xaf\x47\x41\xee\x33\xf0\xf6\xf4\xfa\x6e\xf5\x3d\xaf\x43\x9f\xe8\xf5\x02\x48\x24\xaf\x6f\x5f\xbe\x9e\x37\x9b\x8f\xb5\xf5\x2d\xc9\x29\x81\x73\x9d\xdb\xf0\xb4\xc5\xd5\xce\x7e\xb5\xb3\x6f\xce\xb3\x16\xf7\x5a\x3c\x24\x10\x80\x11\xd1\x13\x4f\xfc\x8e\xa5\xe7\x8d\xca\xdf\x5e\x0d\x9f\x10\x48\xc0\x98\x9c\x8e\x7a\x83\xb8\x10\x9b\x63\x42\xce\x3f\xcd\x34\xfc\x5f\x9c\xc4\x5d\x79\x3b\xe2\x5a\xf3\xe6\x99\x12\xe9\xcb\x7d\x4a\x64\x2c\xcf\x5c\xde\x52\xae\xb9\xbc\x55\x8b\xbb\x7a\xe7\x4c\x5c\xeb\xcd\x2d\xdb\x0d\xc9\x9d\x03\xf5\xdb\x19\xdf\x04\xf6\x81\x05\xb9\x9f\x31\x94\xdf\xd8\xb2\x1e\xc8\xbf\xd0\x9d\x0f\xe5\x7f\x20\x79\xae\x23\xcd\x58\xdf\x23\xc9\xf3\x8f\x54\xb7\xde\x3b\xa2\xff\x73\xab\xfd\x90\x71\x4b\xc6\xef\x00\x1b\x87\x60\xd7\x5c\x02\x00\x00")
+
+func shadersPresentFragSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersPresentFragSpv,
+		"shaders/present.frag.spv",
+	)
+}
+
+func shadersPresentFragSpv() (*asset, error) {
+	bytes, err := shadersPresentFragSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/present.frag.spv", size: 604, mode: os.FileMode(420), modTime: time.Unix(1792398159, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersPresentVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\xcd\xb1\x6a\xf3\x30\x14\x05\xe0\xfd\x3e\xc5\x81\x1f\x82\x65\xfc\x3b\xb2\x71\x27\x25\x43\xc7\x6e\x9d\xba\x06\x55\xbe\x71\x04\xaa\x04\xb2\xa2\xb8\x94\xbc\x7b\x51\xdc\xa6\xa5\x1a\x34\x9c\x7b\xf8\xce\xb6\x26\xd4\x78\x44\x8a\x56\xfb\xc9\x31\x4c\xc8\x1c\xad\x9f\x90\x4e\x8c\xd9\x44\x66\xdf\x60\x8c\xfa\xe2\x71\xb1\xe9\x14\xce\x09\x99\x63\xe2\x05\xaf\xe7\xe3\x91\xe3\xdc\x12\xea\x2d\xfd\xcb\x1c\x67\x1b\x3c\x86\x07\x49\xe4\xf4\x7b\x29\x56\x2e\x18\x9d\x4a\xba\x87\x14\x28\x51\x66\xd3\x23\xf1\x62\x42\x88\xa3\x22\x2a\xd9\xe4\x0e\xcf\x1c\x5f\x56\xf5\x83\xf0\xf5\x32\x9b\xe1\x76\x0b\xb3\x2d\x88\xa2\xab\x22\xca\xc1\x8e\x78\xd3\xd6\x57\x82\x7e\xba\xdf\x22\xf6\xb7\x85\xaa\x9a\xdc\x61\x05\x9f\xfc\xc8\x0b\x76\x3b\x74\x02\x1b\xf4\x0d\xfe\x5c\x36\xe8\x85\xba\x3b\xbf\xe6\x56\x6a\xa8\xee\x74\x8d\xbe\x95\xf8\x8f\xae\x95\x0d\x64\xf9\xba\x56\x0a\x45\x57\xfa\x1c\x00\xda\x51\x2c\xce\x45\x01\x00\x00")
+
+func shadersPresentVertBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersPresentVert,
+		"shaders/present.vert",
+	)
+}
+
+func shadersPresentVert() (*asset, error) {
+	bytes, err := shadersPresentVertBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/present.vert", size: 325, mode: os.FileMode(420), modTime: time.Unix(1792398159, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersPresentVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x93\xcb\x4f\x13\x51\x18\xc5\xcf\xcc\xdc\xdb\x8a\x63\x29\x0f\x29\xbe\xa8\xb4\xf5\x09\x5a\x40\xc1\x98\x34\xf8\x58\xe9\x82\x05\xc4\xbf\xc0\x58\x16\x5d\xd8\x31\x52\x13\x16\x2e\x4c\xdc\xfb\x57\x99\xf8\x2f\xb9\x31\x31\xdf\xc7\xef\x9a\x89\xd0\x69\x32\x73\xce\x77\xce\xf7\xba\x33\x45\x3e\x68\x4a\x99\xec\xb7\xa7\xb3\xdf\xa2\x72\x67\x4a\x35\xfc\xfe\xe6\xe0\xdd\xc1\xf0\x64\x36\x1e\xee\xee\x6d\x9b\x60\x5e\x85\xdd\x3c\xd6\xd6\x25\x7b\x54\x5b\xd2\xc7\xf7\x93\xa9\x3d\xcf\xb9\x57\x6a\x49\x6a\xf8\x25\xbf\x3e\x55\x27\x93\xd9\xa4\x9a\xaa\xc6\x5b\xe5\xd9\xf1\xe9\x87\xaa\xfa\x3c\x36\x3e\x2a\xb8\x7f\x32\x1d\x1f\x9f\x3a\x8e\x2a\xff\xf3\x46\x45\xb5\xce\xf9\x0a\x2d\x49\xaa\xbe\xcc\xf4\x56\xf1\x5f\xcd\x01\xf7\xc4\x65\x70\x0b\x36\x17\xb5\x2c\xff\x06\xb8\x04\x0b\x6c\x75\xba\xe0\x65\xe5\xca\x25\x75\x54\xf8\x06\xd6\x25\xad\x2a\x28\x48\x8e\x03\x38\x82\x4d\xdb\x55\xf0\x5e\x2c\x66\xfc\x8a\x82\x9a\x78\xed\xbf\xae\xe0\x1b\xb4\xbe\x9a\xe0\xcb\xb5\x7c\x86\xaf\x80\xcd\xdf\x53\xa1\x79\x72\x5b\x6c\x11\x5f\x83\xdc\x57\xc9\x6d\xf9\x36\xa9\xd5\x01\x9b\x7e\x15\x7d\x24\x6e\xfa\x9b\xe4\x4b\xfa\x7e\xad\xb7\x01\xfa\x80\xbe\x60\x57\x92\x5e\x25\xfc\xc8\xe1\xb7\x97\x09\x6f\xe1\x1f\x31\xdb\x1c\xf5\x47\xcc\x56\x32\xcf\x88\xd9\x5a\xe0\x67\x8a\xde\x47\x1b\xbf\xcd\xf9\x5b\xb9\x16\xd0\xda\xac\x4b\xf4\xb3\x4f\xaf\xcb\xe4\xff\xca\x2c\x2b\x70\xaf\x15\x7d\xd6\x6b\x78\x3a\x78\x6c\xee\xeb\xf0\x3f\x15\xdd\x73\x03\x9f\x69\x7e\xc1\xdd\x82\xb7\xdd\x54\xcc\xb5\x06\x9f\x34\x5d\x7c\x75\xcd\x6d\xf8\x43\x7f\x73\xcf\xce\x62\x0d\x3e\xf5\xd4\xab\xf5\xf4\x42\x85\x7a\x9c\x99\xc5\x6d\xdf\x77\x88\xf7\xe9\xd9\x76\x7f\x17\x3e\xe5\xb8\x77\xc1\x5c\xf7\xe1\x93\xe6\xc1\x05\x9a\x87\xf0\x3f\xe8\x6f\x13\x6e\xa3\xd6\xf3\x63\xce\xd4\xce\xf5\x3b\xdc\x10\xad\xc5\x0e\xd5\xf0\x77\x73\x1b\x7e\x0b\x6d\xea\x7f\x87\xba\x7d\xe6\xdb\x41\xbb\xcf\xb7\xf0\x84\xf8\x91\x7f\xed\xd2\x53\x38\xa1\x2f\xe1\x8e\xa8\xbd\x4b\x3c\x23\xde\x82\xfb\xa3\x4c\xcf\x95\xe9\xef\x00\x77\xd2\x49\xb9\xc4\x04\x00\x00")
+
+func shadersPresentVertSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersPresentVertSpv,
+		"shaders/present.vert.spv",
+	)
+}
+
+func shadersPresentVertSpv() (*asset, error) {
+	bytes, err := shadersPresentVertSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/present.vert.spv", size: 1220, mode: os.FileMode(420), modTime: time.Unix(1792398159, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -267,10 +561,24 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"shaders/.DS_Store": shadersDs_store,
+	"shaders/canvas.frag": shadersCanvasFrag,
+	"shaders/canvas.frag.spv": shadersCanvasFragSpv,
+	"shaders/canvas.vert": shadersCanvasVert,
+	"shaders/canvas.vert.spv": shadersCanvasVertSpv,
 	"shaders/cube.frag": shadersCubeFrag,
 	"shaders/cube.frag.spv": shadersCubeFragSpv,
 	"shaders/cube.vert": shadersCubeVert,
 	"shaders/cube.vert.spv": shadersCubeVertSpv,
+	"shaders/particles.comp": shadersParticlesComp,
+	"shaders/particles.comp.spv": shadersParticlesCompSpv,
+	"shaders/particles.frag": shadersParticlesFrag,
+	"shaders/particles.frag.spv": shadersParticlesFragSpv,
+	"shaders/particles.vert": shadersParticlesVert,
+	"shaders/particles.vert.spv": shadersParticlesVertSpv,
+	"shaders/present.frag": shadersPresentFrag,
+	"shaders/present.frag.spv": shadersPresentFragSpv,
+	"shaders/present.vert": shadersPresentVert,
+	"shaders/present.vert.spv": shadersPresentVertSpv,
 	"textures/.DS_Store": texturesDs_store,
 	"textures/gopher.png": texturesGopherPng,
 }
@@ -317,10 +625,24 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"shaders": &bintree{nil, map[string]*bintree{
 		".DS_Store": &bintree{shadersDs_store, map[string]*bintree{}},
+		"canvas.frag": &bintree{shadersCanvasFrag, map[string]*bintree{}},
+		"canvas.frag.spv": &bintree{shadersCanvasFragSpv, map[string]*bintree{}},
+		"canvas.vert": &bintree{shadersCanvasVert, map[string]*bintree{}},
+		"canvas.vert.spv": &bintree{shadersCanvasVertSpv, map[string]*bintree{}},
 		"cube.frag": &bintree{shadersCubeFrag, map[string]*bintree{}},
 		"cube.frag.spv": &bintree{shadersCubeFragSpv, map[string]*bintree{}},
 		"cube.vert": &bintree{shadersCubeVert, map[string]*bintree{}},
 		"cube.vert.spv": &bintree{shadersCubeVertSpv, map[string]*bintree{}},
+		"particles.comp": &bintree{shadersParticlesComp, map[string]*bintree{}},
+		"particles.comp.spv": &bintree{shadersParticlesCompSpv, map[string]*bintree{}},
+		"particles.frag": &bintree{shadersParticlesFrag, map[string]*bintree{}},
+		"particles.frag.spv": &bintree{shadersParticlesFragSpv, map[string]*bintree{}},
+		"particles.vert": &bintree{shadersParticlesVert, map[string]*bintree{}},
+		"particles.vert.spv": &bintree{shadersParticlesVertSpv, map[string]*bintree{}},
+		"present.frag": &bintree{shadersPresentFrag, map[string]*bintree{}},
+		"present.frag.spv": &bintree{shadersPresentFragSpv, map[string]*bintree{}},
+		"present.vert": &bintree{shadersPresentVert, map[string]*bintree{}},
+		"present.vert.spv": &bintree{shadersPresentVertSpv, map[string]*bintree{}},
 	}},
 	"textures": &bintree{nil, map[string]*bintree{
 		".DS_Store": &bintree{texturesDs_store, map[string]*bintree{}},
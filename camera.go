@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math"
+)
+
+// Camera produces the view matrix for the frame from input. mouse is false
+// while the UI owns the cursor, in which case cameras ignore mouse input.
+type Camera interface {
+	update(in *Input, dt float32, mouse bool)
+	view() mat4
+}
+
+// OrbitCamera circles Target: dragging or the right stick rotates,
+// scrolling or the left stick zooms.
+type OrbitCamera struct {
+	Target   vec3
+	Yaw      float32
+	Pitch    float32
+	Distance float32
+}
+
+const (
+	cameraRotateSpeed = 0.005
+	// cameraStickSpeed is the turn rate in radians per second at full
+	// stick deflection.
+	cameraStickSpeed  = 2.5
+	cameraStickZoom   = 4
+	cameraMaxPitch    = 1.5
+	cameraMinDistance = 1
+	cameraMaxDistance = 50
+)
+
+func (c *OrbitCamera) update(in *Input, dt float32, mouse bool) {
+	if mouse && in.Down("camera.rotate") {
+		dx, dy := in.CursorDelta()
+		c.Yaw -= dx * cameraRotateSpeed
+		c.Pitch += dy * cameraRotateSpeed
+	}
+	if mouse {
+		c.Distance *= float32(math.Pow(0.9, float64(in.Scroll())))
+	}
+	c.Yaw -= in.Axis("look.left", "look.right") * cameraStickSpeed * dt
+	c.Pitch += in.Axis("look.up", "look.down") * cameraStickSpeed * dt
+	zoom := in.Axis("zoom.out", "zoom.in") * cameraStickZoom * dt
+	c.Distance *= float32(math.Pow(0.9, float64(zoom)))
+	c.Pitch = clamp(c.Pitch, -cameraMaxPitch, cameraMaxPitch)
+	c.Distance = clamp(c.Distance, cameraMinDistance, cameraMaxDistance)
+}
+
+func (c *OrbitCamera) eye() vec3 {
+	sy, cy := math.Sincos(float64(c.Yaw))
+	sp, cp := math.Sincos(float64(c.Pitch))
+	offset := vec3{float32(sy * cp), float32(sp), float32(cy * cp)}
+	return c.Target.add(offset.scale(c.Distance))
+}
+
+func (c *OrbitCamera) view() mat4 {
+	return lookAt(c.eye(), c.Target, vec3{0, 1, 0})
+}
+
+// FlyCamera moves freely: WASD/QE or the left stick and triggers
+// translate, holding the look button and moving the mouse or the right
+// stick turns.
+type FlyCamera struct {
+	Position vec3
+	Yaw      float32
+	Pitch    float32
+	// Speed is in units per second; move.fast multiplies it by four.
+	Speed float32
+}
+
+func (c *FlyCamera) forward() vec3 {
+	sy, cy := math.Sincos(float64(c.Yaw))
+	sp, cp := math.Sincos(float64(c.Pitch))
+	return vec3{float32(-sy * cp), float32(sp), float32(-cy * cp)}
+}
+
+func (c *FlyCamera) update(in *Input, dt float32, mouse bool) {
+	if mouse && in.Down("camera.look") {
+		dx, dy := in.CursorDelta()
+		c.Yaw -= dx * cameraRotateSpeed
+		c.Pitch -= dy * cameraRotateSpeed
+	}
+	c.Yaw -= in.Axis("look.left", "look.right") * cameraStickSpeed * dt
+	c.Pitch -= in.Axis("look.up", "look.down") * cameraStickSpeed * dt
+	c.Pitch = clamp(c.Pitch, -cameraMaxPitch, cameraMaxPitch)
+
+	forward := c.forward()
+	right := forward.cross(vec3{0, 1, 0}).normalize()
+	move := forward.scale(in.Axis("move.back", "move.forward")).
+		add(right.scale(in.Axis("move.left", "move.right"))).
+		add(vec3{0, in.Axis("move.down", "move.up"), 0})
+	speed := c.Speed
+	if in.Down("move.fast") {
+		speed *= 4
+	}
+	// Partial stick deflection moves slower; diagonals are not faster.
+	if move.dot(move) > 1 {
+		move = move.normalize()
+	}
+	c.Position = c.Position.add(move.scale(speed * dt))
+}
+
+func (c *FlyCamera) view() mat4 {
+	return lookAt(c.Position, c.Position.add(c.forward()), vec3{0, 1, 0})
+}
+
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./bindata"
+)
+
+const canvasVertexSize = 20
+
+// canvasVertex mirrors the vertex inputs of shaders/canvas.vert. Color is
+// packed as R8G8B8A8 with red in the lowest byte.
+type canvasVertex struct {
+	X, Y  float32
+	U, V  float32
+	Color uint32
+}
+
+func rgba(r, g, b, a float32) uint32 {
+	pack := func(c float32) uint32 {
+		if c < 0 {
+			c = 0
+		} else if c > 1 {
+			c = 1
+		}
+		return uint32(c*255 + 0.5)
+	}
+	return pack(r) | pack(g)<<8 | pack(b)<<16 | pack(a)<<24
+}
+
+// TextBatch collects screen-space quads in pixel coordinates, with the
+// origin in the top-left corner of the window.
+type TextBatch struct {
+	vertices []canvasVertex
+}
+
+func (b *TextBatch) Reset() {
+	b.vertices = b.vertices[:0]
+}
+
+func (b *TextBatch) quad(x0, y0, x1, y1, u0, v0, u1, v1 float32, color uint32) {
+	b.vertices = append(b.vertices,
+		canvasVertex{x0, y0, u0, v0, color},
+		canvasVertex{x1, y0, u1, v0, color},
+		canvasVertex{x1, y1, u1, v1, color},
+		canvasVertex{x0, y0, u0, v0, color},
+		canvasVertex{x1, y1, u1, v1, color},
+		canvasVertex{x0, y1, u0, v1, color},
+	)
+}
+
+// Rect adds a solid rectangle.
+func (b *TextBatch) Rect(x, y, w, h float32, color uint32) {
+	u0, v0, u1, v1 := glyphUV(glyphSolid)
+	// Sample the middle of the solid cell so filtering never reaches its
+	// neighbours.
+	uc, vc := (u0+u1)/2, (v0+v1)/2
+	b.quad(x, y, x+w, y+h, uc, vc, uc, vc, color)
+}
+
+// Text adds s starting at x, y with glyphs scaled by an integer factor and
+// returns the width of the widest line. Newlines start a new line.
+func (b *TextBatch) Text(x, y float32, scale int, color uint32, s string) float32 {
+	size := float32(glyphSize * scale)
+	cx, width := x, float32(0)
+	for _, r := range s {
+		if r == '\n' {
+			cx = x
+			y += size + float32(scale)
+			continue
+		}
+		if r != ' ' {
+			u0, v0, u1, v1 := glyphUV(r)
+			b.quad(cx, y, cx+size, y+size, u0, v0, u1, v1, color)
+		}
+		cx += size
+		if cx-x > width {
+			width = cx - x
+		}
+	}
+	return width
+}
+
+// textWidth returns the width in pixels of a single line of text.
+func textWidth(s string, scale int) float32 {
+	return float32(len([]rune(s)) * glyphSize * scale)
+}
+
+// Canvas draws TextBatch contents with its own pipeline. Every swapchain
+// image gets its own host-visible vertex buffer so a batch can be rewritten
+// while earlier frames are still in flight.
+type Canvas struct {
+	name        string
+	maxVertices int
+	width       uint32
+	height      uint32
+	vertices    []*Buffer
+	counts      []int
+
+	descLayout     vk.DescriptorSetLayout
+	pipelineLayout vk.PipelineLayout
+	pipeline       vk.Pipeline
+	descSet        vk.DescriptorSet
+}
+
+func (a *Application) newCanvas(name string, font *FontAtlas, maxQuads int) *Canvas {
+	dev := a.Context().Device()
+	imageCount := len(a.Context().SwapchainImageResources())
+	c := &Canvas{
+		name:        name,
+		maxVertices: maxQuads * 6,
+		width:       a.width,
+		height:      a.height,
+		counts:      make([]int, imageCount),
+	}
+	for i := 0; i < imageCount; i++ {
+		buf := a.newBuffer(c.maxVertices*canvasVertexSize, vk.BufferUsageVertexBufferBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		buf.Write(0, make([]byte, buf.size))
+		c.vertices = append(c.vertices, buf)
+	}
+
+	ret := vk.CreateDescriptorSetLayout(dev, &vk.DescriptorSetLayoutCreateInfo{
+		SType:        vk.StructureTypeDescriptorSetLayoutCreateInfo,
+		BindingCount: 1,
+		PBindings: []vk.DescriptorSetLayoutBinding{{
+			Binding:         0,
+			DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageFragmentBit),
+		}},
+	}, nil, &c.descLayout)
+	orPanic(as.NewError(ret))
+
+	ret = vk.CreatePipelineLayout(dev, &vk.PipelineLayoutCreateInfo{
+		SType:          vk.StructureTypePipelineLayoutCreateInfo,
+		SetLayoutCount: 1,
+		PSetLayouts: []vk.DescriptorSetLayout{
+			c.descLayout,
+		},
+		PushConstantRangeCount: 1,
+		PPushConstantRanges: []vk.PushConstantRange{{
+			StageFlags: vk.ShaderStageFlags(vk.ShaderStageVertexBit),
+			Size:       8,
+		}},
+	}, nil, &c.pipelineLayout)
+	orPanic(as.NewError(ret))
+
+	c.descSet = a.descriptors.MustAllocate(c.descLayout)
+
+	vk.UpdateDescriptorSets(dev, 1, []vk.WriteDescriptorSet{{
+		SType:           vk.StructureTypeWriteDescriptorSet,
+		DstSet:          c.descSet,
+		DstBinding:      0,
+		DescriptorCount: 1,
+		DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+		PImageInfo: []vk.DescriptorImageInfo{{
+			Sampler:     font.sampler,
+			ImageView:   font.view,
+			ImageLayout: vk.ImageLayoutShaderReadOnlyOptimal,
+		}},
+	}}, 0, nil)
+
+	c.preparePipeline(dev, a.pipelineCache, a.renderPass, a.multisampleState())
+
+	for i, buf := range c.vertices {
+		a.debug.NameBuffer(buf, fmt.Sprintf("%s vertices %d", name, i))
+	}
+	a.debug.Name(c.descLayout, name+" descriptor layout")
+	a.debug.Name(c.pipelineLayout, name+" pipeline layout")
+	a.debug.Name(c.pipeline, name+" pipeline")
+	a.debug.Name(c.descSet, name+" descriptor set")
+	return c
+}
+
+// resizeCanvas adapts c to a recreated swapchain: its new size, and a
+// pipeline for the new main render pass.
+func (a *Application) resizeCanvas(c *Canvas) {
+	dev := a.Context().Device()
+	c.width, c.height = a.width, a.height
+	vk.DestroyPipeline(dev, c.pipeline, nil)
+	c.preparePipeline(dev, a.pipelineCache, a.renderPass, a.multisampleState())
+	a.debug.Name(c.pipeline, c.name+" pipeline")
+}
+
+func (c *Canvas) preparePipeline(dev vk.Device, cache vk.PipelineCache, renderPass vk.RenderPass,
+	multisample *vk.PipelineMultisampleStateCreateInfo) {
+	vs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/canvas.vert.spv"))
+	orPanic(err)
+	fs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/canvas.frag.spv"))
+	orPanic(err)
+
+	pipeline := make([]vk.Pipeline, 1)
+	ret := vk.CreateGraphicsPipelines(dev, cache, 1, []vk.GraphicsPipelineCreateInfo{{
+		SType:      vk.StructureTypeGraphicsPipelineCreateInfo,
+		Layout:     c.pipelineLayout,
+		RenderPass: renderPass,
+
+		PDynamicState: &vk.PipelineDynamicStateCreateInfo{
+			SType:             vk.StructureTypePipelineDynamicStateCreateInfo,
+			DynamicStateCount: 2,
+			PDynamicStates: []vk.DynamicState{
+				vk.DynamicStateScissor,
+				vk.DynamicStateViewport,
+			},
+		},
+		PVertexInputState: &vk.PipelineVertexInputStateCreateInfo{
+			SType:                         vk.StructureTypePipelineVertexInputStateCreateInfo,
+			VertexBindingDescriptionCount: 1,
+			PVertexBindingDescriptions: []vk.VertexInputBindingDescription{{
+				Binding:   0,
+				Stride:    canvasVertexSize,
+				InputRate: vk.VertexInputRateVertex,
+			}},
+			VertexAttributeDescriptionCount: 3,
+			PVertexAttributeDescriptions: []vk.VertexInputAttributeDescription{{
+				Location: 0,
+				Format:   vk.FormatR32g32Sfloat,
+				Offset:   0,
+			}, {
+				Location: 1,
+				Format:   vk.FormatR32g32Sfloat,
+				Offset:   8,
+			}, {
+				Location: 2,
+				Format:   vk.FormatR8g8b8a8Unorm,
+				Offset:   16,
+			}},
+		},
+		PInputAssemblyState: &vk.PipelineInputAssemblyStateCreateInfo{
+			SType:    vk.StructureTypePipelineInputAssemblyStateCreateInfo,
+			Topology: vk.PrimitiveTopologyTriangleList,
+		},
+		PRasterizationState: &vk.PipelineRasterizationStateCreateInfo{
+			SType:       vk.StructureTypePipelineRasterizationStateCreateInfo,
+			PolygonMode: vk.PolygonModeFill,
+			CullMode:    vk.CullModeFlags(vk.CullModeNone),
+			FrontFace:   vk.FrontFaceCounterClockwise,
+			LineWidth:   1.0,
+		},
+		PColorBlendState: &vk.PipelineColorBlendStateCreateInfo{
+			SType:           vk.StructureTypePipelineColorBlendStateCreateInfo,
+			AttachmentCount: 1,
+			PAttachments: []vk.PipelineColorBlendAttachmentState{{
+				ColorWriteMask:      0xF,
+				BlendEnable:         vk.True,
+				SrcColorBlendFactor: vk.BlendFactorSrcAlpha,
+				DstColorBlendFactor: vk.BlendFactorOneMinusSrcAlpha,
+				ColorBlendOp:        vk.BlendOpAdd,
+				SrcAlphaBlendFactor: vk.BlendFactorOne,
+				DstAlphaBlendFactor: vk.BlendFactorOneMinusSrcAlpha,
+				AlphaBlendOp:        vk.BlendOpAdd,
+			}},
+		},
+		PMultisampleState: multisample,
+		PViewportState: &vk.PipelineViewportStateCreateInfo{
+			SType:         vk.StructureTypePipelineViewportStateCreateInfo,
+			ScissorCount:  1,
+			ViewportCount: 1,
+		},
+		// Drawn on top of everything in the scene.
+		PDepthStencilState: &vk.PipelineDepthStencilStateCreateInfo{
+			SType:            vk.StructureTypePipelineDepthStencilStateCreateInfo,
+			DepthTestEnable:  vk.False,
+			DepthWriteEnable: vk.False,
+			DepthCompareOp:   vk.CompareOpAlways,
+		},
+		StageCount: 2,
+		PStages: []vk.PipelineShaderStageCreateInfo{{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageVertexBit,
+			Module: vs,
+			PName:  "main\x00",
+		}, {
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageFragmentBit,
+			Module: fs,
+			PName:  "main\x00",
+		}},
+	}}, nil, pipeline)
+	orPanic(as.NewError(ret))
+	c.pipeline = pipeline[0]
+	vk.DestroyShaderModule(dev, vs, nil)
+	vk.DestroyShaderModule(dev, fs, nil)
+}
+
+// upload copies the batch into the vertex buffer of the given swapchain
+// image. Quads beyond the capacity of the canvas are dropped.
+func (c *Canvas) upload(dev vk.Device, imageIdx int, batch *TextBatch) {
+	vertices := batch.vertices
+	if len(vertices) > c.maxVertices {
+		vertices = vertices[:c.maxVertices]
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, vertices)
+	c.counts[imageIdx] = len(vertices)
+	if buf.Len() > 0 {
+		c.vertices[imageIdx].Write(0, buf.Bytes())
+	}
+}
+
+func (c *Canvas) draw(cmd vk.CommandBuffer, imageIdx int) {
+	if c.counts[imageIdx] == 0 {
+		return
+	}
+	screenSize := [2]float32{float32(c.width), float32(c.height)}
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, c.pipeline)
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointGraphics, c.pipelineLayout,
+		0, 1, []vk.DescriptorSet{c.descSet}, 0, nil)
+	vk.CmdPushConstants(cmd, c.pipelineLayout, vk.ShaderStageFlags(vk.ShaderStageVertexBit),
+		0, 8, unsafe.Pointer(&screenSize[0]))
+	vk.CmdBindVertexBuffers(cmd, 0, 1, []vk.Buffer{c.vertices[imageIdx].buffer}, []vk.DeviceSize{0})
+	vk.CmdDraw(cmd, uint32(c.counts[imageIdx]), 1, 0, 0)
+}
+
+func (c *Canvas) Destroy(dev vk.Device) {
+	vk.DestroyPipeline(dev, c.pipeline, nil)
+	vk.DestroyPipelineLayout(dev, c.pipelineLayout, nil)
+	vk.DestroyDescriptorSetLayout(dev, c.descLayout, nil)
+	for _, buf := range c.vertices {
+		buf.Destroy(dev)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unsafe"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./bindata"
+	"./memory"
+)
+
+// Buffer is a vk.Buffer bound to a range of the application's allocator.
+type Buffer struct {
+	buffer vk.Buffer
+	alloc  *memory.Allocation
+	size   vk.DeviceSize
+}
+
+// ComputePipeline bundles a compute shader with its own descriptor sets,
+// each laid out as one descriptor per binding in the order they were
+// declared. Several sets let per-swapchain-image command buffers bind
+// per-image buffers.
+type ComputePipeline struct {
+	bindings         []vk.DescriptorType
+	pushConstantSize uint32
+
+	descLayout     vk.DescriptorSetLayout
+	pipelineLayout vk.PipelineLayout
+	pipeline       vk.Pipeline
+	descSets       []vk.DescriptorSet
+}
+
+func (a *Application) newBuffer(size int, usage vk.BufferUsageFlagBits, props vk.MemoryPropertyFlagBits) *Buffer {
+	dev := a.Context().Device()
+	b := &Buffer{
+		size: vk.DeviceSize(size),
+	}
+	ret := vk.CreateBuffer(dev, &vk.BufferCreateInfo{
+		SType:       vk.StructureTypeBufferCreateInfo,
+		Size:        b.size,
+		Usage:       vk.BufferUsageFlags(usage),
+		SharingMode: vk.SharingModeExclusive,
+	}, nil, &b.buffer)
+	orPanic(as.NewError(ret))
+
+	var err error
+	b.alloc, err = a.allocator.AllocateBuffer(b.buffer, props)
+	orPanic(err)
+	return b
+}
+
+// Write copies data into a host-visible buffer at the given offset. The
+// memory stays mapped and the allocator only hands out coherent
+// host-visible memory, so this is a plain copy.
+func (b *Buffer) Write(offset int, data []byte) {
+	copy(b.mapped(offset, len(data)), data)
+}
+
+// Read copies len(data) bytes from a host-visible buffer at the given
+// offset into data.
+func (b *Buffer) Read(offset int, data []byte) {
+	copy(data, b.mapped(offset, len(data)))
+}
+
+func (b *Buffer) mapped(offset, size int) []byte {
+	bytes := b.alloc.Bytes()
+	if bytes == nil {
+		panic("buffer memory is not host visible")
+	}
+	return bytes[offset : offset+size]
+}
+
+func (b *Buffer) Destroy(dev vk.Device) {
+	vk.DestroyBuffer(dev, b.buffer, nil)
+	b.alloc.Free()
+}
+
+// newComputePipeline loads the SPIR-V asset named by shader from bindata and
+// creates a pipeline with one descriptor of each given type, bound in order
+// starting at binding 0, plus an optional push constant block. setCount
+// descriptor sets are allocated with that layout from the application's
+// persistent descriptors.
+func (a *Application) newComputePipeline(shader string,
+	bindings []vk.DescriptorType, pushConstantSize uint32, setCount int) *ComputePipeline {
+
+	dev := a.Context().Device()
+	cp := &ComputePipeline{
+		bindings:         bindings,
+		pushConstantSize: pushConstantSize,
+	}
+
+	layoutBindings := make([]vk.DescriptorSetLayoutBinding, 0, len(bindings))
+	for i, typ := range bindings {
+		layoutBindings = append(layoutBindings, vk.DescriptorSetLayoutBinding{
+			Binding:         uint32(i),
+			DescriptorType:  typ,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageComputeBit),
+		})
+	}
+	ret := vk.CreateDescriptorSetLayout(dev, &vk.DescriptorSetLayoutCreateInfo{
+		SType:        vk.StructureTypeDescriptorSetLayoutCreateInfo,
+		BindingCount: uint32(len(layoutBindings)),
+		PBindings:    layoutBindings,
+	}, nil, &cp.descLayout)
+	orPanic(as.NewError(ret))
+
+	layoutInfo := vk.PipelineLayoutCreateInfo{
+		SType:          vk.StructureTypePipelineLayoutCreateInfo,
+		SetLayoutCount: 1,
+		PSetLayouts: []vk.DescriptorSetLayout{
+			cp.descLayout,
+		},
+	}
+	if pushConstantSize > 0 {
+		layoutInfo.PushConstantRangeCount = 1
+		layoutInfo.PPushConstantRanges = []vk.PushConstantRange{{
+			StageFlags: vk.ShaderStageFlags(vk.ShaderStageComputeBit),
+			Size:       pushConstantSize,
+		}}
+	}
+	ret = vk.CreatePipelineLayout(dev, &layoutInfo, nil, &cp.pipelineLayout)
+	orPanic(as.NewError(ret))
+
+	cs, err := as.LoadShaderModule(dev, bindata.MustAsset(shader))
+	orPanic(err)
+
+	pipeline := make([]vk.Pipeline, 1)
+	ret = vk.CreateComputePipelines(dev, a.pipelineCache, 1, []vk.ComputePipelineCreateInfo{{
+		SType:  vk.StructureTypeComputePipelineCreateInfo,
+		Layout: cp.pipelineLayout,
+		Stage: vk.PipelineShaderStageCreateInfo{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageComputeBit,
+			Module: cs,
+			PName:  "main\x00",
+		},
+	}}, nil, pipeline)
+	orPanic(as.NewError(ret))
+	cp.pipeline = pipeline[0]
+	vk.DestroyShaderModule(dev, cs, nil)
+
+	cp.descSets = make([]vk.DescriptorSet, setCount)
+	for i := range cp.descSets {
+		cp.descSets[i] = a.descriptors.MustAllocate(cp.descLayout)
+	}
+
+	name := strings.TrimSuffix(path.Base(shader), ".spv")
+	a.debug.Name(cp.descLayout, name+" descriptor layout")
+	a.debug.Name(cp.pipelineLayout, name+" pipeline layout")
+	a.debug.Name(cp.pipeline, name)
+	for i, set := range cp.descSets {
+		a.debug.Name(set, fmt.Sprintf("%s descriptor set %d", name, i))
+	}
+	return cp
+}
+
+// BindBuffer points a storage or uniform buffer binding of the given set at buf.
+func (cp *ComputePipeline) BindBuffer(dev vk.Device, set int, binding uint32, buf *Buffer) {
+	vk.UpdateDescriptorSets(dev, 1, []vk.WriteDescriptorSet{{
+		SType:           vk.StructureTypeWriteDescriptorSet,
+		DstSet:          cp.descSets[set],
+		DstBinding:      binding,
+		DescriptorCount: 1,
+		DescriptorType:  cp.bindings[binding],
+		PBufferInfo: []vk.DescriptorBufferInfo{{
+			Buffer: buf.buffer,
+			Offset: 0,
+			Range:  buf.size,
+		}},
+	}}, 0, nil)
+}
+
+// Dispatch records the pipeline bind, the given descriptor set, the push
+// constants (if any) and a dispatch of the given workgroup counts into cmd.
+func (cp *ComputePipeline) Dispatch(cmd vk.CommandBuffer, set int, pushConstants unsafe.Pointer, x, y, z uint32) {
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointCompute, cp.pipeline)
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointCompute, cp.pipelineLayout,
+		0, 1, []vk.DescriptorSet{cp.descSets[set]}, 0, nil)
+	if cp.pushConstantSize > 0 && pushConstants != nil {
+		vk.CmdPushConstants(cmd, cp.pipelineLayout,
+			vk.ShaderStageFlags(vk.ShaderStageComputeBit), 0, cp.pushConstantSize, pushConstants)
+	}
+	vk.CmdDispatch(cmd, x, y, z)
+}
+
+func (cp *ComputePipeline) Destroy(dev vk.Device) {
+	vk.DestroyPipeline(dev, cp.pipeline, nil)
+	vk.DestroyPipelineLayout(dev, cp.pipelineLayout, nil)
+	vk.DestroyDescriptorSetLayout(dev, cp.descLayout, nil)
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// Config holds the startup options. They are fixed for the lifetime of the
+// process, unlike Settings which the UI edits while running; Render only
+// provides their initial values.
+//
+// Options are layered: defaults, then the config file, then environment
+// variables, then flags, each overriding the ones before.
+type Config struct {
+	Title      string `json:"title"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Fullscreen bool   `json:"fullscreen"`
+
+	// VSync selects FIFO presentation; without it PresentMode is used.
+	// asche always creates its swapchain with FIFO, so validate rejects
+	// anything else.
+	VSync       bool   `json:"vsync"`
+	PresentMode string `json:"presentMode"`
+	// FPS caps the frame rate; 0 renders as fast as presentation allows.
+	FPS int `json:"fps"`
+
+	// GPU is the index of the physical device to use, -1 to pick one by
+	// GPUName, a part of its name, or else by GPUPrefer, the device type
+	// preferred: "discrete", "integrated" or "cpu".
+	GPU        int    `json:"gpu"`
+	GPUName    string `json:"gpuName"`
+	GPUPrefer  string `json:"gpuPrefer"`
+	Validation bool   `json:"validation"`
+	// ValidationFatal stops with exit status 1 after the frame that hit
+	// the first validation error, for tests.
+	ValidationFatal bool `json:"validationFatal"`
+	// ValidationVerbose also logs informational and debug messages.
+	ValidationVerbose bool `json:"validationVerbose"`
+	MSAA              int  `json:"msaa"`
+	// Threads is how many goroutines record the draws of the main pass
+	// into secondary command buffers: 1 records them inline, 0 uses one
+	// per CPU up to maxRecordThreads.
+	Threads int `json:"threads"`
+
+	// Headless renders Frames frames into a hidden window and writes the
+	// last one to this path instead of running interactively.
+	Headless string `json:"headless"`
+	Frames   int    `json:"frames"`
+
+	// Record captures frames into a directory of numbered PNGs, or into a
+	// Y4M stream when it ends in .y4m. Every RecordEvery-th frame is kept,
+	// the simulation advances 1/RecordFPS seconds per frame regardless of
+	// real time, and RecordFrames > 0 stops after that many captures.
+	Record       string `json:"record"`
+	RecordEvery  int    `json:"recordEvery"`
+	RecordFPS    int    `json:"recordFps"`
+	RecordFrames int    `json:"recordFrames"`
+
+	// GamepadMappings is an SDL_GameControllerDB-style file mapping
+	// controllers, by the name GLFW reports, to the standard layout.
+	GamepadMappings string `json:"gamepadMappings"`
+
+	// Screenshots is the directory timestamped screenshots are saved in.
+	Screenshots string `json:"screenshots"`
+
+	// Stats is where per-frame timings are exported on exit, as .csv or
+	// .json; empty only logs their summary.
+	Stats string `json:"stats"`
+
+	Scene string `json:"scene"`
+	// Texture is the image on the cube, an embedded asset or a file, and
+	// Sampler how it is filtered.
+	Texture string        `json:"texture"`
+	Sampler SamplerConfig `json:"sampler"`
+	Render  Settings      `json:"render"`
+}
+
+// configEnvPrefix starts the environment variable of every option: the
+// "fps" option is read from APP_FPS, "presentMode" from APP_PRESENTMODE.
+// APP_CONFIG names the config file when -config is not given.
+const configEnvPrefix = "APP_"
+
+var presentModes = map[string]vk.PresentMode{
+	"immediate":    vk.PresentModeImmediate,
+	"mailbox":      vk.PresentModeMailbox,
+	"fifo":         vk.PresentModeFifo,
+	"fifo-relaxed": vk.PresentModeFifoRelaxed,
+}
+
+func defaultConfig() Config {
+	return Config{
+		Title:       "test",
+		Width:       500,
+		Height:      500,
+		VSync:       true,
+		PresentMode: "fifo",
+		FPS:         60,
+		GPU:         -1,
+		GPUPrefer:   "discrete",
+		MSAA:        1,
+		Frames:      1,
+		Screenshots: "screenshots",
+		RecordEvery: 1,
+		RecordFPS:   60,
+		Scene:       "fountain",
+		Texture:     "textures/gopher.png",
+		Sampler:     defaultSamplerConfig(),
+		Render:      defaultSettings(),
+	}
+}
+
+// Actions run instead of the application when asked for on the command
+// line.
+const (
+	actionPrintConfig = "print-config"
+	actionListGPUs    = "list-gpus"
+	actionInfo        = "info"
+)
+
+// loadConfig builds the configuration from all layers. action is set when
+// the command line asks for something other than running.
+func loadConfig(args []string) (cfg Config, action string, err error) {
+	cfg = defaultConfig()
+
+	// A first pass over the flags only finds the config file; they are
+	// applied for real once the file and environment are in.
+	scratch := cfg
+	path, _, err := parseFlags(&scratch, args)
+	if err != nil {
+		return cfg, "", err
+	}
+	if path == "" {
+		path = os.Getenv(configEnvPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return cfg, "", err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return cfg, "", err
+	}
+	if _, action, err = parseFlags(&cfg, args); err != nil {
+		return cfg, "", err
+	}
+	return cfg, action, cfg.validate()
+}
+
+// loadFile merges a JSON config file over cfg. Options it leaves out keep
+// their values, unknown ones are rejected.
+func (cfg *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		if serr, ok := err.(*json.SyntaxError); ok {
+			line := 1 + bytes.Count(data[:serr.Offset], []byte("\n"))
+			return fmt.Errorf("config %s:%d: %v", path, line, err)
+		}
+		return fmt.Errorf("config %s: %v", path, err)
+	}
+	return nil
+}
+
+// loadEnv applies APP_* variables for the top-level options.
+func (cfg *Config) loadEnv() error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.ToUpper(t.Field(i).Tag.Get("json"))
+		env := configEnvPrefix + name
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not an integer", env, value)
+			}
+			field.SetInt(int64(n))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a boolean", env, value)
+			}
+			field.SetBool(b)
+		default:
+			return fmt.Errorf("%s: this option can only be set in the config file", env)
+		}
+	}
+	return nil
+}
+
+// print writes the configuration in the config file format.
+func (cfg *Config) print(w io.Writer) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// parseFlags fills cfg from the command line, leaving options that were not
+// given at their current values. It also returns the -config path and the
+// action asked for, if any.
+func parseFlags(cfg *Config, args []string) (configPath string, action string, err error) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	var printConfig, listGPUs, info bool
+	fs.StringVar(&configPath, "config", "", "JSON config file, overridden by APP_* variables and flags")
+	fs.BoolVar(&printConfig, "print-config", false, "print the merged configuration and exit")
+	fs.BoolVar(&listGPUs, "list-gpus", false, "list the physical devices, marking the one selected, and exit")
+	fs.BoolVar(&info, "info", false, "print a JSON report of the Vulkan instance and devices and exit")
+	fs.StringVar(&cfg.Title, "title", cfg.Title, "window title and Vulkan application name")
+	fs.IntVar(&cfg.Width, "width", cfg.Width, "window width in pixels")
+	fs.IntVar(&cfg.Height, "height", cfg.Height, "window height in pixels")
+	fs.BoolVar(&cfg.Fullscreen, "fullscreen", cfg.Fullscreen, "use the primary monitor at its current resolution")
+	fs.BoolVar(&cfg.VSync, "vsync", cfg.VSync, "synchronize presentation with the display (FIFO, the only mode supported)")
+	fs.StringVar(&cfg.PresentMode, "present-mode", cfg.PresentMode,
+		"present mode without vsync; only fifo is supported")
+	fs.IntVar(&cfg.FPS, "fps", cfg.FPS, "frame rate cap, 0 for uncapped")
+	fs.IntVar(&cfg.GPU, "gpu", cfg.GPU, "physical device index, -1 to pick one by name or preference")
+	fs.StringVar(&cfg.GPUName, "gpu-name", cfg.GPUName, "use the first device whose name contains this")
+	fs.StringVar(&cfg.GPUPrefer, "gpu-prefer", cfg.GPUPrefer, "preferred device type: discrete, integrated or cpu")
+	fs.BoolVar(&cfg.Validation, "validation", cfg.Validation, "enable the Vulkan validation layers")
+	fs.BoolVar(&cfg.ValidationFatal, "validation-fatal", cfg.ValidationFatal, "exit with status 1 after the first validation error")
+	fs.BoolVar(&cfg.ValidationVerbose, "validation-verbose", cfg.ValidationVerbose, "also log informational validation messages")
+	fs.IntVar(&cfg.MSAA, "msaa", cfg.MSAA, "samples per pixel: 1, 2, 4 or 8")
+	fs.IntVar(&cfg.Threads, "threads", cfg.Threads, "goroutines recording draws, 1 to record inline, 0 for one per CPU")
+	fs.StringVar(&cfg.Headless, "headless", cfg.Headless, "render without a visible window and write the result to this PNG")
+	fs.IntVar(&cfg.Frames, "frames", cfg.Frames, "frames to render in headless mode")
+	fs.StringVar(&cfg.Record, "record", cfg.Record, "record frames as PNGs into this directory, or to a .y4m file")
+	fs.IntVar(&cfg.RecordEvery, "record-every", cfg.RecordEvery, "keep every Nth frame while recording")
+	fs.IntVar(&cfg.RecordFPS, "record-fps", cfg.RecordFPS, "simulated frames per second while recording")
+	fs.IntVar(&cfg.RecordFrames, "record-frames", cfg.RecordFrames, "stop after recording this many frames, 0 to record until closed")
+	fs.StringVar(&cfg.GamepadMappings, "gamepad-mappings", cfg.GamepadMappings, "SDL_GameControllerDB-style file of controller mappings")
+	fs.StringVar(&cfg.Screenshots, "screenshots", cfg.Screenshots, "directory for screenshots taken with F12")
+	fs.StringVar(&cfg.Stats, "stats", cfg.Stats, "export frame timings to this .csv or .json file on exit")
+	fs.StringVar(&cfg.Scene, "scene", cfg.Scene, "particle scene: "+strings.Join(sceneNames(), ", "))
+	fs.StringVar(&cfg.Texture, "texture", cfg.Texture, "image on the cube, an embedded asset or a file")
+	fs.Float64Var(&cfg.Sampler.LODBias, "lod-bias", cfg.Sampler.LODBias, "bias added to the texture mip level")
+	if err := fs.Parse(args); err != nil {
+		return "", "", err
+	}
+	if fs.NArg() > 0 {
+		return "", "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	switch {
+	case printConfig:
+		action = actionPrintConfig
+	case listGPUs:
+		action = actionListGPUs
+	case info:
+		action = actionInfo
+	}
+	return configPath, action, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return fmt.Errorf("window size must be positive, got %dx%d", cfg.Width, cfg.Height)
+	}
+	if _, ok := presentModes[cfg.PresentMode]; !ok {
+		return fmt.Errorf("unknown present mode %q, want one of %s",
+			cfg.PresentMode, strings.Join(presentModeNames(), ", "))
+	}
+	if mode := cfg.presentMode(); mode != vk.PresentModeFifo {
+		return fmt.Errorf("present mode %s is not supported, asche always presents with fifo",
+			presentModeName(mode))
+	}
+	if cfg.FPS < 0 {
+		return fmt.Errorf("fps must not be negative, got %d", cfg.FPS)
+	}
+	if cfg.GPU < -1 {
+		return fmt.Errorf("gpu must be a device index or -1, got %d", cfg.GPU)
+	}
+	if _, ok := gpuPreferences[cfg.GPUPrefer]; !ok {
+		return fmt.Errorf("gpuPrefer must be discrete, integrated or cpu, got %q", cfg.GPUPrefer)
+	}
+	switch cfg.MSAA {
+	case 1, 2, 4, 8:
+	default:
+		return fmt.Errorf("msaa must be 1, 2, 4 or 8, got %d", cfg.MSAA)
+	}
+	if cfg.Threads < 0 {
+		return fmt.Errorf("threads must not be negative, got %d", cfg.Threads)
+	}
+	if (cfg.ValidationFatal || cfg.ValidationVerbose) && !cfg.Validation {
+		return fmt.Errorf("validationFatal and validationVerbose need validation enabled")
+	}
+	if cfg.Headless != "" && cfg.Frames < 1 {
+		return fmt.Errorf("headless mode needs at least one frame, got %d", cfg.Frames)
+	}
+	if cfg.RecordEvery < 1 || cfg.RecordFPS < 1 || cfg.RecordFrames < 0 {
+		return fmt.Errorf("recordEvery and recordFps must be at least 1 and recordFrames not negative")
+	}
+	if ext := filepath.Ext(cfg.Stats); cfg.Stats != "" && ext != ".csv" && ext != ".json" {
+		return fmt.Errorf("stats file must end in .csv or .json, got %q", cfg.Stats)
+	}
+	if _, ok := scenes[cfg.Scene]; !ok {
+		return fmt.Errorf("unknown scene %q, want one of %s",
+			cfg.Scene, strings.Join(sceneNames(), ", "))
+	}
+	return cfg.Sampler.validate()
+}
+
+// presentMode is the mode the configuration asks for.
+func (cfg *Config) presentMode() vk.PresentMode {
+	if cfg.VSync {
+		return vk.PresentModeFifo
+	}
+	return presentModes[cfg.PresentMode]
+}
+
+func presentModeNames() []string {
+	return []string{"immediate", "mailbox", "fifo", "fifo-relaxed"}
+}
+
+func sceneNames() []string {
+	var names []string
+	for name := range scenes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recordThreads is the number of goroutines recording draws.
+func (cfg *Config) recordThreads() int {
+	if cfg.Threads > 0 {
+		return cfg.Threads
+	}
+	n := runtime.NumCPU()
+	if n > maxRecordThreads {
+		n = maxRecordThreads
+	}
+	return n
+}
+
+// sampleCount picks the largest supported sample count not above the
+// configured one, for both color and depth attachments.
+func (a *Application) sampleCount() vk.SampleCountFlagBits {
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(a.Context().Platform().PhysicalDevice(), &props)
+	props.Deref()
+	props.Limits.Deref()
+	supported := props.Limits.FramebufferColorSampleCounts & props.Limits.FramebufferDepthSampleCounts
+
+	for samples := a.config.MSAA; samples > 1; samples /= 2 {
+		if supported&vk.SampleCountFlags(samples) != 0 {
+			if samples != a.config.MSAA {
+				log.Printf("msaa: %dx is not supported, using %dx", a.config.MSAA, samples)
+			}
+			return vk.SampleCountFlagBits(samples)
+		}
+	}
+	if a.config.MSAA > 1 {
+		log.Printf("msaa: %dx is not supported, rendering without multisampling", a.config.MSAA)
+	}
+	return vk.SampleCount1Bit
+}
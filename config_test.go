@@ -0,0 +1,190 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		flags []string
+		fps   int
+		title string
+	}{
+		{
+			name:  "defaults",
+			fps:   60,
+			title: "test",
+		},
+		{
+			name:  "file over defaults",
+			file:  `{"fps": 30, "title": "file"}`,
+			fps:   30,
+			title: "file",
+		},
+		{
+			name:  "env over file",
+			file:  `{"fps": 30, "title": "file"}`,
+			env:   map[string]string{"APP_FPS": "20"},
+			fps:   20,
+			title: "file",
+		},
+		{
+			name:  "flags over env",
+			file:  `{"fps": 30, "title": "file"}`,
+			env:   map[string]string{"APP_FPS": "20", "APP_TITLE": "env"},
+			flags: []string{"-fps", "10"},
+			fps:   10,
+			title: "env",
+		},
+		{
+			name:  "flags over file",
+			file:  `{"fps": 30}`,
+			flags: []string{"-fps", "0", "-title", "flag"},
+			fps:   0,
+			title: "flag",
+		},
+		{
+			name:  "env over defaults",
+			env:   map[string]string{"APP_TITLE": "env"},
+			fps:   60,
+			title: "env",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// APP_CONFIG would pick up a stray file from the test
+			// environment.
+			t.Setenv(configEnvPrefix+"CONFIG", "")
+			var args []string
+			if tt.file != "" {
+				args = append(args, "-config", writeConfigFile(t, tt.file))
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args = append(args, tt.flags...)
+
+			cfg, _, err := loadConfig(args)
+			if err != nil {
+				t.Fatalf("loadConfig(%q): %v", args, err)
+			}
+			if cfg.FPS != tt.fps || cfg.Title != tt.title {
+				t.Errorf("got fps %d, title %q; want fps %d, title %q",
+					cfg.FPS, cfg.Title, tt.fps, tt.title)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	t.Setenv(configEnvPrefix+"CONFIG", writeConfigFile(t, `{"width": 640}`))
+	cfg, _, err := loadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 640 {
+		t.Errorf("width = %d, want 640 from APP_CONFIG", cfg.Width)
+	}
+}
+
+// TestLoadConfigRenderPreset checks that a file only overrides the render
+// settings it lists.
+func TestLoadConfigRenderPreset(t *testing.T) {
+	t.Setenv(configEnvPrefix+"CONFIG", "")
+	path := writeConfigFile(t, `{"render": {"cullMode": "none"}}`)
+	cfg, _, err := loadConfig([]string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := defaultSettings()
+	want.CullMode = 0
+	if cfg.Render != want {
+		t.Errorf("render = %+v, want %+v", cfg.Render, want)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		flags []string
+		want  string
+	}{
+		{
+			name: "unknown file option",
+			file: `{"fsp": 30}`,
+			want: `unknown field "fsp"`,
+		},
+		{
+			name: "file syntax error",
+			file: "{\n\"fps\": 30,\n}",
+			want: ":3:",
+		},
+		{
+			name: "bad env integer",
+			env:  map[string]string{"APP_FPS": "fast"},
+			want: `APP_FPS: "fast" is not an integer`,
+		},
+		{
+			name: "env for a file-only option",
+			env:  map[string]string{"APP_RENDER": "{}"},
+			want: "can only be set in the config file",
+		},
+		{
+			name:  "validation after all layers",
+			file:  `{"msaa": 4}`,
+			flags: []string{"-msaa", "3"},
+			want:  "msaa must be 1, 2, 4 or 8, got 3",
+		},
+		{
+			name:  "present mode",
+			flags: []string{"-vsync=false", "-present-mode", "mailbox"},
+			want:  "present mode mailbox is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(configEnvPrefix+"CONFIG", "")
+			var args []string
+			if tt.file != "" {
+				args = append(args, "-config", writeConfigFile(t, tt.file))
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args = append(args, tt.flags...)
+
+			_, _, err := loadConfig(args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadConfig(%q) = %v, want an error containing %q", args, err, tt.want)
+			}
+		})
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMain(m *testing.M) {
+	// Options left in the environment would leak into every case.
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, configEnvPrefix) {
+			os.Unsetenv(kv[:strings.Index(kv, "=")])
+		}
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// cubeVertices are the 36 corners of the cube's triangles, six per side,
+// and cubeUVs their texture coordinates, as in the LunarG cube demo.
+var cubeVertices = [36][3]float32{
+	{-1, -1, -1}, {-1, -1, 1}, {-1, 1, 1}, // -X side
+	{-1, 1, 1}, {-1, 1, -1}, {-1, -1, -1},
+
+	{-1, -1, -1}, {1, 1, -1}, {1, -1, -1}, // -Z side
+	{-1, -1, -1}, {-1, 1, -1}, {1, 1, -1},
+
+	{-1, -1, -1}, {1, -1, -1}, {1, -1, 1}, // -Y side
+	{-1, -1, -1}, {1, -1, 1}, {-1, -1, 1},
+
+	{-1, 1, -1}, {-1, 1, 1}, {1, 1, 1}, // +Y side
+	{-1, 1, -1}, {1, 1, 1}, {1, 1, -1},
+
+	{1, 1, -1}, {1, 1, 1}, {1, -1, 1}, // +X side
+	{1, -1, 1}, {1, -1, -1}, {1, 1, -1},
+
+	{-1, 1, 1}, {-1, -1, 1}, {1, 1, 1}, // +Z side
+	{-1, -1, 1}, {1, -1, 1}, {1, 1, 1},
+}
+
+var cubeUVs = [36][2]float32{
+	{0, 1}, {1, 1}, {1, 0}, // -X side
+	{1, 0}, {0, 0}, {0, 1},
+
+	{1, 1}, {0, 0}, {0, 1}, // -Z side
+	{1, 1}, {1, 0}, {0, 0},
+
+	{1, 0}, {1, 1}, {0, 1}, // -Y side
+	{1, 0}, {0, 1}, {0, 0},
+
+	{1, 0}, {0, 0}, {0, 1}, // +Y side
+	{1, 0}, {0, 1}, {1, 1},
+
+	{1, 0}, {0, 0}, {0, 1}, // +X side
+	{0, 1}, {1, 1}, {1, 0},
+
+	{0, 0}, {0, 1}, {1, 0}, // +Z side
+	{0, 1}, {1, 1}, {1, 0},
+}
+
+// cubeUniforms matches the std140 block of shaders/cube.vert and
+// cube.frag. LightDir points towards the light, with the ambient term in w,
+// and LightColor is premultiplied by its intensity.
+type cubeUniforms struct {
+	MVP        mat4
+	Model      mat4
+	LightDir   [4]float32
+	LightColor [4]float32
+	Position   [36][4]float32
+	Attr       [36][4]float32
+	Normal     [36][4]float32
+}
+
+// cubeModel scales the cube down to a unit cube resting on the ground.
+var cubeModel = mat4{
+	0.5, 0, 0, 0,
+	0, 0.5, 0, 0,
+	0, 0, 0.5, 0,
+	0, 0.5, 0, 1,
+}
+
+// prepareCubeDataBuffers creates a uniform buffer per swapchain image,
+// rewritten every frame with the current transform. The buffers outlive
+// swapchain recreation; only images without one get a new buffer.
+func (a *Application) prepareCubeDataBuffers() {
+	var data cubeUniforms
+	for i, v := range cubeVertices {
+		data.Position[i] = [4]float32{v[0], v[1], v[2], 1}
+		data.Attr[i] = [4]float32{cubeUVs[i][0], cubeUVs[i][1], 0, 0}
+	}
+	for i := 0; i < len(cubeVertices); i += 3 {
+		v0, v1, v2 := vec3(cubeVertices[i]), vec3(cubeVertices[i+1]), vec3(cubeVertices[i+2])
+		n := v1.sub(v0).cross(v2.sub(v0)).normalize()
+		// The cube is centered on the origin, so outward normals point
+		// away from it.
+		if n.dot(v0) < 0 {
+			n = n.scale(-1)
+		}
+		for j := i; j < i+3; j++ {
+			data.Normal[j] = [4]float32{n[0], n[1], n[2], 0}
+		}
+	}
+	a.cubeData = data
+
+	size := binary.Size(&data)
+	for i := len(a.cubeBuffers); i < len(a.Context().SwapchainImageResources()); i++ {
+		buf := a.newBuffer(size, vk.BufferUsageUniformBufferBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		a.debug.NameBuffer(buf, fmt.Sprintf("cube uniforms %d", i))
+		a.cubeBuffers = append(a.cubeBuffers, buf)
+	}
+}
+
+// updateCube spins the cube by this frame's share of the rotation speed
+// and writes the transform and light to the image's uniforms.
+func (a *Application) updateCube(imageIdx int) {
+	a.cubeAngle = float32(math.Mod(float64(a.cubeAngle+a.settings.RotationSpeed*a.frameDelta), 360))
+	model := cubeModel.mul(rotateY(a.cubeAngle * math.Pi / 180))
+	a.cubeData.MVP = a.proj.mul(a.view).mul(model)
+	a.cubeData.Model = model
+	light := a.settings.lightDirection()
+	a.cubeData.LightDir = [4]float32{light[0], light[1], light[2], a.settings.Ambient}
+	c := a.settings.LightColor
+	a.cubeData.LightColor = [4]float32{c[0] * c[3], c[1] * c[3], c[2] * c[3], 1}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, &a.cubeData)
+	a.cubeBuffers[imageIdx].Write(0, buf.Bytes())
+}
+
+// prepareTextures loads the cube's texture, falling back to the embedded
+// default when it cannot be loaded. The first frame cannot sample it
+// before the upload is done, so this waits for it.
+func (a *Application) prepareTextures() {
+	if a.texture != nil {
+		return
+	}
+	texture, ticket, err := a.loadTexture(a.config.Texture, a.config.Sampler)
+	if fallback := defaultConfig().Texture; err != nil && a.config.Texture != fallback {
+		log.Printf("%v; using %s instead", err, fallback)
+		texture, ticket, err = a.loadTexture(fallback, a.config.Sampler)
+	}
+	orPanic(err)
+	ticket.Wait()
+	a.texture = texture
+}
@@ -0,0 +1,200 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+// The vulkan-go bindings do not cover VK_EXT_debug_utils, so the few
+// structures and entry points used here are declared by hand. Handles are
+// passed as opaque pointers, which is what they are on 64-bit platforms.
+
+typedef struct {
+	int32_t     sType;
+	const void* pNext;
+	int32_t     objectType;
+	uint64_t    objectHandle;
+	const char* pObjectName;
+} debugObjectNameInfo;
+
+typedef struct {
+	int32_t     sType;
+	const void* pNext;
+	const char* pLabelName;
+	float       color[4];
+} debugLabel;
+
+typedef int32_t (*setObjectNameFunc)(void* device, const debugObjectNameInfo* info);
+typedef void (*beginLabelFunc)(void* cmd, const debugLabel* label);
+typedef void (*endLabelFunc)(void* cmd);
+
+static int32_t callSetObjectName(void* fn, void* device, int32_t type, uint64_t handle, const char* name) {
+	debugObjectNameInfo info = {1000128000, 0, type, handle, name};
+	return ((setObjectNameFunc)fn)(device, &info);
+}
+
+static void callBeginLabel(void* fn, void* cmd, const char* name, float r, float g, float b, float a) {
+	debugLabel label = {1000128002, 0, name, {r, g, b, a}};
+	((beginLabelFunc)fn)(cmd, &label);
+}
+
+static void callEndLabel(void* fn, void* cmd) {
+	((endLabelFunc)fn)(cmd);
+}
+*/
+import "C"
+
+import (
+	"log"
+	"reflect"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const debugUtilsExtension = "VK_EXT_debug_utils"
+
+// VkObjectType values of the handles that get named.
+const (
+	objectTypeSemaphore           = 5
+	objectTypeCommandBuffer       = 6
+	objectTypeFence               = 7
+	objectTypeDeviceMemory        = 8
+	objectTypeBuffer              = 9
+	objectTypeImage               = 10
+	objectTypeQueryPool           = 12
+	objectTypeImageView           = 14
+	objectTypePipelineCache       = 16
+	objectTypePipelineLayout      = 17
+	objectTypeRenderPass          = 18
+	objectTypePipeline            = 19
+	objectTypeDescriptorSetLayout = 20
+	objectTypeSampler             = 21
+	objectTypeDescriptorPool      = 22
+	objectTypeDescriptorSet       = 23
+	objectTypeFramebuffer         = 24
+	objectTypeCommandPool         = 25
+)
+
+// DebugUtils attaches names to Vulkan objects and labels to command buffer
+// regions so they are recognizable in frame captures and validation
+// messages. Every method is a no-op when the extension is missing, so
+// callers never need to check.
+type DebugUtils struct {
+	dev           vk.Device
+	setObjectName unsafe.Pointer
+	beginLabel    unsafe.Pointer
+	endLabel      unsafe.Pointer
+}
+
+func (a *Application) prepareDebugUtils() {
+	if a.debug != nil {
+		return
+	}
+	a.debug = &DebugUtils{dev: a.Context().Device()}
+	if !a.debugUtilsEnabled {
+		return
+	}
+	instance := a.Context().Platform().Instance()
+	a.debug.setObjectName = unsafe.Pointer(vk.GetInstanceProcAddr(instance, "vkSetDebugUtilsObjectNameEXT\x00"))
+	a.debug.beginLabel = unsafe.Pointer(vk.GetInstanceProcAddr(instance, "vkCmdBeginDebugUtilsLabelEXT\x00"))
+	a.debug.endLabel = unsafe.Pointer(vk.GetInstanceProcAddr(instance, "vkCmdEndDebugUtilsLabelEXT\x00"))
+	if a.debug.setObjectName == nil || a.debug.beginLabel == nil || a.debug.endLabel == nil {
+		log.Printf("debug utils: %s entry points are missing, objects stay unnamed", debugUtilsExtension)
+		*a.debug = DebugUtils{}
+	}
+}
+
+// objectType returns the VkObjectType of handle, or 0 for handles it does not
+// know, which are left unnamed.
+func objectType(handle interface{}) int32 {
+	switch handle.(type) {
+	case vk.Semaphore:
+		return objectTypeSemaphore
+	case vk.CommandBuffer:
+		return objectTypeCommandBuffer
+	case vk.Fence:
+		return objectTypeFence
+	case vk.DeviceMemory:
+		return objectTypeDeviceMemory
+	case vk.Buffer:
+		return objectTypeBuffer
+	case vk.Image:
+		return objectTypeImage
+	case vk.QueryPool:
+		return objectTypeQueryPool
+	case vk.ImageView:
+		return objectTypeImageView
+	case vk.PipelineCache:
+		return objectTypePipelineCache
+	case vk.PipelineLayout:
+		return objectTypePipelineLayout
+	case vk.RenderPass:
+		return objectTypeRenderPass
+	case vk.Pipeline:
+		return objectTypePipeline
+	case vk.DescriptorSetLayout:
+		return objectTypeDescriptorSetLayout
+	case vk.Sampler:
+		return objectTypeSampler
+	case vk.DescriptorPool:
+		return objectTypeDescriptorPool
+	case vk.DescriptorSet:
+		return objectTypeDescriptorSet
+	case vk.Framebuffer:
+		return objectTypeFramebuffer
+	case vk.CommandPool:
+		return objectTypeCommandPool
+	}
+	log.Printf("debug utils: cannot name objects of type %T", handle)
+	return 0
+}
+
+// Name attaches name to a Vulkan handle such as a vk.Image or vk.Pipeline.
+func (d *DebugUtils) Name(handle interface{}, name string) {
+	if d == nil || d.setObjectName == nil {
+		return
+	}
+	typ := objectType(handle)
+	if typ == 0 {
+		return
+	}
+	ptr := reflect.ValueOf(handle).Pointer()
+	if ptr == 0 {
+		return
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	C.callSetObjectName(d.setObjectName, unsafe.Pointer(d.dev),
+		C.int32_t(typ), C.uint64_t(ptr), cname)
+}
+
+// NameBuffer names a Buffer. Its memory is an allocator block shared with
+// other resources, so it is left unnamed.
+func (d *DebugUtils) NameBuffer(b *Buffer, name string) {
+	d.Name(b.buffer, name)
+}
+
+// Begin opens a labeled region in cmd; every Begin needs a matching End.
+func (d *DebugUtils) Begin(cmd vk.CommandBuffer, label string, color [4]float32) {
+	if d == nil || d.beginLabel == nil {
+		return
+	}
+	clabel := C.CString(label)
+	defer C.free(unsafe.Pointer(clabel))
+	C.callBeginLabel(d.beginLabel, unsafe.Pointer(cmd), clabel,
+		C.float(color[0]), C.float(color[1]), C.float(color[2]), C.float(color[3]))
+}
+
+func (d *DebugUtils) End(cmd vk.CommandBuffer) {
+	if d == nil || d.endLabel == nil {
+		return
+	}
+	C.callEndLabel(d.endLabel, unsafe.Pointer(cmd))
+}
+
+// Label colors, so related regions stand out in capture tools.
+var (
+	labelCompute = [4]float32{0.9, 0.6, 0.2, 1}
+	labelScene   = [4]float32{0.3, 0.6, 0.9, 1}
+	labelOverlay = [4]float32{0.5, 0.8, 0.4, 1}
+)
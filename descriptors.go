@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const (
+	// descriptorPoolSets is the set count of the first pool; every pool
+	// created after it is twice as large, up to descriptorPoolMaxSets.
+	descriptorPoolSets    = 16
+	descriptorPoolMaxSets = 1024
+)
+
+// descriptorRatios is how many descriptors of each type a pool holds per
+// set. Pools are sized by guesses rather than exact counts; a set that
+// does not fit simply moves on to the next pool.
+var descriptorRatios = []struct {
+	typ   vk.DescriptorType
+	ratio float32
+}{
+	{vk.DescriptorTypeUniformBuffer, 2},
+	{vk.DescriptorTypeStorageBuffer, 2},
+	{vk.DescriptorTypeCombinedImageSampler, 2},
+	{vk.DescriptorTypeStorageImage, 1},
+	{vk.DescriptorTypeUniformBufferDynamic, 1},
+	{vk.DescriptorTypeStorageBufferDynamic, 1},
+	{vk.DescriptorTypeSampledImage, 1},
+	{vk.DescriptorTypeSampler, 0.5},
+}
+
+// DescriptorAllocator hands out descriptor sets from a growing list of
+// pools. When a pool runs out of memory or is too fragmented, it is set
+// aside as full and allocation continues in a fresh or recycled pool.
+// Sets are never freed one by one; Reset returns every set at once.
+type DescriptorAllocator struct {
+	dev   vk.Device
+	debug *DebugUtils
+	name  string
+
+	current vk.DescriptorPool
+	full    []vk.DescriptorPool
+	// free holds pools that were reset and can be used again.
+	free     []vk.DescriptorPool
+	nextSets uint32
+	pools    int
+}
+
+func newDescriptorAllocator(dev vk.Device, debug *DebugUtils, name string) *DescriptorAllocator {
+	return &DescriptorAllocator{
+		dev:      dev,
+		debug:    debug,
+		name:     name,
+		nextSets: descriptorPoolSets,
+	}
+}
+
+func (d *DescriptorAllocator) createPool() (vk.DescriptorPool, error) {
+	sets := d.nextSets
+	if d.nextSets < descriptorPoolMaxSets {
+		d.nextSets *= 2
+	}
+	sizes := make([]vk.DescriptorPoolSize, 0, len(descriptorRatios))
+	for _, r := range descriptorRatios {
+		count := uint32(r.ratio * float32(sets))
+		if count == 0 {
+			count = 1
+		}
+		sizes = append(sizes, vk.DescriptorPoolSize{
+			Type:            r.typ,
+			DescriptorCount: count,
+		})
+	}
+	var pool vk.DescriptorPool
+	ret := vk.CreateDescriptorPool(d.dev, &vk.DescriptorPoolCreateInfo{
+		SType:         vk.StructureTypeDescriptorPoolCreateInfo,
+		MaxSets:       sets,
+		PoolSizeCount: uint32(len(sizes)),
+		PPoolSizes:    sizes,
+	}, nil, &pool)
+	if err := vk.Error(ret); err != nil {
+		return nil, fmt.Errorf("%s descriptors: creating a pool of %d sets: %v", d.name, sets, err)
+	}
+	d.pools++
+	d.debug.Name(pool, fmt.Sprintf("%s descriptor pool %d", d.name, d.pools))
+	return pool, nil
+}
+
+// nextPool retires the current pool, if any, and makes a recycled or new
+// pool current.
+func (d *DescriptorAllocator) nextPool() error {
+	if d.current != vk.NullDescriptorPool {
+		d.full = append(d.full, d.current)
+		d.current = vk.NullDescriptorPool
+	}
+	if n := len(d.free); n > 0 {
+		d.current = d.free[n-1]
+		d.free = d.free[:n-1]
+		return nil
+	}
+	pool, err := d.createPool()
+	if err != nil {
+		return err
+	}
+	d.current = pool
+	return nil
+}
+
+// Allocate returns a set with the given layout.
+func (d *DescriptorAllocator) Allocate(layout vk.DescriptorSetLayout) (vk.DescriptorSet, error) {
+	if d.current == vk.NullDescriptorPool {
+		if err := d.nextPool(); err != nil {
+			return nil, err
+		}
+	}
+	var set vk.DescriptorSet
+	for attempt := 0; ; attempt++ {
+		ret := vk.AllocateDescriptorSets(d.dev, &vk.DescriptorSetAllocateInfo{
+			SType:              vk.StructureTypeDescriptorSetAllocateInfo,
+			DescriptorPool:     d.current,
+			DescriptorSetCount: 1,
+			PSetLayouts:        []vk.DescriptorSetLayout{layout},
+		}, &set)
+		switch {
+		case ret == vk.Success:
+			return set, nil
+		case (ret == vk.ErrorOutOfPoolMemory || ret == vk.ErrorFragmentedPool) && attempt == 0:
+			if err := d.nextPool(); err != nil {
+				return nil, err
+			}
+		default:
+			// A fresh pool that cannot hold the set never will.
+			return nil, fmt.Errorf("%s descriptors: allocating a set: %v", d.name, vk.Error(ret))
+		}
+	}
+}
+
+// MustAllocate is Allocate for sets the application cannot run without.
+func (d *DescriptorAllocator) MustAllocate(layout vk.DescriptorSetLayout) vk.DescriptorSet {
+	set, err := d.Allocate(layout)
+	orPanic(err)
+	return set
+}
+
+// Reset frees every set allocated so far, keeping the pools for reuse.
+// The GPU must be done with the sets.
+func (d *DescriptorAllocator) Reset() {
+	if d.current != vk.NullDescriptorPool {
+		d.full = append(d.full, d.current)
+		d.current = vk.NullDescriptorPool
+	}
+	for _, pool := range d.full {
+		ret := vk.ResetDescriptorPool(d.dev, pool, 0)
+		orPanic(as.NewError(ret))
+		d.free = append(d.free, pool)
+	}
+	d.full = d.full[:0]
+}
+
+// Pools returns how many pools the allocator has created.
+func (d *DescriptorAllocator) Pools() int {
+	return d.pools
+}
+
+func (d *DescriptorAllocator) Destroy() {
+	d.Reset()
+	for _, pool := range d.free {
+		vk.DestroyDescriptorPool(d.dev, pool, nil)
+	}
+	d.free = nil
+}
+
+// prepareDescriptors creates the allocator for sets that live as long as
+// the application, and one per swapchain image for transient sets that
+// only the image's next frame uses.
+func (a *Application) prepareDescriptors() {
+	dev := a.Context().Device()
+	if a.descriptors == nil {
+		a.descriptors = newDescriptorAllocator(dev, a.debug, "persistent")
+	}
+	for len(a.frameDescriptors) < len(a.Context().SwapchainImageResources()) {
+		name := fmt.Sprintf("frame %d", len(a.frameDescriptors))
+		a.frameDescriptors = append(a.frameDescriptors, newDescriptorAllocator(dev, a.debug, name))
+	}
+}
+
+// FrameDescriptors returns the transient allocator of an image, reset at
+// the start of each of its frames. Its sets are only valid in command
+// buffers recorded for that frame.
+func (a *Application) FrameDescriptors(imageIdx int) *DescriptorAllocator {
+	return a.frameDescriptors[imageIdx]
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const swapchainExtension = "VK_KHR_swapchain"
+
+// availableDeviceExtensions is the set of extensions gpu supports.
+func availableDeviceExtensions(gpu vk.PhysicalDevice) map[string]bool {
+	var count uint32
+	ret := vk.EnumerateDeviceExtensionProperties(gpu, "", &count, nil)
+	orPanic(as.NewError(ret))
+	props := make([]vk.ExtensionProperties, count)
+	ret = vk.EnumerateDeviceExtensionProperties(gpu, "", &count, props)
+	orPanic(as.NewError(ret))
+
+	extensions := make(map[string]bool, count)
+	for _, p := range props {
+		p.Deref()
+		extensions[vk.ToString(p.ExtensionName[:])] = true
+	}
+	return extensions
+}
+
+// deviceSelectEnv is read by Mesa's device selection layer, which moves
+// the named device to the front of the enumeration. asche always creates
+// its device on the first physical device, so this is the only way to
+// steer it.
+const deviceSelectEnv = "MESA_VK_DEVICE_SELECT"
+
+// gpuPreferences rank device types for each -gpu-prefer value; types not
+// listed come last.
+var gpuPreferences = map[string][]vk.PhysicalDeviceType{
+	"discrete": {vk.PhysicalDeviceTypeDiscreteGpu, vk.PhysicalDeviceTypeIntegratedGpu,
+		vk.PhysicalDeviceTypeVirtualGpu, vk.PhysicalDeviceTypeCpu},
+	"integrated": {vk.PhysicalDeviceTypeIntegratedGpu, vk.PhysicalDeviceTypeDiscreteGpu,
+		vk.PhysicalDeviceTypeVirtualGpu, vk.PhysicalDeviceTypeCpu},
+	"cpu": {vk.PhysicalDeviceTypeCpu, vk.PhysicalDeviceTypeDiscreteGpu,
+		vk.PhysicalDeviceTypeIntegratedGpu, vk.PhysicalDeviceTypeVirtualGpu},
+}
+
+// DeviceInfo describes a physical device and whether the application can
+// run on it.
+type DeviceInfo struct {
+	Index         int
+	Name          string
+	Type          vk.PhysicalDeviceType
+	VendorID      uint32
+	DeviceID      uint32
+	APIVersion    uint32
+	DriverVersion uint32
+	Heaps         []MemoryHeapInfo
+	QueueFamilies []QueueFamilyInfo
+	// Missing lists what the device lacks, empty when it is suitable.
+	Missing []string
+}
+
+type MemoryHeapInfo struct {
+	Size        uint64
+	DeviceLocal bool
+}
+
+type QueueFamilyInfo struct {
+	Flags vk.QueueFlags
+	Count uint32
+}
+
+// Suitable reports whether the device has everything the application
+// needs.
+func (d *DeviceInfo) Suitable() bool {
+	return len(d.Missing) == 0
+}
+
+func deviceTypeName(t vk.PhysicalDeviceType) string {
+	switch t {
+	case vk.PhysicalDeviceTypeDiscreteGpu:
+		return "discrete"
+	case vk.PhysicalDeviceTypeIntegratedGpu:
+		return "integrated"
+	case vk.PhysicalDeviceTypeVirtualGpu:
+		return "virtual"
+	case vk.PhysicalDeviceTypeCpu:
+		return "cpu"
+	}
+	return "other"
+}
+
+func versionString(v uint32) string {
+	return fmt.Sprintf("%d.%d.%d", v>>22, v>>12&0x3ff, v&0xfff)
+}
+
+// driverVersionString decodes a driver version, which vendors encode in
+// their own ways.
+func driverVersionString(vendorID, v uint32) string {
+	switch vendorID {
+	case 0x10de: // NVIDIA
+		return fmt.Sprintf("%d.%d.%d.%d", v>>22, v>>14&0xff, v>>6&0xff, v&0x3f)
+	case 0x8086: // Intel on Windows
+		return fmt.Sprintf("%d.%d", v>>14, v&0x3fff)
+	}
+	return versionString(v)
+}
+
+func queueFlagsString(flags vk.QueueFlags) string {
+	var names []string
+	for _, f := range []struct {
+		bit  vk.QueueFlagBits
+		name string
+	}{
+		{vk.QueueGraphicsBit, "graphics"},
+		{vk.QueueComputeBit, "compute"},
+		{vk.QueueTransferBit, "transfer"},
+		{vk.QueueSparseBindingBit, "sparse"},
+	} {
+		if flags&vk.QueueFlags(f.bit) != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// withInstance runs fn with a Vulkan instance of its own, created with the
+// given extensions and without a window.
+func withInstance(name string, extensions []string, fn func(instance vk.Instance) error) error {
+	names := make([]string, len(extensions))
+	for i, ext := range extensions {
+		names[i] = ext + "\x00"
+	}
+	var instance vk.Instance
+	ret := vk.CreateInstance(&vk.InstanceCreateInfo{
+		SType: vk.StructureTypeInstanceCreateInfo,
+		PApplicationInfo: &vk.ApplicationInfo{
+			SType:            vk.StructureTypeApplicationInfo,
+			ApiVersion:       vk.MakeVersion(1, 0, 0),
+			PApplicationName: name + "\x00",
+			PEngineName:      name + "\x00",
+		},
+		EnabledExtensionCount:   uint32(len(names)),
+		PpEnabledExtensionNames: names,
+	}, nil, &instance)
+	if err := vk.Error(ret); err != nil {
+		return fmt.Errorf("creating an instance: %v", err)
+	}
+	defer vk.DestroyInstance(instance, nil)
+	if err := vk.InitInstance(instance); err != nil {
+		return err
+	}
+	return fn(instance)
+}
+
+func physicalDevices(instance vk.Instance) []vk.PhysicalDevice {
+	var count uint32
+	ret := vk.EnumeratePhysicalDevices(instance, &count, nil)
+	orPanic(as.NewError(ret))
+	gpus := make([]vk.PhysicalDevice, count)
+	ret = vk.EnumeratePhysicalDevices(instance, &count, gpus)
+	orPanic(as.NewError(ret))
+	return gpus[:count]
+}
+
+// describeDevice gathers the properties of gpu and checks it against the
+// requirements of the application: a queue family for both graphics and
+// compute, which the frame is recorded on, the swapchain extension, and
+// the formats the frame renders into.
+func describeDevice(index int, gpu vk.PhysicalDevice) DeviceInfo {
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(gpu, &props)
+	props.Deref()
+	d := DeviceInfo{
+		Index:         index,
+		Name:          vk.ToString(props.DeviceName[:]),
+		Type:          props.DeviceType,
+		VendorID:      props.VendorID,
+		DeviceID:      props.DeviceID,
+		APIVersion:    props.ApiVersion,
+		DriverVersion: props.DriverVersion,
+	}
+
+	var mem vk.PhysicalDeviceMemoryProperties
+	vk.GetPhysicalDeviceMemoryProperties(gpu, &mem)
+	mem.Deref()
+	for i := uint32(0); i < mem.MemoryHeapCount; i++ {
+		heap := mem.MemoryHeaps[i]
+		heap.Deref()
+		d.Heaps = append(d.Heaps, MemoryHeapInfo{
+			Size:        uint64(heap.Size),
+			DeviceLocal: heap.Flags&vk.MemoryHeapFlags(vk.MemoryHeapDeviceLocalBit) != 0,
+		})
+	}
+
+	var familyCount uint32
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &familyCount, nil)
+	families := make([]vk.QueueFamilyProperties, familyCount)
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &familyCount, families)
+	graphicsCompute := vk.QueueFlags(vk.QueueGraphicsBit | vk.QueueComputeBit)
+	hasQueue := false
+	for _, family := range families {
+		family.Deref()
+		d.QueueFamilies = append(d.QueueFamilies, QueueFamilyInfo{
+			Flags: family.QueueFlags,
+			Count: family.QueueCount,
+		})
+		hasQueue = hasQueue || family.QueueFlags&graphicsCompute == graphicsCompute
+	}
+	if !hasQueue {
+		d.Missing = append(d.Missing, "a queue family with both graphics and compute")
+	}
+	if !availableDeviceExtensions(gpu)[swapchainExtension] {
+		d.Missing = append(d.Missing, swapchainExtension)
+	}
+	for _, f := range []struct {
+		format   vk.Format
+		features vk.FormatFeatureFlagBits
+		name     string
+	}{
+		{vk.FormatB8g8r8a8Unorm, vk.FormatFeatureColorAttachmentBit, "B8G8R8A8 color attachments"},
+		{vk.FormatD16Unorm, vk.FormatFeatureDepthStencilAttachmentBit, "D16 depth attachments"},
+	} {
+		var fp vk.FormatProperties
+		vk.GetPhysicalDeviceFormatProperties(gpu, f.format, &fp)
+		fp.Deref()
+		if fp.OptimalTilingFeatures&vk.FormatFeatureFlags(f.features) == 0 {
+			d.Missing = append(d.Missing, f.name)
+		}
+	}
+	return d
+}
+
+// enumerateDevices describes every physical device, in the order the
+// loader enumerates them.
+func enumerateDevices() ([]DeviceInfo, error) {
+	var devices []DeviceInfo
+	err := withInstance("gpu enumeration", nil, func(instance vk.Instance) error {
+		for i, gpu := range physicalDevices(instance) {
+			devices = append(devices, describeDevice(i, gpu))
+		}
+		return nil
+	})
+	if err == nil && len(devices) == 0 {
+		err = errors.New("no Vulkan devices found")
+	}
+	return devices, err
+}
+
+// selectDevice picks the device the configuration asks for: the one at
+// the -gpu index, else the first whose name contains -gpu-name, else the
+// best suitable one by -gpu-prefer. A device that is asked for explicitly
+// but lacks something is an error, with the reason.
+func selectDevice(devices []DeviceInfo, cfg *Config) (*DeviceInfo, error) {
+	reject := func(d *DeviceInfo) error {
+		return fmt.Errorf("device %d (%s) cannot be used, it lacks %s",
+			d.Index, d.Name, strings.Join(d.Missing, ", "))
+	}
+	switch {
+	case cfg.GPU >= 0:
+		if cfg.GPU >= len(devices) {
+			return nil, fmt.Errorf("device %d requested, but only %d are present", cfg.GPU, len(devices))
+		}
+		d := &devices[cfg.GPU]
+		if !d.Suitable() {
+			return nil, reject(d)
+		}
+		return d, nil
+	case cfg.GPUName != "":
+		var rejected *DeviceInfo
+		for i := range devices {
+			d := &devices[i]
+			if !strings.Contains(strings.ToLower(d.Name), strings.ToLower(cfg.GPUName)) {
+				continue
+			}
+			if d.Suitable() {
+				return d, nil
+			}
+			if rejected == nil {
+				rejected = d
+			}
+		}
+		if rejected != nil {
+			return nil, reject(rejected)
+		}
+		return nil, fmt.Errorf("no device name contains %q", cfg.GPUName)
+	}
+
+	rank := func(t vk.PhysicalDeviceType) int {
+		for i, pt := range gpuPreferences[cfg.GPUPrefer] {
+			if pt == t {
+				return i
+			}
+		}
+		return len(gpuPreferences[cfg.GPUPrefer])
+	}
+	var candidates []*DeviceInfo
+	for i := range devices {
+		d := &devices[i]
+		if d.Suitable() {
+			candidates = append(candidates, d)
+		} else {
+			log.Printf("gpu: skipping device %d (%s), it lacks %s",
+				d.Index, d.Name, strings.Join(d.Missing, ", "))
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("no device has what the application needs")
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return rank(candidates[i].Type) < rank(candidates[j].Type)
+	})
+	return candidates[0], nil
+}
+
+// requestDevice asks the loader to enumerate d first, so that asche
+// creates its device on it. A selection already made in the environment
+// is left alone.
+func requestDevice(d *DeviceInfo) {
+	if _, ok := os.LookupEnv(deviceSelectEnv); ok {
+		return
+	}
+	os.Setenv(deviceSelectEnv, fmt.Sprintf("%x:%x", d.VendorID, d.DeviceID))
+}
+
+// printDevices writes a description of every device, marking the one
+// selected.
+func printDevices(w io.Writer, devices []DeviceInfo, selected *DeviceInfo) {
+	for i := range devices {
+		d := &devices[i]
+		mark := " "
+		if d == selected {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%s %d: %s (%s, %04x:%04x)\n", mark, d.Index, d.Name,
+			deviceTypeName(d.Type), d.VendorID, d.DeviceID)
+		fmt.Fprintf(w, "    Vulkan %s, driver %s\n",
+			versionString(d.APIVersion), driverVersionString(d.VendorID, d.DriverVersion))
+		for j, heap := range d.Heaps {
+			kind := "host"
+			if heap.DeviceLocal {
+				kind = "device local"
+			}
+			fmt.Fprintf(w, "    heap %d: %d MiB %s\n", j, heap.Size>>20, kind)
+		}
+		for j, family := range d.QueueFamilies {
+			fmt.Fprintf(w, "    queue family %d: %d x %s\n", j, family.Count, queueFlagsString(family.Flags))
+		}
+		if !d.Suitable() {
+			fmt.Fprintf(w, "    unsuitable, lacks %s\n", strings.Join(d.Missing, ", "))
+		}
+	}
+}
+
+// checkGPU compares the device asche picked with the selected one. The
+// handles of the two instances differ, so devices are matched by their
+// IDs and name; two identical devices cannot be told apart. Another device
+// is an error when it lacks something or when -gpu or -gpu-name asked for
+// the selected one, and a warning otherwise.
+func (a *Application) checkGPU(platform as.Platform, selected *DeviceInfo) error {
+	used := describeDevice(-1, platform.PhysicalDevice())
+	if used.VendorID == selected.VendorID && used.DeviceID == selected.DeviceID && used.Name == selected.Name {
+		log.Printf("gpu: using device %d, %s (%s)", selected.Index, selected.Name, deviceTypeName(selected.Type))
+		return nil
+	}
+	switch {
+	case !used.Suitable():
+		return fmt.Errorf("%s was selected, but the platform picked %s, which lacks %s",
+			selected.Name, used.Name, strings.Join(used.Missing, ", "))
+	case a.config.GPU >= 0 || a.config.GPUName != "":
+		return fmt.Errorf("%s was requested, but the platform picked %s; %s may not be honored "+
+			"without Mesa's device selection layer", selected.Name, used.Name, deviceSelectEnv)
+	}
+	log.Printf("gpu: %s was selected, but the platform picked %s; %s may not be honored "+
+		"without Mesa's device selection layer", selected.Name, used.Name, deviceSelectEnv)
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"sync"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// maxRecordThreads caps the recording goroutines picked by default; past a
+// few the draws of a frame are too few to split further.
+const maxRecordThreads = 4
+
+// Draw is an entry of the main pass's draw list. Consecutive draws of the
+// same group are recorded under one debug label and profiler scope.
+type Draw struct {
+	group  string
+	record func(cmd vk.CommandBuffer, imageIdx int)
+}
+
+var drawGroupLabels = map[string][4]float32{
+	"scene":   labelScene,
+	"overlay": labelOverlay,
+}
+
+// drawList lists the draws of the main pass in order: the cube and the
+// scenePasses, then the overlayPasses on top.
+func (a *Application) drawList() []Draw {
+	draws := []Draw{{group: "scene", record: a.drawCube}}
+	for _, pass := range a.scenePasses {
+		draws = append(draws, Draw{group: "scene", record: pass})
+	}
+	for _, pass := range a.overlayPasses {
+		draws = append(draws, Draw{group: "overlay", record: pass})
+	}
+	return draws
+}
+
+// recordDraws records draws into cmd in order. The viewport and scissor
+// are set first, since secondary command buffers do not inherit them.
+func (a *Application) recordDraws(cmd vk.CommandBuffer, imageIdx int, draws []Draw) {
+	vk.CmdSetViewport(cmd, 0, 1, []vk.Viewport{{
+		Width:    float32(a.width),
+		Height:   float32(a.height),
+		MinDepth: 0.0,
+		MaxDepth: 1.0,
+	}})
+	vk.CmdSetScissor(cmd, 0, 1, []vk.Rect2D{{
+		Extent: vk.Extent2D{
+			Width:  a.width,
+			Height: a.height,
+		},
+	}})
+
+	group := ""
+	for _, d := range draws {
+		if d.group != group {
+			if group != "" {
+				a.profiler.End(cmd, imageIdx)
+				a.debug.End(cmd)
+			}
+			group = d.group
+			a.debug.Begin(cmd, group, drawGroupLabels[group])
+			a.profiler.Begin(cmd, imageIdx, group)
+		}
+		d.record(cmd, imageIdx)
+	}
+	if group != "" {
+		a.profiler.End(cmd, imageIdx)
+		a.debug.End(cmd)
+	}
+}
+
+// recordDrawsParallel splits draws into contiguous runs, one per secondary
+// command buffer of the frame, records the runs on separate goroutines and
+// executes the buffers in order from cmd, inside pass.
+func (a *Application) recordDrawsParallel(cmd vk.CommandBuffer, imageIdx int, pass *GraphPass, draws []Draw) {
+	secondaries := a.frames[imageIdx].secondaries
+	if len(draws) < len(secondaries) {
+		secondaries = secondaries[:len(draws)]
+	}
+	inheritance := pass.Inheritance(imageIdx)
+
+	var wg sync.WaitGroup
+	for i, sec := range secondaries {
+		run := draws[len(draws)*i/len(secondaries) : len(draws)*(i+1)/len(secondaries)]
+		wg.Add(1)
+		go func(sec vk.CommandBuffer, run []Draw) {
+			defer wg.Done()
+			ret := vk.BeginCommandBuffer(sec, &vk.CommandBufferBeginInfo{
+				SType: vk.StructureTypeCommandBufferBeginInfo,
+				Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit |
+					vk.CommandBufferUsageRenderPassContinueBit),
+				PInheritanceInfo: []vk.CommandBufferInheritanceInfo{inheritance},
+			})
+			orPanic(as.NewError(ret))
+			a.recordDraws(sec, imageIdx, run)
+			ret = vk.EndCommandBuffer(sec)
+			orPanic(as.NewError(ret))
+		}(sec, run)
+	}
+	wg.Wait()
+	vk.CmdExecuteCommands(cmd, uint32(len(secondaries)), secondaries)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// Frame holds what recording a frame of one swapchain image takes: a
+// transient command pool that is reset as a whole before every frame of
+// the image, the command buffer recorded from it, and the fence the
+// buffer's submission signals. When draws are recorded in parallel, every
+// recording goroutine has a pool of its own too, as pools cannot be used
+// from several goroutines at once, with one secondary command buffer.
+type Frame struct {
+	pool  vk.CommandPool
+	cmd   vk.CommandBuffer
+	fence vk.Fence
+
+	// capture says what becomes of the output the frame copies into
+	// readback, which is read when the image's frame is next waited for.
+	capture  frameCapture
+	readback *Buffer
+
+	workerPools []vk.CommandPool
+	secondaries []vk.CommandBuffer
+}
+
+func (a *Application) createFramePool(name string) vk.CommandPool {
+	var pool vk.CommandPool
+	ret := vk.CreateCommandPool(a.Context().Device(), &vk.CommandPoolCreateInfo{
+		SType:            vk.StructureTypeCommandPoolCreateInfo,
+		Flags:            vk.CommandPoolCreateFlags(vk.CommandPoolCreateTransientBit),
+		QueueFamilyIndex: a.Context().Platform().GraphicsQueueFamilyIndex(),
+	}, nil, &pool)
+	orPanic(as.NewError(ret))
+	a.debug.Name(pool, name)
+	return pool
+}
+
+func (a *Application) allocateCommandBuffer(pool vk.CommandPool, level vk.CommandBufferLevel, name string) vk.CommandBuffer {
+	cmds := make([]vk.CommandBuffer, 1)
+	ret := vk.AllocateCommandBuffers(a.Context().Device(), &vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        pool,
+		Level:              level,
+		CommandBufferCount: 1,
+	}, cmds)
+	orPanic(as.NewError(ret))
+	a.debug.Name(cmds[0], name)
+	return cmds[0]
+}
+
+// prepareFrames creates the frames of swapchain images that do not have
+// one yet, and records the commands asche submits for each swapchain
+// image. Those cannot be re-recorded, as asche allocates them from a pool
+// without the reset flag, so they only draw the output of the frame into
+// the swapchain image once it has been acquired.
+func (a *Application) prepareFrames() {
+	dev := a.Context().Device()
+	a.preparePresentPass()
+
+	swapchainImageResources := a.Context().SwapchainImageResources()
+	for len(a.frames) < len(swapchainImageResources) {
+		i := len(a.frames)
+		f := &Frame{}
+		f.pool = a.createFramePool(fmt.Sprintf("frame command pool %d", i))
+		f.cmd = a.allocateCommandBuffer(f.pool, vk.CommandBufferLevelPrimary,
+			fmt.Sprintf("frame commands %d", i))
+		if threads := a.config.recordThreads(); threads > 1 {
+			for w := 0; w < threads; w++ {
+				pool := a.createFramePool(fmt.Sprintf("frame %d worker %d command pool", i, w))
+				f.workerPools = append(f.workerPools, pool)
+				f.secondaries = append(f.secondaries, a.allocateCommandBuffer(pool,
+					vk.CommandBufferLevelSecondary, fmt.Sprintf("frame %d worker %d draws", i, w)))
+			}
+		}
+
+		// Signaled, as if the frame before the first had finished.
+		ret := vk.CreateFence(dev, &vk.FenceCreateInfo{
+			SType: vk.StructureTypeFenceCreateInfo,
+			Flags: vk.FenceCreateFlags(vk.FenceCreateSignaledBit),
+		}, nil, &f.fence)
+		orPanic(as.NewError(ret))
+		a.debug.Name(f.fence, fmt.Sprintf("frame fence %d", i))
+		a.frames = append(a.frames, f)
+	}
+
+	for i, res := range swapchainImageResources {
+		a.debug.Name(res.CommandBuffer(), fmt.Sprintf("present commands %d", i))
+		a.recordPresentCommands(res, i)
+	}
+}
+
+// waitFrame waits until the previous frame of a swapchain image has run,
+// reads back what it captured, then resets its command pools, which frees
+// everything recorded for it.
+func (a *Application) waitFrame(imageIdx int) {
+	dev := a.Context().Device()
+	f := a.frames[imageIdx]
+	if f.capture.wanted() {
+		a.readCaptures(imageIdx)
+	}
+	ret := vk.WaitForFences(dev, 1, []vk.Fence{f.fence}, vk.True, vk.MaxUint64)
+	orPanic(as.NewError(ret))
+	for _, pool := range append([]vk.CommandPool{f.pool}, f.workerPools...) {
+		ret = vk.ResetCommandPool(dev, pool, 0)
+		orPanic(as.NewError(ret))
+	}
+}
+
+// submitFrame records the frame of a swapchain image and submits it. It
+// goes to the graphics queue ahead of the present commands asche submits
+// for the image, which only draw its output once rendering is done.
+func (a *Application) submitFrame(imageIdx int) {
+	dev := a.Context().Device()
+	f := a.frames[imageIdx]
+	f.capture = a.nextCapture
+	f.capture.width, f.capture.height = a.width, a.height
+	if f.capture.path == "" {
+		f.capture.path, a.screenshotPending = a.screenshotPending, ""
+	}
+	a.drawBuildCommandBuffer(f.cmd, imageIdx)
+
+	ret := vk.ResetFences(dev, 1, []vk.Fence{f.fence})
+	orPanic(as.NewError(ret))
+	ret = vk.QueueSubmit(a.Context().Platform().GraphicsQueue(), 1, []vk.SubmitInfo{{
+		SType:              vk.StructureTypeSubmitInfo,
+		CommandBufferCount: 1,
+		PCommandBuffers:    []vk.CommandBuffer{f.cmd},
+	}}, f.fence)
+	orPanic(as.NewError(ret))
+	a.profiler.Submitted(imageIdx)
+	if f.capture.wanted() {
+		a.captureQueue = append(a.captureQueue, imageIdx)
+	}
+}
+
+func (f *Frame) Destroy(dev vk.Device) {
+	if f.readback != nil {
+		f.readback.Destroy(dev)
+	}
+	vk.DestroyFence(dev, f.fence, nil)
+	vk.DestroyCommandPool(dev, f.pool, nil)
+	for _, pool := range f.workerPools {
+		vk.DestroyCommandPool(dev, pool, nil)
+	}
+}
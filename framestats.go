@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// frameStatsLimit caps the recorded frames, about four hours at 60 FPS.
+const frameStatsLimit = 1 << 20
+
+// FrameTiming holds the CPU-side durations of one frame in milliseconds.
+// asche submits the command buffer and queues it for presentation in the
+// same call, so Present covers both.
+type FrameTiming struct {
+	Frame int `json:"frame"`
+	// CPU is the whole iteration of the main loop, without the time spent
+	// waiting for the frame rate cap.
+	CPU float64 `json:"cpu"`
+	// Acquire is the wait for a free swapchain image.
+	Acquire float64 `json:"acquire"`
+	// Update is the application's per-frame work in VulkanContextInvalidate.
+	Update  float64 `json:"update"`
+	Present float64 `json:"present"`
+}
+
+// TimingSummary describes the distribution of one FrameTiming column.
+type TimingSummary struct {
+	Min float64 `json:"min"`
+	Avg float64 `json:"avg"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// FrameStats collects FrameTiming for every frame of the session.
+type FrameStats struct {
+	frames  []FrameTiming
+	dropped int
+}
+
+var frameStatsColumns = []string{"cpu", "acquire", "update", "present"}
+
+func (t FrameTiming) column(name string) float64 {
+	switch name {
+	case "cpu":
+		return t.CPU
+	case "acquire":
+		return t.Acquire
+	case "update":
+		return t.Update
+	case "present":
+		return t.Present
+	}
+	panic("unknown frame timing column " + name)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (s *FrameStats) add(t FrameTiming) {
+	if len(s.frames) >= frameStatsLimit {
+		s.dropped++
+		return
+	}
+	t.Frame = len(s.frames)
+	s.frames = append(s.frames, t)
+}
+
+// percentile expects sorted values and uses the nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// Summary summarizes every column; it is empty before the first frame.
+func (s *FrameStats) Summary() map[string]TimingSummary {
+	summary := make(map[string]TimingSummary, len(frameStatsColumns))
+	if len(s.frames) == 0 {
+		return summary
+	}
+	values := make([]float64, len(s.frames))
+	for _, name := range frameStatsColumns {
+		var sum float64
+		for i, f := range s.frames {
+			values[i] = f.column(name)
+			sum += values[i]
+		}
+		sort.Float64s(values)
+		summary[name] = TimingSummary{
+			Min: values[0],
+			Avg: sum / float64(len(values)),
+			P95: percentile(values, 95),
+			P99: percentile(values, 99),
+			Max: values[len(values)-1],
+		}
+	}
+	return summary
+}
+
+func (s *FrameStats) logSummary() {
+	if len(s.frames) == 0 {
+		return
+	}
+	summary := s.Summary()
+	log.Printf("frame timing over %d frames (ms):", len(s.frames))
+	log.Printf("  %-8s %8s %8s %8s %8s %8s", "", "min", "avg", "p95", "p99", "max")
+	for _, name := range frameStatsColumns {
+		t := summary[name]
+		log.Printf("  %-8s %8.3f %8.3f %8.3f %8.3f %8.3f", name, t.Min, t.Avg, t.P95, t.P99, t.Max)
+	}
+	if s.dropped > 0 {
+		log.Printf("  %d frames past the first %d were not recorded", s.dropped, frameStatsLimit)
+	}
+}
+
+// Export writes every frame to path, as CSV or JSON depending on its
+// extension. The JSON form also carries the summary.
+func (s *FrameStats) Export(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(path) {
+	case ".csv":
+		err = s.writeCSV(f)
+	case ".json":
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			Summary map[string]TimingSummary `json:"summary"`
+			Frames  []FrameTiming            `json:"frames"`
+		}{s.Summary(), s.frames})
+	default:
+		err = fmt.Errorf("unknown stats format %q, want .csv or .json", filepath.Ext(path))
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *FrameStats) writeCSV(f *os.File) error {
+	w := csv.NewWriter(f)
+	w.Write(append([]string{"frame"}, frameStatsColumns...))
+	row := make([]string, len(frameStatsColumns)+1)
+	for _, t := range s.frames {
+		row[0] = strconv.Itoa(t.Frame)
+		for i, name := range frameStatsColumns {
+			row[i+1] = strconv.FormatFloat(t.column(name), 'f', 4, 64)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
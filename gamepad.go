@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vulkan-go/glfw/v3.3/glfw"
+)
+
+// gamepadDeadzone is the stick deflection below which an axis reads zero;
+// beyond it the range is rescaled so output still starts at zero.
+const gamepadDeadzone = 0.2
+
+// GamepadButton and GamepadAxis are the controls of the standard gamepad
+// layout, named after an Xbox controller. GLFW only reports raw joystick
+// buttons and axes, which a mapping translates into these.
+type GamepadButton int
+
+const (
+	ButtonA GamepadButton = iota
+	ButtonB
+	ButtonX
+	ButtonY
+	ButtonLeftBumper
+	ButtonRightBumper
+	ButtonBack
+	ButtonStart
+	ButtonGuide
+	ButtonLeftThumb
+	ButtonRightThumb
+	ButtonDpadUp
+	ButtonDpadRight
+	ButtonDpadDown
+	ButtonDpadLeft
+	gamepadButtonCount
+)
+
+type GamepadAxis int
+
+const (
+	AxisLeftX GamepadAxis = iota
+	AxisLeftY
+	AxisRightX
+	AxisRightY
+	AxisLeftTrigger
+	AxisRightTrigger
+	gamepadAxisCount
+)
+
+// The names SDL_GameControllerDB mappings use for the standard controls.
+var gamepadButtonNames = map[string]GamepadButton{
+	"a":             ButtonA,
+	"b":             ButtonB,
+	"x":             ButtonX,
+	"y":             ButtonY,
+	"leftshoulder":  ButtonLeftBumper,
+	"rightshoulder": ButtonRightBumper,
+	"back":          ButtonBack,
+	"start":         ButtonStart,
+	"guide":         ButtonGuide,
+	"leftstick":     ButtonLeftThumb,
+	"rightstick":    ButtonRightThumb,
+	"dpup":          ButtonDpadUp,
+	"dpright":       ButtonDpadRight,
+	"dpdown":        ButtonDpadDown,
+	"dpleft":        ButtonDpadLeft,
+}
+
+var gamepadAxisNames = map[string]GamepadAxis{
+	"leftx":        AxisLeftX,
+	"lefty":        AxisLeftY,
+	"rightx":       AxisRightX,
+	"righty":       AxisRightY,
+	"lefttrigger":  AxisLeftTrigger,
+	"righttrigger": AxisRightTrigger,
+}
+
+// gamepadSource is the raw joystick input a standard control reads: a
+// button, or an axis, possibly only one half of it or inverted.
+type gamepadSource struct {
+	axis  bool
+	index int
+	// half is 1 or -1 to read only that half of the axis, as [0, 1].
+	half   float32
+	invert bool
+}
+
+func (s gamepadSource) read(axes []float32, buttons []byte) float32 {
+	if !s.axis {
+		if s.index < len(buttons) && glfw.Action(buttons[s.index]) == glfw.Press {
+			return 1
+		}
+		return 0
+	}
+	if s.index >= len(axes) {
+		return 0
+	}
+	v := axes[s.index]
+	if s.half != 0 {
+		v = clamp(v*s.half, 0, 1)
+	}
+	if s.invert {
+		v = -v
+	}
+	return v
+}
+
+// gamepadMapping is one controller's line of an SDL_GameControllerDB file.
+type gamepadMapping struct {
+	name    string
+	buttons map[GamepadButton]gamepadSource
+	axes    map[GamepadAxis]gamepadSource
+}
+
+// gamepadState is the standard controls of a gamepad at one point in time.
+// Triggers are stored in [0, 1], sticks in [-1, 1] with +Y pointing down.
+type gamepadState struct {
+	buttons [gamepadButtonCount]bool
+	axes    [gamepadAxisCount]float32
+}
+
+func (m *gamepadMapping) sample(axes []float32, buttons []byte) gamepadState {
+	var s gamepadState
+	for b, src := range m.buttons {
+		s.buttons[b] = src.read(axes, buttons) >= 0.5
+	}
+	for a, src := range m.axes {
+		v := src.read(axes, buttons)
+		// Trigger axes rest at -1; buttons and half axes already
+		// read as [0, 1].
+		if (a == AxisLeftTrigger || a == AxisRightTrigger) && src.axis && src.half == 0 {
+			v = (v + 1) / 2
+		}
+		s.axes[a] = v
+	}
+	return s
+}
+
+// gamepadPlatform is how mapping files name the running platform.
+func gamepadPlatform() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "Windows"
+	case "darwin":
+		return "Mac OS X"
+	default:
+		return "Linux"
+	}
+}
+
+// parseGamepadMappings reads mappings in the SDL_GameControllerDB format,
+// one "guid,name,control:source,..." per line, keeping those for this
+// platform. This GLFW cannot report joystick GUIDs, so mappings are keyed
+// by name, which must be the name GLFW reports. Hat sources and controls
+// outside the standard layout are skipped.
+func parseGamepadMappings(r io.Reader) (map[string]*gamepadMapping, error) {
+	mappings := make(map[string]*gamepadMapping)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) < 2 || fields[1] == "" {
+			return nil, fmt.Errorf("line %d: want guid,name,mappings", line)
+		}
+		m := &gamepadMapping{
+			name:    fields[1],
+			buttons: make(map[GamepadButton]gamepadSource),
+			axes:    make(map[GamepadAxis]gamepadSource),
+		}
+		platform := ""
+		for _, field := range fields[2:] {
+			if field == "" {
+				continue
+			}
+			kv := strings.SplitN(field, ":", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("line %d: %q is not control:source", line, field)
+			}
+			control, source := kv[0], kv[1]
+			if control == "platform" {
+				platform = source
+				continue
+			}
+			button, isButton := gamepadButtonNames[control]
+			axis, isAxis := gamepadAxisNames[control]
+			if !isButton && !isAxis || strings.HasPrefix(source, "h") {
+				continue
+			}
+			src, err := parseGamepadSource(source)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %v", line, control, err)
+			}
+			if isButton {
+				m.buttons[button] = src
+			} else {
+				m.axes[axis] = src
+			}
+		}
+		if platform == "" || platform == gamepadPlatform() {
+			mappings[m.name] = m
+		}
+	}
+	return mappings, scanner.Err()
+}
+
+// parseGamepadSource parses "b3", "a1", "a1~", "+a2" or "-a2".
+func parseGamepadSource(s string) (gamepadSource, error) {
+	var src gamepadSource
+	switch {
+	case strings.HasPrefix(s, "+"):
+		src.half, s = 1, s[1:]
+	case strings.HasPrefix(s, "-"):
+		src.half, s = -1, s[1:]
+	}
+	if strings.HasSuffix(s, "~") {
+		src.invert, s = true, s[:len(s)-1]
+	}
+	switch {
+	case strings.HasPrefix(s, "a"):
+		src.axis = true
+	case strings.HasPrefix(s, "b") && src.half == 0 && !src.invert:
+	default:
+		return src, fmt.Errorf("unsupported source %q", s)
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil || n < 0 {
+		return src, fmt.Errorf("bad source index in %q", s)
+	}
+	src.index = n
+	return src, nil
+}
+
+// gamepad is a joystick with a mapping to the standard layout. Its state is
+// sampled once per frame so every action sees the same values.
+type gamepad struct {
+	joy     glfw.Joystick
+	mapping *gamepadMapping
+	state   gamepadState
+	prev    gamepadState
+}
+
+// GamepadEvent records a controller being plugged in or removed.
+type GamepadEvent struct {
+	Name      string
+	Connected bool
+}
+
+// attachGamepads picks up controllers that are already connected and
+// watches for hotplugging. Joysticks without a mapping are ignored; mappings
+// are loaded with LoadGamepadMappings.
+func (in *Input) attachGamepads() {
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if glfw.JoystickPresent(joy) {
+			in.connectGamepad(joy)
+		}
+	}
+	glfw.SetJoystickCallback(func(joy, event int) {
+		switch glfw.MonitorEvent(event) {
+		case glfw.Connected:
+			in.connectGamepad(glfw.Joystick(joy))
+		case glfw.Disconnected:
+			in.disconnectGamepad(glfw.Joystick(joy))
+		}
+	})
+}
+
+func (in *Input) connectGamepad(joy glfw.Joystick) {
+	name := glfw.GetJoystickName(joy)
+	mapping, ok := in.gamepadMappings[name]
+	if !ok {
+		log.Printf("joystick %q has no gamepad mapping, ignoring it", name)
+		return
+	}
+	in.gamepads[joy] = &gamepad{joy: joy, mapping: mapping}
+	in.gamepadEvents = append(in.gamepadEvents, GamepadEvent{Name: name, Connected: true})
+	log.Printf("gamepad connected: %s", name)
+}
+
+func (in *Input) disconnectGamepad(joy glfw.Joystick) {
+	pad, ok := in.gamepads[joy]
+	if !ok {
+		return
+	}
+	delete(in.gamepads, joy)
+	in.gamepadEvents = append(in.gamepadEvents, GamepadEvent{Name: pad.mapping.name, Connected: false})
+	log.Printf("gamepad disconnected: %s", pad.mapping.name)
+}
+
+// LoadGamepadMappings adds the mappings of an SDL_GameControllerDB-style
+// file; see parseGamepadMappings.
+func (in *Input) LoadGamepadMappings(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	mappings, err := parseGamepadMappings(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	for name, m := range mappings {
+		in.gamepadMappings[name] = m
+	}
+	log.Printf("gamepad: %d mappings for %s loaded from %s", len(mappings), gamepadPlatform(), path)
+	// A new mapping can turn an already connected joystick into a gamepad.
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if _, ok := in.gamepads[joy]; !ok && glfw.JoystickPresent(joy) {
+			if _, ok := mappings[glfw.GetJoystickName(joy)]; ok {
+				in.connectGamepad(joy)
+			}
+		}
+	}
+	return nil
+}
+
+func (in *Input) pollGamepads() {
+	for _, pad := range in.gamepads {
+		pad.prev = pad.state
+		pad.state = pad.mapping.sample(glfw.GetJoystickAxes(pad.joy), glfw.GetJoystickButtons(pad.joy))
+	}
+}
+
+// Gamepads returns the names of the connected gamepads, sorted.
+func (in *Input) Gamepads() []string {
+	var names []string
+	for _, pad := range in.gamepads {
+		names = append(names, pad.mapping.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GamepadEvents returns the connects and disconnects seen this frame.
+func (in *Input) GamepadEvents() []GamepadEvent {
+	return in.gamepadEvents
+}
+
+func (p *gamepad) value(b Binding) (now, prev float32) {
+	switch b.kind {
+	case bindingGamepadButton:
+		return boolValue(p.state.buttons[b.padButton]), boolValue(p.prev.buttons[b.padButton])
+	case bindingGamepadAxis:
+		return axisValue(p.state, b.padAxis, b.sign), axisValue(p.prev, b.padAxis, b.sign)
+	}
+	return 0, 0
+}
+
+// axisValue returns the deflection of one half of an axis in [0, 1].
+func axisValue(s gamepadState, axis GamepadAxis, sign float32) float32 {
+	v := s.axes[axis] * sign
+	if v <= gamepadDeadzone {
+		return 0
+	}
+	return clamp((v-gamepadDeadzone)/(1-gamepadDeadzone), 0, 1)
+}
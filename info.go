@@ -0,0 +1,394 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	as "github.com/vulkan-go/asche"
+	"github.com/vulkan-go/glfw/v3.3/glfw"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./texfile"
+)
+
+// InfoReport is what -info prints: what the Vulkan installation and every
+// device offer, for attaching to bug reports.
+type InfoReport struct {
+	InstanceExtensions []ExtensionReport `json:"instanceExtensions"`
+	Layers             []LayerReport     `json:"layers"`
+	// SurfaceError says why surface capabilities are missing, when no
+	// window could be created.
+	SurfaceError string         `json:"surfaceError,omitempty"`
+	Devices      []DeviceReport `json:"devices"`
+}
+
+type ExtensionReport struct {
+	Name    string `json:"name"`
+	Version uint32 `json:"version"`
+}
+
+type LayerReport struct {
+	Name                  string `json:"name"`
+	Description           string `json:"description"`
+	SpecVersion           string `json:"specVersion"`
+	ImplementationVersion uint32 `json:"implementationVersion"`
+}
+
+type DeviceReport struct {
+	Index         int                    `json:"index"`
+	Name          string                 `json:"name"`
+	Type          string                 `json:"type"`
+	VendorID      uint32                 `json:"vendorId"`
+	DeviceID      uint32                 `json:"deviceId"`
+	APIVersion    string                 `json:"apiVersion"`
+	DriverVersion string                 `json:"driverVersion"`
+	Suitable      bool                   `json:"suitable"`
+	Missing       []string               `json:"missing,omitempty"`
+	MemoryHeaps   []HeapReport           `json:"memoryHeaps"`
+	QueueFamilies []QueueFamilyReport    `json:"queueFamilies"`
+	Extensions    []ExtensionReport      `json:"extensions"`
+	Limits        map[string]interface{} `json:"limits"`
+	Features      map[string]interface{} `json:"features"`
+	Formats       []FormatReport         `json:"formats"`
+	Surface       *SurfaceReport         `json:"surface,omitempty"`
+}
+
+type HeapReport struct {
+	Size        uint64 `json:"size"`
+	DeviceLocal bool   `json:"deviceLocal"`
+}
+
+type QueueFamilyReport struct {
+	Flags string `json:"flags"`
+	Count uint32 `json:"count"`
+	// Present is whether the family can present to the report's surface,
+	// nil without one.
+	Present *bool `json:"present,omitempty"`
+}
+
+// FormatReport lists the features of a format with each tiling, and as a
+// buffer format.
+type FormatReport struct {
+	Format  string   `json:"format"`
+	Value   int32    `json:"value"`
+	Optimal []string `json:"optimal"`
+	Linear  []string `json:"linear"`
+	Buffer  []string `json:"buffer"`
+}
+
+type SurfaceReport struct {
+	MinImageCount           uint32          `json:"minImageCount"`
+	MaxImageCount           uint32          `json:"maxImageCount"`
+	CurrentExtent           [2]uint32       `json:"currentExtent"`
+	MinImageExtent          [2]uint32       `json:"minImageExtent"`
+	MaxImageExtent          [2]uint32       `json:"maxImageExtent"`
+	SupportedUsage          []string        `json:"supportedUsage"`
+	Formats                 []SurfaceFormat `json:"formats"`
+	PresentModes            []string        `json:"presentModes"`
+	MaxImageArrayLayers     uint32          `json:"maxImageArrayLayers"`
+	SupportedCompositeAlpha uint32          `json:"supportedCompositeAlpha"`
+	SupportedTransforms     uint32          `json:"supportedTransforms"`
+}
+
+type SurfaceFormat struct {
+	Format     string `json:"format"`
+	ColorSpace int32  `json:"colorSpace"`
+}
+
+// infoFormats are the formats the application renders with, besides the
+// texture formats of texfile.
+var infoFormats = []struct {
+	format vk.Format
+	name   string
+}{
+	{vk.FormatD16Unorm, "D16"},
+	{vk.FormatX8D24UnormPack32, "X8 D24"},
+	{vk.FormatD32Sfloat, "D32 float"},
+	{vk.FormatS8Uint, "S8"},
+	{vk.FormatD16UnormS8Uint, "D16 S8"},
+	{vk.FormatD24UnormS8Uint, "D24 S8"},
+	{vk.FormatD32SfloatS8Uint, "D32 float S8"},
+	{vk.FormatR8Unorm, "R8"},
+	{vk.FormatR32g32Sfloat, "RG32 float"},
+}
+
+var formatFeatureNames = []struct {
+	bit  vk.FormatFeatureFlagBits
+	name string
+}{
+	{vk.FormatFeatureSampledImageBit, "sampled"},
+	{vk.FormatFeatureSampledImageFilterLinearBit, "sampledFilterLinear"},
+	{vk.FormatFeatureStorageImageBit, "storage"},
+	{vk.FormatFeatureStorageImageAtomicBit, "storageAtomic"},
+	{vk.FormatFeatureUniformTexelBufferBit, "uniformTexelBuffer"},
+	{vk.FormatFeatureStorageTexelBufferBit, "storageTexelBuffer"},
+	{vk.FormatFeatureVertexBufferBit, "vertexBuffer"},
+	{vk.FormatFeatureColorAttachmentBit, "colorAttachment"},
+	{vk.FormatFeatureColorAttachmentBlendBit, "colorAttachmentBlend"},
+	{vk.FormatFeatureDepthStencilAttachmentBit, "depthStencilAttachment"},
+	{vk.FormatFeatureBlitSrcBit, "blitSrc"},
+	{vk.FormatFeatureBlitDstBit, "blitDst"},
+}
+
+var imageUsageNames = []struct {
+	bit  vk.ImageUsageFlagBits
+	name string
+}{
+	{vk.ImageUsageTransferSrcBit, "transferSrc"},
+	{vk.ImageUsageTransferDstBit, "transferDst"},
+	{vk.ImageUsageSampledBit, "sampled"},
+	{vk.ImageUsageStorageBit, "storage"},
+	{vk.ImageUsageColorAttachmentBit, "colorAttachment"},
+	{vk.ImageUsageDepthStencilAttachmentBit, "depthStencilAttachment"},
+	{vk.ImageUsageTransientAttachmentBit, "transientAttachment"},
+	{vk.ImageUsageInputAttachmentBit, "inputAttachment"},
+}
+
+func formatFeatures(flags vk.FormatFeatureFlags) []string {
+	names := []string{}
+	for _, f := range formatFeatureNames {
+		if flags&vk.FormatFeatureFlags(f.bit) != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// camelFields maps the exported fields of a struct of the bindings to
+// their values, named in the camel case of the report. Booleans become
+// true and false rather than 1 and 0.
+func camelFields(v interface{}) map[string]interface{} {
+	rv := reflect.ValueOf(v)
+	fields := make(map[string]interface{})
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		value := rv.Field(i).Interface()
+		if b, ok := value.(vk.Bool32); ok {
+			value = b == vk.True
+		}
+		fields[strings.ToLower(f.Name[:1])+f.Name[1:]] = value
+	}
+	return fields
+}
+
+func instanceExtensionReports() []ExtensionReport {
+	var count uint32
+	ret := vk.EnumerateInstanceExtensionProperties("", &count, nil)
+	orPanic(as.NewError(ret))
+	props := make([]vk.ExtensionProperties, count)
+	ret = vk.EnumerateInstanceExtensionProperties("", &count, props)
+	orPanic(as.NewError(ret))
+	return extensionReports(props[:count])
+}
+
+func extensionReports(props []vk.ExtensionProperties) []ExtensionReport {
+	reports := []ExtensionReport{}
+	for _, p := range props {
+		p.Deref()
+		reports = append(reports, ExtensionReport{
+			Name:    vk.ToString(p.ExtensionName[:]),
+			Version: p.SpecVersion,
+		})
+	}
+	return reports
+}
+
+func layerReports() []LayerReport {
+	var count uint32
+	ret := vk.EnumerateInstanceLayerProperties(&count, nil)
+	orPanic(as.NewError(ret))
+	props := make([]vk.LayerProperties, count)
+	ret = vk.EnumerateInstanceLayerProperties(&count, props)
+	orPanic(as.NewError(ret))
+	reports := []LayerReport{}
+	for _, p := range props[:count] {
+		p.Deref()
+		reports = append(reports, LayerReport{
+			Name:                  vk.ToString(p.LayerName[:]),
+			Description:           vk.ToString(p.Description[:]),
+			SpecVersion:           versionString(p.SpecVersion),
+			ImplementationVersion: p.ImplementationVersion,
+		})
+	}
+	return reports
+}
+
+func formatReport(gpu vk.PhysicalDevice, format vk.Format, name string) FormatReport {
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(gpu, format, &props)
+	props.Deref()
+	return FormatReport{
+		Format:  name,
+		Value:   int32(format),
+		Optimal: formatFeatures(props.OptimalTilingFeatures),
+		Linear:  formatFeatures(props.LinearTilingFeatures),
+		Buffer:  formatFeatures(props.BufferFeatures),
+	}
+}
+
+func surfaceReport(gpu vk.PhysicalDevice, surface vk.Surface) *SurfaceReport {
+	var caps vk.SurfaceCapabilities
+	ret := vk.GetPhysicalDeviceSurfaceCapabilities(gpu, surface, &caps)
+	orPanic(as.NewError(ret))
+	caps.Deref()
+	caps.CurrentExtent.Deref()
+	caps.MinImageExtent.Deref()
+	caps.MaxImageExtent.Deref()
+	r := &SurfaceReport{
+		MinImageCount:           caps.MinImageCount,
+		MaxImageCount:           caps.MaxImageCount,
+		CurrentExtent:           [2]uint32{caps.CurrentExtent.Width, caps.CurrentExtent.Height},
+		MinImageExtent:          [2]uint32{caps.MinImageExtent.Width, caps.MinImageExtent.Height},
+		MaxImageExtent:          [2]uint32{caps.MaxImageExtent.Width, caps.MaxImageExtent.Height},
+		SupportedUsage:          []string{},
+		MaxImageArrayLayers:     caps.MaxImageArrayLayers,
+		SupportedCompositeAlpha: uint32(caps.SupportedCompositeAlpha),
+		SupportedTransforms:     uint32(caps.SupportedTransforms),
+	}
+	for _, u := range imageUsageNames {
+		if caps.SupportedUsageFlags&vk.ImageUsageFlags(u.bit) != 0 {
+			r.SupportedUsage = append(r.SupportedUsage, u.name)
+		}
+	}
+
+	var count uint32
+	ret = vk.GetPhysicalDeviceSurfaceFormats(gpu, surface, &count, nil)
+	orPanic(as.NewError(ret))
+	formats := make([]vk.SurfaceFormat, count)
+	ret = vk.GetPhysicalDeviceSurfaceFormats(gpu, surface, &count, formats)
+	orPanic(as.NewError(ret))
+	for _, f := range formats[:count] {
+		f.Deref()
+		r.Formats = append(r.Formats, SurfaceFormat{
+			Format:     texfile.FormatName(f.Format),
+			ColorSpace: int32(f.ColorSpace),
+		})
+	}
+
+	ret = vk.GetPhysicalDeviceSurfacePresentModes(gpu, surface, &count, nil)
+	orPanic(as.NewError(ret))
+	modes := make([]vk.PresentMode, count)
+	ret = vk.GetPhysicalDeviceSurfacePresentModes(gpu, surface, &count, modes)
+	orPanic(as.NewError(ret))
+	for _, mode := range modes[:count] {
+		r.PresentModes = append(r.PresentModes, presentModeName(mode))
+	}
+	return r
+}
+
+func deviceReport(index int, gpu vk.PhysicalDevice, surface vk.Surface) DeviceReport {
+	d := describeDevice(index, gpu)
+	r := DeviceReport{
+		Index:         d.Index,
+		Name:          d.Name,
+		Type:          deviceTypeName(d.Type),
+		VendorID:      d.VendorID,
+		DeviceID:      d.DeviceID,
+		APIVersion:    versionString(d.APIVersion),
+		DriverVersion: driverVersionString(d.VendorID, d.DriverVersion),
+		Suitable:      d.Suitable(),
+		Missing:       d.Missing,
+	}
+	for _, heap := range d.Heaps {
+		r.MemoryHeaps = append(r.MemoryHeaps, HeapReport{Size: heap.Size, DeviceLocal: heap.DeviceLocal})
+	}
+	for i, family := range d.QueueFamilies {
+		fr := QueueFamilyReport{Flags: queueFlagsString(family.Flags), Count: family.Count}
+		if surface != vk.NullSurface {
+			var supported vk.Bool32
+			vk.GetPhysicalDeviceSurfaceSupport(gpu, uint32(i), surface, &supported)
+			present := supported == vk.True
+			fr.Present = &present
+		}
+		r.QueueFamilies = append(r.QueueFamilies, fr)
+	}
+
+	var count uint32
+	ret := vk.EnumerateDeviceExtensionProperties(gpu, "", &count, nil)
+	orPanic(as.NewError(ret))
+	exts := make([]vk.ExtensionProperties, count)
+	ret = vk.EnumerateDeviceExtensionProperties(gpu, "", &count, exts)
+	orPanic(as.NewError(ret))
+	r.Extensions = extensionReports(exts[:count])
+
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(gpu, &props)
+	props.Deref()
+	props.Limits.Deref()
+	r.Limits = camelFields(props.Limits)
+	var features vk.PhysicalDeviceFeatures
+	vk.GetPhysicalDeviceFeatures(gpu, &features)
+	features.Deref()
+	r.Features = camelFields(features)
+
+	for _, f := range infoFormats {
+		r.Formats = append(r.Formats, formatReport(gpu, f.format, f.name))
+	}
+	for _, f := range texfile.Formats() {
+		r.Formats = append(r.Formats, formatReport(gpu, f, texfile.FormatName(f)))
+	}
+	if surface != vk.NullSurface {
+		r.Surface = surfaceReport(gpu, surface)
+	}
+	return r
+}
+
+// printInfo writes the report as JSON. Surface capabilities need a window
+// to create a surface for; it stays hidden, and without a display the
+// report is written without them.
+func printInfo(w io.Writer) error {
+	report := InfoReport{
+		InstanceExtensions: instanceExtensionReports(),
+		Layers:             layerReports(),
+	}
+
+	var window *glfw.Window
+	var extensions []string
+	if err := glfw.Init(); err != nil {
+		report.SurfaceError = fmt.Sprintf("initializing GLFW: %v", err)
+	} else {
+		defer glfw.Terminate()
+		glfw.WindowHint(glfw.ClientAPI, glfw.NoAPI)
+		glfw.WindowHint(glfw.Visible, glfw.False)
+		window, err = glfw.CreateWindow(64, 64, "info", nil, nil)
+		if err != nil {
+			report.SurfaceError = fmt.Sprintf("creating a window: %v", err)
+		} else {
+			defer window.Destroy()
+			extensions = vk.GetRequiredInstanceExtensions()
+		}
+	}
+
+	err := withInstance("info", extensions, func(instance vk.Instance) error {
+		surface := vk.NullSurface
+		if window != nil {
+			ret := vk.CreateWindowSurface(instance, window.GLFWWindow(), nil, &surface)
+			if err := vk.Error(ret); err != nil {
+				report.SurfaceError = fmt.Sprintf("creating a surface: %v", err)
+				surface = vk.NullSurface
+			} else {
+				defer vk.DestroySurface(instance, surface, nil)
+			}
+		}
+		for i, gpu := range physicalDevices(instance) {
+			report.Devices = append(report.Devices, deviceReport(i, gpu, surface))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"github.com/vulkan-go/glfw/v3.3/glfw"
+)
+
+type bindingKind int
+
+const (
+	bindingKey bindingKind = iota
+	bindingMouseButton
+	bindingGamepadButton
+	bindingGamepadAxis
+)
+
+// Binding is one physical input that can trigger an action.
+type Binding struct {
+	kind      bindingKind
+	key       glfw.Key
+	button    glfw.MouseButton
+	padButton GamepadButton
+	padAxis   GamepadAxis
+	// sign selects which half of a gamepad axis the binding reacts to.
+	sign float32
+}
+
+func KeyBinding(key glfw.Key) Binding {
+	return Binding{kind: bindingKey, key: key}
+}
+
+func MouseBinding(button glfw.MouseButton) Binding {
+	return Binding{kind: bindingMouseButton, button: button}
+}
+
+// GamepadButtonBinding binds a button of any connected gamepad, using the
+// standard gamepad layout.
+func GamepadButtonBinding(button GamepadButton) Binding {
+	return Binding{kind: bindingGamepadButton, padButton: button}
+}
+
+// GamepadAxisBinding binds one direction of a gamepad axis: sign 1 for the
+// positive half, -1 for the negative half. Sticks report +Y when pushed down.
+func GamepadAxisBinding(axis GamepadAxis, sign float32) Binding {
+	return Binding{kind: bindingGamepadAxis, padAxis: axis, sign: sign}
+}
+
+// Input turns GLFW callbacks into per-frame state. Edges (pressed and
+// released) and cursor/scroll deltas accumulate between frames and are
+// cleared by endFrame, so a press shorter than a frame is never lost.
+type Input struct {
+	keys         map[glfw.Key]bool
+	keysPressed  map[glfw.Key]bool
+	keysReleased map[glfw.Key]bool
+
+	buttons         [glfw.MouseButtonLast + 1]bool
+	buttonsPressed  [glfw.MouseButtonLast + 1]bool
+	buttonsReleased [glfw.MouseButtonLast + 1]bool
+
+	cursorX, cursorY float32
+	deltaX, deltaY   float32
+	scrollX, scrollY float32
+	hasCursor        bool
+
+	gamepads        map[glfw.Joystick]*gamepad
+	gamepadMappings map[string]*gamepadMapping
+	gamepadEvents   []GamepadEvent
+
+	bindings map[string][]Binding
+}
+
+func NewInput() *Input {
+	return &Input{
+		keys:            make(map[glfw.Key]bool),
+		keysPressed:     make(map[glfw.Key]bool),
+		keysReleased:    make(map[glfw.Key]bool),
+		gamepads:        make(map[glfw.Joystick]*gamepad),
+		gamepadMappings: make(map[string]*gamepadMapping),
+		bindings:        make(map[string][]Binding),
+	}
+}
+
+// defaultBindings are the actions used by the cameras and the UI.
+func (in *Input) defaultBindings() {
+	in.Bind("ui.toggle", KeyBinding(glfw.KeyF1),
+		GamepadButtonBinding(ButtonStart))
+	in.Bind("ui.click", MouseBinding(glfw.MouseButtonLeft))
+	in.Bind("camera.toggle", KeyBinding(glfw.KeyC),
+		GamepadButtonBinding(ButtonY))
+	in.Bind("screenshot", KeyBinding(glfw.KeyF12),
+		GamepadButtonBinding(ButtonBack))
+	in.Bind("camera.rotate", MouseBinding(glfw.MouseButtonLeft))
+	in.Bind("camera.look", MouseBinding(glfw.MouseButtonRight))
+	in.Bind("move.forward", KeyBinding(glfw.KeyW), KeyBinding(glfw.KeyUp),
+		GamepadAxisBinding(AxisLeftY, -1))
+	in.Bind("move.back", KeyBinding(glfw.KeyS), KeyBinding(glfw.KeyDown),
+		GamepadAxisBinding(AxisLeftY, 1))
+	in.Bind("move.left", KeyBinding(glfw.KeyA), KeyBinding(glfw.KeyLeft),
+		GamepadAxisBinding(AxisLeftX, -1))
+	in.Bind("move.right", KeyBinding(glfw.KeyD), KeyBinding(glfw.KeyRight),
+		GamepadAxisBinding(AxisLeftX, 1))
+	in.Bind("move.up", KeyBinding(glfw.KeyE),
+		GamepadAxisBinding(AxisRightTrigger, 1))
+	in.Bind("move.down", KeyBinding(glfw.KeyQ),
+		GamepadAxisBinding(AxisLeftTrigger, 1))
+	in.Bind("move.fast", KeyBinding(glfw.KeyLeftShift), KeyBinding(glfw.KeyRightShift),
+		GamepadButtonBinding(ButtonLeftBumper))
+	in.Bind("look.left", GamepadAxisBinding(AxisRightX, -1))
+	in.Bind("look.right", GamepadAxisBinding(AxisRightX, 1))
+	in.Bind("look.up", GamepadAxisBinding(AxisRightY, -1))
+	in.Bind("look.down", GamepadAxisBinding(AxisRightY, 1))
+	in.Bind("zoom.in", GamepadAxisBinding(AxisLeftY, -1))
+	in.Bind("zoom.out", GamepadAxisBinding(AxisLeftY, 1))
+}
+
+// Bind adds bindings to an action; any of them triggers it.
+func (in *Input) Bind(action string, bindings ...Binding) {
+	in.bindings[action] = append(in.bindings[action], bindings...)
+}
+
+// Unbind removes every binding of an action.
+func (in *Input) Unbind(action string) {
+	delete(in.bindings, action)
+}
+
+func (in *Input) attachWindow(window *glfw.Window) {
+	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int,
+		action glfw.Action, mods glfw.ModifierKey) {
+		switch action {
+		case glfw.Press:
+			in.keys[key] = true
+			in.keysPressed[key] = true
+		case glfw.Release:
+			in.keys[key] = false
+			in.keysReleased[key] = true
+		}
+	})
+	window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton,
+		action glfw.Action, mods glfw.ModifierKey) {
+		if button < 0 || button > glfw.MouseButtonLast {
+			return
+		}
+		switch action {
+		case glfw.Press:
+			in.buttons[button] = true
+			in.buttonsPressed[button] = true
+		case glfw.Release:
+			in.buttons[button] = false
+			in.buttonsReleased[button] = true
+		}
+	})
+	window.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		if in.hasCursor {
+			in.deltaX += float32(x) - in.cursorX
+			in.deltaY += float32(y) - in.cursorY
+		}
+		in.cursorX, in.cursorY = float32(x), float32(y)
+		in.hasCursor = true
+	})
+	window.SetScrollCallback(func(w *glfw.Window, xoff, yoff float64) {
+		in.scrollX += float32(xoff)
+		in.scrollY += float32(yoff)
+	})
+}
+
+// beginFrame samples the gamepads; keyboard and mouse state arrives through
+// callbacks during glfw.PollEvents.
+func (in *Input) beginFrame() {
+	in.pollGamepads()
+}
+
+func (in *Input) endFrame() {
+	for key := range in.keysPressed {
+		delete(in.keysPressed, key)
+	}
+	for key := range in.keysReleased {
+		delete(in.keysReleased, key)
+	}
+	in.buttonsPressed = [glfw.MouseButtonLast + 1]bool{}
+	in.buttonsReleased = [glfw.MouseButtonLast + 1]bool{}
+	in.deltaX, in.deltaY = 0, 0
+	in.scrollX, in.scrollY = 0, 0
+	in.gamepadEvents = in.gamepadEvents[:0]
+}
+
+func boolValue(b bool) float32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// value returns how far a binding is engaged, in [0, 1], now and in the
+// previous frame. Keys and buttons are either 0 or 1.
+func (in *Input) value(b Binding) (now, prev float32) {
+	switch b.kind {
+	case bindingKey:
+		down := in.keys[b.key]
+		// Rebuild the previous state from this frame's edges.
+		was := (down && !in.keysPressed[b.key]) || in.keysReleased[b.key]
+		return boolValue(down), boolValue(was)
+	case bindingMouseButton:
+		down := in.buttons[b.button]
+		was := (down && !in.buttonsPressed[b.button]) || in.buttonsReleased[b.button]
+		return boolValue(down), boolValue(was)
+	case bindingGamepadButton, bindingGamepadAxis:
+		for _, pad := range in.gamepads {
+			n, p := pad.value(b)
+			if n > now {
+				now = n
+			}
+			if p > prev {
+				prev = p
+			}
+		}
+	}
+	return now, prev
+}
+
+// Value returns the strongest engagement of any binding of action, in
+// [0, 1]; analog sticks and triggers give intermediate values.
+func (in *Input) Value(action string) float32 {
+	var v float32
+	for _, b := range in.bindings[action] {
+		if now, _ := in.value(b); now > v {
+			v = now
+		}
+	}
+	return v
+}
+
+// Down reports whether any binding of action is held, counting analog
+// inputs past the halfway point.
+func (in *Input) Down(action string) bool {
+	return in.Value(action) >= 0.5
+}
+
+// Pressed reports whether any binding of action went down this frame.
+func (in *Input) Pressed(action string) bool {
+	for _, b := range in.bindings[action] {
+		switch b.kind {
+		case bindingKey:
+			if in.keysPressed[b.key] {
+				return true
+			}
+		case bindingMouseButton:
+			if in.buttonsPressed[b.button] {
+				return true
+			}
+		default:
+			if now, prev := in.value(b); now >= 0.5 && prev < 0.5 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Released reports whether any binding of action went up this frame.
+func (in *Input) Released(action string) bool {
+	for _, b := range in.bindings[action] {
+		switch b.kind {
+		case bindingKey:
+			if in.keysReleased[b.key] {
+				return true
+			}
+		case bindingMouseButton:
+			if in.buttonsReleased[b.button] {
+				return true
+			}
+		default:
+			if now, prev := in.value(b); now < 0.5 && prev >= 0.5 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Axis combines two opposing actions into a value in [-1, 1].
+func (in *Input) Axis(negative, positive string) float32 {
+	return in.Value(positive) - in.Value(negative)
+}
+
+func (in *Input) Cursor() (x, y float32) {
+	return in.cursorX, in.cursorY
+}
+
+func (in *Input) CursorDelta() (dx, dy float32) {
+	return in.deltaX, in.deltaY
+}
+
+// Scroll returns the vertical scroll distance accumulated this frame.
+func (in *Input) Scroll() float32 {
+	return in.scrollY
+}
@@ -1,39 +1,131 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+
 	as "github.com/vulkan-go/asche"
-	vk "github.com/vulkan-go/vulkan"
 	"github.com/vulkan-go/glfw/v3.3/glfw"
+	vk "github.com/vulkan-go/vulkan"
 	"github.com/xlab/closer"
 
 	"./bindata"
-	"time"
+	"./memory"
 )
+
 ///////////////////////////////////////////////////////////////////////////////
-type Depth struct {
-	format   vk.Format
-	image    vk.Image
-	memAlloc *vk.MemoryAllocateInfo
-	mem      vk.DeviceMemory
-	view     vk.ImageView
-}
 
 type Application struct {
 	as.BaseVulkanApp
 	windowHandle uintptr
 	debugEnabled bool
-	height uint32
-	width uint32
-	depth *Depth
-
-	descPool vk.DescriptorPool
+	// debugCallback receives validation layer messages; validationErrors
+	// counts the errors among them, and validationFailed is set on the
+	// first one when they are fatal. Both are accessed atomically.
+	debugCallback    vk.DebugReportCallback
+	validationErrors int32
+	validationFailed int32
+	// debug names objects and labels command buffer regions for capture
+	// tools when VK_EXT_debug_utils is enabled.
+	debugUtilsEnabled bool
+	debug             *DebugUtils
+	profiler          *Profiler
+	// allocator places every buffer and image the application creates in
+	// shared blocks of device memory.
+	allocator *memory.Allocator
+	// uploader stages buffer and image data for device-local resources.
+	uploader *Uploader
+	height   uint32
+	width    uint32
+	// samples is the MSAA sample count; above one the scene renders into a
+	// transient multisampled image, which is resolved into the swapchain
+	// image.
+	samples vk.SampleCountFlagBits
+	// graph records the frame; renderPass is its main pass, which every
+	// pipeline drawn in the scene or overlay must be compatible with.
+	graph      *RenderGraph
+	renderPass vk.RenderPass
+
+	// texture is sampled by the cube; cubeBuffers hold its per-image
+	// uniforms, staged in cubeData, and cubeSets bind both. cubeAngle is
+	// its spin in degrees.
+	texture     *Texture
+	cubeBuffers []*Buffer
+	cubeSets    []vk.DescriptorSet
+	cubeData    cubeUniforms
+	cubeAngle   float32
+
+	// frames are recorded anew every frame, one per swapchain image.
+	// present draws their output into the swapchain images.
+	frames  []*Frame
+	present *PresentPass
+
+	// descriptors allocates sets that live as long as the application;
+	// frameDescriptors hold one allocator per swapchain image for sets
+	// used by a single frame.
+	descriptors      *DescriptorAllocator
+	frameDescriptors []*DescriptorAllocator
 
 	pipelineLayout vk.PipelineLayout
 	descLayout     vk.DescriptorSetLayout
 	pipelineCache  vk.PipelineCache
-	renderPass     vk.RenderPass
 	pipeline       vk.Pipeline
+	// pipelineSettings are the settings pipeline was built with.
+	pipelineSettings Settings
+
+	// computePasses are recorded in order in the graph's compute pass,
+	// which they declare their buffer uses on so the graph can place
+	// barriers. scenePasses are drawn in the main pass after the cube,
+	// possibly on another goroutine, so they must not change any state.
+	computePasses []func(cmd vk.CommandBuffer, imageIdx int)
+	scenePasses   []func(cmd vk.CommandBuffer, imageIdx int)
+
+	// overlayPasses are drawn at the end of the main pass, on top of the
+	// scene, under the same rules.
+	overlayPasses []func(cmd vk.CommandBuffer, imageIdx int)
+
+	window    *glfw.Window
+	settings  Settings
+	scene     Scene
+	particles *ParticleSystem
+	font      *FontAtlas
+	overlay   *Overlay
+	ui        *UI
+
+	config      Config
+	presentMode vk.PresentMode
+
+	input  *Input
+	orbit  *OrbitCamera
+	fly    *FlyCamera
+	camera Camera
+	view   mat4
+	proj   mat4
+
+	// frameDelta is the time since the previous frame and simTime the total
+	// simulated time, both in seconds.
+	frameDelta float32
+	simTime    float32
+
+	// stats records frame timings; updateTime is this frame's time spent
+	// in VulkanContextInvalidate.
+	stats      FrameStats
+	updateTime time.Duration
+
+	// screenshotPending is where the next frame is saved, and nextCapture
+	// what else becomes of it. captureQueue lists the swapchain images
+	// whose frames are still to be read back, in the order they were
+	// submitted, and captures waits for screenshots still being written.
+	screenshotPending string
+	nextCapture       frameCapture
+	captureQueue      []int
+	captures          sync.WaitGroup
+	recorder          *Recorder
 }
 
 func (a *Application) VulkanContextPrepare() error {
@@ -41,81 +133,103 @@ func (a *Application) VulkanContextPrepare() error {
 	a.height = dim.Height
 	a.width = dim.Width
 
-	a.prepareDepth()
-	// a.prepareTextures()
-	// a.prepareCubeDataBuffers()
+	a.prepareDebugReport()
+	a.prepareDebugUtils()
+	a.prepareAllocator()
+	a.prepareUploader()
+	a.samples = a.sampleCount()
+	a.prepareTextures()
+	a.prepareCubeDataBuffers()
 	a.prepareDescriptorLayout()
-	a.prepareRenderPass()
+	a.prepareRenderGraph()
 	a.preparePipeline()
-	a.prepareDescriptorPool()
+	a.prepareDescriptors()
 	a.prepareDescriptorSet()
-	a.prepareFramebuffers()
-
-	swapchainImageResources := a.Context().SwapchainImageResources()
-	for _, res := range swapchainImageResources {
-		a.drawBuildCommandBuffer(res, res.CommandBuffer())
-	}
+	a.prepareCamera()
+	a.prepareOverlay()
+	a.prepareUI()
+	a.prepareParticles()
+	a.prepareProfiler()
+	a.prepareFrames()
 	return nil
 }
 
-func (a *Application) prepareDepth() {
+func (a *Application) VulkanContextInvalidate(imageIdx int) error {
+	start := time.Now()
+	defer func() { a.updateTime = time.Since(start) }()
 	dev := a.Context().Device()
-	depthFormat := vk.FormatD16Unorm
-	a.depth = &Depth{
-		format: depthFormat,
-	}
-	ret := vk.CreateImage(dev, &vk.ImageCreateInfo{
-		SType:     vk.StructureTypeImageCreateInfo,
-		ImageType: vk.ImageType2d,
-		Format:    depthFormat,
-		Extent: vk.Extent3D{
-			Width:  a.width,
-			Height: a.height,
-			Depth:  1,
-		},
-		MipLevels:   1,
-		ArrayLayers: 1,
-		Samples:     vk.SampleCount1Bit,
-		Tiling:      vk.ImageTilingOptimal,
-		Usage:       vk.ImageUsageFlags(vk.ImageUsageDepthStencilAttachmentBit),
-	}, nil, &a.depth.image)
-	orPanic(as.NewError(ret))
-
-	var memReqs vk.MemoryRequirements
-	vk.GetImageMemoryRequirements(dev, a.depth.image, &memReqs)
-	memReqs.Deref()
+	a.waitFrame(imageIdx)
+	a.profiler.collect(dev, imageIdx)
+	a.FrameDescriptors(imageIdx).Reset()
+	a.uploader.Poll()
+	// The UI goes first so the cameras know whether it took the mouse.
+	a.ui.update(dev, imageIdx, a.input)
+	a.updatePipeline()
+	a.updateCamera()
+	a.updateCube(imageIdx)
+	if a.particles != nil {
+		a.particles.update(dev, imageIdx, a.frameDelta, a.simTime, a.view, a.proj)
+	}
+	a.updateOverlay(imageIdx)
+	if a.input.Pressed("screenshot") {
+		a.RequestScreenshot("")
+	}
+	a.submitFrame(imageIdx)
+	a.input.endFrame()
+	return nil
+}
 
-	memProps := a.Context().Platform().MemoryProperties()
-	memTypeIndex, _ := as.FindRequiredMemoryTypeFallback(memProps,
-		vk.MemoryPropertyFlagBits(memReqs.MemoryTypeBits), vk.MemoryPropertyDeviceLocalBit)
-	a.depth.memAlloc = &vk.MemoryAllocateInfo{
-		SType:           vk.StructureTypeMemoryAllocateInfo,
-		AllocationSize:  memReqs.Size,
-		MemoryTypeIndex: memTypeIndex,
+func (a *Application) prepareCamera() {
+	a.proj = perspective(math.Pi/4, float32(a.width)/float32(a.height), 0.1, 100)
+	if a.camera != nil {
+		return
+	}
+	a.orbit = &OrbitCamera{
+		Target:   vec3{0, 0.5, 0},
+		Pitch:    0.25,
+		Distance: 8,
+	}
+	a.fly = &FlyCamera{
+		Position: a.orbit.eye(),
+		Pitch:    -0.2,
+		Speed:    3,
 	}
+	a.camera = a.orbit
+	a.view = a.camera.view()
+}
 
-	var mem vk.DeviceMemory
-	ret = vk.AllocateMemory(dev, a.depth.memAlloc, nil, &mem)
-	orPanic(as.NewError(ret))
-	a.depth.mem = mem
+func (a *Application) updateCamera() {
+	if a.input.Pressed("camera.toggle") {
+		if a.camera == Camera(a.orbit) {
+			a.camera = a.fly
+		} else {
+			a.camera = a.orbit
+		}
+	}
+	a.camera.update(a.input, a.frameDelta, !a.ui.wantMouse)
+	a.view = a.camera.view()
+}
 
-	ret = vk.BindImageMemory(dev, a.depth.image, a.depth.mem, 0)
-	orPanic(as.NewError(ret))
+// prepareAllocator creates the memory allocator on the first call; it
+// outlives swapchain recreation.
+func (a *Application) prepareAllocator() {
+	if a.allocator != nil {
+		return
+	}
+	platform := a.Context().Platform()
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(platform.PhysicalDevice(), &props)
+	props.Deref()
+	a.allocator = memory.New(a.Context().Device(), platform.MemoryProperties(), props.Limits)
+}
 
-	var view vk.ImageView
-	ret = vk.CreateImageView(dev, &vk.ImageViewCreateInfo{
-		SType:  vk.StructureTypeImageViewCreateInfo,
-		Format: depthFormat,
-		SubresourceRange: vk.ImageSubresourceRange{
-			AspectMask: vk.ImageAspectFlags(vk.ImageAspectDepthBit),
-			LevelCount: 1,
-			LayerCount: 1,
-		},
-		ViewType: vk.ImageViewType2d,
-		Image:    a.depth.image,
-	}, nil, &view)
-	orPanic(as.NewError(ret))
-	a.depth.view = view
+// multisampleState is shared by every graphics pipeline drawn in the main
+// render pass, which must all match its sample count.
+func (a *Application) multisampleState() *vk.PipelineMultisampleStateCreateInfo {
+	return &vk.PipelineMultisampleStateCreateInfo{
+		SType:                vk.StructureTypePipelineMultisampleStateCreateInfo,
+		RasterizationSamples: a.samples,
+	}
 }
 
 func (a *Application) prepareDescriptorLayout() {
@@ -124,10 +238,22 @@ func (a *Application) prepareDescriptorLayout() {
 	var descLayout vk.DescriptorSetLayout
 	ret := vk.CreateDescriptorSetLayout(dev, &vk.DescriptorSetLayoutCreateInfo{
 		SType:        vk.StructureTypeDescriptorSetLayoutCreateInfo,
-		BindingCount: 0,
+		BindingCount: 2,
+		PBindings: []vk.DescriptorSetLayoutBinding{{
+			Binding:         0,
+			DescriptorType:  vk.DescriptorTypeUniformBuffer,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageVertexBit | vk.ShaderStageFragmentBit),
+		}, {
+			Binding:         1,
+			DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageFragmentBit),
+		}},
 	}, nil, &descLayout)
 	orPanic(as.NewError(ret))
 	a.descLayout = descLayout
+	a.debug.Name(a.descLayout, "cube descriptor layout")
 
 	var pipelineLayout vk.PipelineLayout
 	ret = vk.CreatePipelineLayout(dev, &vk.PipelineLayoutCreateInfo{
@@ -139,73 +265,81 @@ func (a *Application) prepareDescriptorLayout() {
 	}, nil, &pipelineLayout)
 	orPanic(as.NewError(ret))
 	a.pipelineLayout = pipelineLayout
+	a.debug.Name(a.pipelineLayout, "cube pipeline layout")
 }
 
-func (a *Application) prepareRenderPass() {
+// prepareRenderGraph declares the frame: a compute pass running the
+// computePasses, then the main pass drawing the scene and the overlay into
+// the graph's output, through a multisampled image when multisampling.
+// The depth and multisampled images are transient, so the graph creates
+// them and discards their contents every frame.
+func (a *Application) prepareRenderGraph() {
 	dev := a.Context().Device()
-	// The initial layout for the color and depth attachments will be vk.LayoutUndefined
-	// because at the start of the renderpass, we don't care about their contents.
-	// At the start of the subpass, the color attachment's layout will be transitioned
-	// to vk.LayoutColorAttachmentOptimal and the depth stencil attachment's layout
-	// will be transitioned to vk.LayoutDepthStencilAttachmentOptimal.  At the end of
-	// the renderpass, the color attachment's layout will be transitioned to
-	// vk.LayoutPresentSrc to be ready to present.  This is all done as part of
-	// the renderpass, no barriers are necessary.
-	var renderPass vk.RenderPass
-	ret := vk.CreateRenderPass(dev, &vk.RenderPassCreateInfo{
-		SType:           vk.StructureTypeRenderPassCreateInfo,
-		AttachmentCount: 2,
-		PAttachments: []vk.AttachmentDescription{{
-			Format:         a.Context().SwapchainDimensions().Format,
-			Samples:        vk.SampleCount1Bit,
-			LoadOp:         vk.AttachmentLoadOpClear,
-			StoreOp:        vk.AttachmentStoreOpStore,
-			StencilLoadOp:  vk.AttachmentLoadOpDontCare,
-			StencilStoreOp: vk.AttachmentStoreOpDontCare,
-			InitialLayout:  vk.ImageLayoutUndefined,
-			FinalLayout:    vk.ImageLayoutPresentSrc,
-		}, {
-			Format:         a.depth.format,
-			Samples:        vk.SampleCount1Bit,
-			LoadOp:         vk.AttachmentLoadOpClear,
-			StoreOp:        vk.AttachmentStoreOpDontCare,
-			StencilLoadOp:  vk.AttachmentLoadOpDontCare,
-			StencilStoreOp: vk.AttachmentStoreOpDontCare,
-			InitialLayout:  vk.ImageLayoutUndefined,
-			FinalLayout:    vk.ImageLayoutDepthStencilAttachmentOptimal,
-		}},
-		SubpassCount: 1,
-		PSubpasses: []vk.SubpassDescription{{
-			PipelineBindPoint:    vk.PipelineBindPointGraphics,
-			ColorAttachmentCount: 1,
-			PColorAttachments: []vk.AttachmentReference{{
-				Attachment: 0,
-				Layout:     vk.ImageLayoutColorAttachmentOptimal,
-			}},
-			PDepthStencilAttachment: &vk.AttachmentReference{
-				Attachment: 1,
-				Layout:     vk.ImageLayoutDepthStencilAttachmentOptimal,
-			},
-		}},
-	}, nil, &renderPass)
-	orPanic(as.NewError(ret))
-	a.renderPass = renderPass
+	format := a.Context().SwapchainDimensions().Format
+	swapchainImageResources := a.Context().SwapchainImageResources()
+	g := newRenderGraph(dev, a.allocator, a.debug, a.width, a.height,
+		format, len(swapchainImageResources))
+
+	g.AddPass("compute", ComputePass, a.recordCompute)
+	pass := g.AddPass("main", GraphicsPass, a.recordMain)
+	if a.samples == vk.SampleCount1Bit {
+		pass.Color(g.Output(), &a.settings.ClearColor)
+	} else {
+		color := g.Transient("msaa color", format, a.samples)
+		pass.Color(color, &a.settings.ClearColor).Resolve(color, g.Output())
+	}
+	pass.Depth(g.Transient("depth", vk.FormatD16Unorm, a.samples), true)
+	if a.config.recordThreads() > 1 {
+		pass.Secondary()
+	}
+	orPanic(g.Compile())
+
+	if a.graph != nil {
+		// Frames of the old swapchain may still be rendering into it.
+		vk.DeviceWaitIdle(dev)
+		a.graph.Destroy()
+	}
+	a.graph = g
+	a.renderPass = pass.RenderPass()
+	for i, res := range swapchainImageResources {
+		a.debug.Name(res.Image(), fmt.Sprintf("swapchain image %d", i))
+	}
 }
 
 func (a *Application) preparePipeline() {
 	dev := a.Context().Device()
 
-	vs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/cube.vert.spv"))
-	orPanic(err)
-	fs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/cube.frag.spv"))
-	orPanic(err)
-
 	var pipelineCache vk.PipelineCache
 	ret := vk.CreatePipelineCache(dev, &vk.PipelineCacheCreateInfo{
 		SType: vk.StructureTypePipelineCacheCreateInfo,
 	}, nil, &pipelineCache)
 	orPanic(as.NewError(ret))
 	a.pipelineCache = pipelineCache
+	a.debug.Name(a.pipelineCache, "pipeline cache")
+
+	a.createCubePipeline()
+}
+
+// updatePipeline rebuilds the cube pipeline when the settings it was built
+// with changed. Frames still in flight may use the old one, so it waits for
+// the device before destroying it.
+func (a *Application) updatePipeline() {
+	if a.settings.samePipeline(a.pipelineSettings) {
+		return
+	}
+	dev := a.Context().Device()
+	vk.DeviceWaitIdle(dev)
+	vk.DestroyPipeline(dev, a.pipeline, nil)
+	a.createCubePipeline()
+}
+
+func (a *Application) createCubePipeline() {
+	dev := a.Context().Device()
+
+	vs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/cube.vert.spv"))
+	orPanic(err)
+	fs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/cube.frag.spv"))
+	orPanic(err)
 
 	pipelineCreateInfos := []vk.GraphicsPipelineCreateInfo{{
 		SType:      vk.StructureTypeGraphicsPipelineCreateInfo,
@@ -230,7 +364,7 @@ func (a *Application) preparePipeline() {
 		PRasterizationState: &vk.PipelineRasterizationStateCreateInfo{
 			SType:       vk.StructureTypePipelineRasterizationStateCreateInfo,
 			PolygonMode: vk.PolygonModeFill,
-			CullMode:    vk.CullModeFlags(vk.CullModeBackBit),
+			CullMode:    vk.CullModeFlags(cullModes[a.settings.CullMode]),
 			FrontFace:   vk.FrontFaceCounterClockwise,
 			LineWidth:   1.0,
 		},
@@ -242,10 +376,7 @@ func (a *Application) preparePipeline() {
 				BlendEnable:    vk.False,
 			}},
 		},
-		PMultisampleState: &vk.PipelineMultisampleStateCreateInfo{
-			SType:                vk.StructureTypePipelineMultisampleStateCreateInfo,
-			RasterizationSamples: vk.SampleCount1Bit,
-		},
+		PMultisampleState: a.multisampleState(),
 		PViewportState: &vk.PipelineViewportStateCreateInfo{
 			SType:         vk.StructureTypePipelineViewportStateCreateInfo,
 			ScissorCount:  1,
@@ -253,8 +384,8 @@ func (a *Application) preparePipeline() {
 		},
 		PDepthStencilState: &vk.PipelineDepthStencilStateCreateInfo{
 			SType:                 vk.StructureTypePipelineDepthStencilStateCreateInfo,
-			DepthTestEnable:       vk.True,
-			DepthWriteEnable:      vk.True,
+			DepthTestEnable:       vkBool(a.settings.DepthTest),
+			DepthWriteEnable:      vkBool(a.settings.DepthTest),
 			DepthCompareOp:        vk.CompareOpLessOrEqual,
 			DepthBoundsTestEnable: vk.False,
 			Back: vk.StencilOpState{
@@ -284,167 +415,154 @@ func (a *Application) preparePipeline() {
 	}}
 
 	pipeline := make([]vk.Pipeline, 1)
-	ret = vk.CreateGraphicsPipelines(dev, a.pipelineCache, 1, pipelineCreateInfos, nil, pipeline)
+	ret := vk.CreateGraphicsPipelines(dev, a.pipelineCache, 1, pipelineCreateInfos, nil, pipeline)
 
 	orPanic(as.NewError(ret))
 	a.pipeline = pipeline[0]
+	a.pipelineSettings = a.settings
+	a.debug.Name(a.pipeline, "cube pipeline")
 	vk.DestroyShaderModule(dev, vs, nil)
 	vk.DestroyShaderModule(dev, fs, nil)
 }
 
-func (a *Application) prepareDescriptorPool() {
-	dev := a.Context().Device()
-	swapchainImageResources := a.Context().SwapchainImageResources()
-	var descPool vk.DescriptorPool
-	ret := vk.CreateDescriptorPool(dev, &vk.DescriptorPoolCreateInfo{
-		SType:         vk.StructureTypeDescriptorPoolCreateInfo,
-		MaxSets:       uint32(len(swapchainImageResources)),
-		PoolSizeCount: 0,
-	}, nil, &descPool)
-	orPanic(as.NewError(ret))
-	a.descPool = descPool
-}
-
+// prepareDescriptorSet points the cube's sets at the uniform buffers and
+// texture. The sets live in the persistent allocator, so they are kept
+// across swapchain recreation and only allocated for new images.
 func (a *Application) prepareDescriptorSet() {
 	dev := a.Context().Device()
 	swapchainImageResources := a.Context().SwapchainImageResources()
 
-	// texInfos := make([]vk.DescriptorImageInfo, 0, len(s.textures))
-	// for _, tex := range s.textures {
-	// 	texInfos = append(texInfos, vk.DescriptorImageInfo{
-	// 		Sampler:     tex.sampler,
-	// 		ImageView:   tex.view,
-	// 		ImageLayout: vk.ImageLayoutGeneral,
-	// 	})
-	// }
-
-	for _, res := range swapchainImageResources {
-		var set vk.DescriptorSet
-		ret := vk.AllocateDescriptorSets(dev, &vk.DescriptorSetAllocateInfo{
-			SType:              vk.StructureTypeDescriptorSetAllocateInfo,
-			DescriptorPool:     a.descPool,
-			DescriptorSetCount: 1,
-			PSetLayouts:        []vk.DescriptorSetLayout{a.descLayout},
-		}, &set)
-		orPanic(as.NewError(ret))
-
+	for i, res := range swapchainImageResources {
+		if i == len(a.cubeSets) {
+			set := a.descriptors.MustAllocate(a.descLayout)
+			a.debug.Name(set, fmt.Sprintf("cube descriptor set %d", i))
+			a.cubeSets = append(a.cubeSets, set)
+		}
+		set := a.cubeSets[i]
 		res.SetDescriptorSet(set)
 
-		vk.UpdateDescriptorSets(dev, 0, nil, 0, nil)
-	}
-}
-
-func (a *Application) prepareFramebuffers() {
-	dev := a.Context().Device()
-	swapchainImageResources := a.Context().SwapchainImageResources()
-
-	for _, res := range swapchainImageResources {
-		var fb vk.Framebuffer
-
-		ret := vk.CreateFramebuffer(dev, &vk.FramebufferCreateInfo{
-			SType:           vk.StructureTypeFramebufferCreateInfo,
-			RenderPass:      a.renderPass,
-			AttachmentCount: 2,
-			PAttachments: []vk.ImageView{
-				res.View(),
-				a.depth.view,
-			},
-			Width:  a.width,
-			Height: a.height,
-			Layers: 1,
-		}, nil, &fb)
-		orPanic(as.NewError(ret))
-
-		res.SetFramebuffer(fb)
+		vk.UpdateDescriptorSets(dev, 2, []vk.WriteDescriptorSet{{
+			SType:           vk.StructureTypeWriteDescriptorSet,
+			DstSet:          set,
+			DstBinding:      0,
+			DescriptorCount: 1,
+			DescriptorType:  vk.DescriptorTypeUniformBuffer,
+			PBufferInfo: []vk.DescriptorBufferInfo{{
+				Buffer: a.cubeBuffers[i].buffer,
+				Range:  a.cubeBuffers[i].size,
+			}},
+		}, {
+			SType:           vk.StructureTypeWriteDescriptorSet,
+			DstSet:          set,
+			DstBinding:      1,
+			DescriptorCount: 1,
+			DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+			PImageInfo: []vk.DescriptorImageInfo{{
+				Sampler:     a.texture.sampler,
+				ImageView:   a.texture.view,
+				ImageLayout: vk.ImageLayoutShaderReadOnlyOptimal,
+			}},
+		}}, 0, nil)
 	}
 }
 
-func (a *Application) drawBuildCommandBuffer(res *as.SwapchainImageResources, cmd vk.CommandBuffer) {
+// drawBuildCommandBuffer records the frame of a swapchain image into cmd,
+// which is submitted once and then reset with its pool.
+func (a *Application) drawBuildCommandBuffer(cmd vk.CommandBuffer, imageIdx int) {
 	ret := vk.BeginCommandBuffer(cmd, &vk.CommandBufferBeginInfo{
 		SType: vk.StructureTypeCommandBufferBeginInfo,
-		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageSimultaneousUseBit),
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit),
 	})
 	orPanic(as.NewError(ret))
 
-	clearValues := make([]vk.ClearValue, 2)
-	clearValues[1].SetDepthStencil(1, 0)
-	clearValues[0].SetColor([]float32{
-		0.2, 0.2, 0.2, 0.2,
-	})
+	a.profiler.Reset(cmd, imageIdx)
+	a.profiler.Begin(cmd, imageIdx, "frame")
+	a.graph.Record(cmd, imageIdx)
+	a.profiler.End(cmd, imageIdx)
+	if a.frames[imageIdx].capture.wanted() {
+		a.recordReadback(cmd, imageIdx)
+	}
 
-	vk.CmdBeginRenderPass(cmd, &vk.RenderPassBeginInfo{
-		SType:       vk.StructureTypeRenderPassBeginInfo,
-		RenderPass:  a.renderPass,
-		Framebuffer: res.Framebuffer(),
-		RenderArea: vk.Rect2D{
-			Offset: vk.Offset2D{
-				X: 0, Y: 0,
-			},
-			Extent: vk.Extent2D{
-				Width:  a.width,
-				Height: a.height,
-			},
-		},
-		ClearValueCount: 2,
-		PClearValues:    clearValues,
-	}, vk.SubpassContentsInline)
+	ret = vk.EndCommandBuffer(cmd)
+	orPanic(as.NewError(ret))
+}
+
+func (a *Application) recordCompute(cmd vk.CommandBuffer, imageIdx int) {
+	a.debug.Begin(cmd, "compute", labelCompute)
+	a.profiler.Begin(cmd, imageIdx, "compute")
+	for _, pass := range a.computePasses {
+		pass(cmd, imageIdx)
+	}
+	a.profiler.End(cmd, imageIdx)
+	a.debug.End(cmd)
+}
+
+// recordMain records the draw list of the main pass, on worker goroutines
+// into secondary command buffers when the frame has them.
+func (a *Application) recordMain(cmd vk.CommandBuffer, imageIdx int) {
+	draws := a.drawList()
+	if len(a.frames[imageIdx].secondaries) > 0 {
+		a.recordDrawsParallel(cmd, imageIdx, a.graph.Pass("main"), draws)
+		return
+	}
+	a.recordDraws(cmd, imageIdx, draws)
+}
 
+func (a *Application) drawCube(cmd vk.CommandBuffer, imageIdx int) {
+	res := a.Context().SwapchainImageResources()[imageIdx]
 	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, a.pipeline)
 	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointGraphics, a.pipelineLayout,
 		0, 1, []vk.DescriptorSet{res.DescriptorSet()}, 0, nil)
+	vk.CmdDraw(cmd, 12*3, 1, 0, 0)
+}
 
-	vk.CmdSetViewport(cmd, 0, 1, []vk.Viewport{{
-		Width:    float32(a.width),
-		Height:   float32(a.height),
-		MinDepth: 0.0,
-		MaxDepth: 1.0,
-	}})
+// runOneShot records fn into a throwaway command buffer, submits it to the
+// graphics queue and waits for it to finish.
+func (a *Application) runOneShot(fn func(cmd vk.CommandBuffer)) {
+	dev := a.Context().Device()
+	platform := a.Context().Platform()
+
+	var cmdPool vk.CommandPool
+	ret := vk.CreateCommandPool(dev, &vk.CommandPoolCreateInfo{
+		SType:            vk.StructureTypeCommandPoolCreateInfo,
+		Flags:            vk.CommandPoolCreateFlags(vk.CommandPoolCreateTransientBit),
+		QueueFamilyIndex: platform.GraphicsQueueFamilyIndex(),
+	}, nil, &cmdPool)
+	orPanic(as.NewError(ret))
+	defer vk.DestroyCommandPool(dev, cmdPool, nil)
+
+	cmds := make([]vk.CommandBuffer, 1)
+	ret = vk.AllocateCommandBuffers(dev, &vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        cmdPool,
+		Level:              vk.CommandBufferLevelPrimary,
+		CommandBufferCount: 1,
+	}, cmds)
+	orPanic(as.NewError(ret))
 
-	vk.CmdSetScissor(cmd, 0, 1, []vk.Rect2D{{
-		Offset: vk.Offset2D{
-			X: 0, Y: 0,
-		},
-		Extent: vk.Extent2D{
-			Width:  a.width,
-			Height: a.height,
-		},
-	}})
+	ret = vk.BeginCommandBuffer(cmds[0], &vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit),
+	})
+	orPanic(as.NewError(ret))
+	fn(cmds[0])
+	ret = vk.EndCommandBuffer(cmds[0])
+	orPanic(as.NewError(ret))
 
-	vk.CmdDraw(cmd, 12*3, 1, 0, 0)
-	// Note that ending the renderpass changes the image's layout from
-	// vk.ImageLayoutColorAttachmentOptimal to vk.ImageLayoutPresentSrc
-	vk.CmdEndRenderPass(cmd)
-
-	graphicsQueueIndex := a.Context().Platform().GraphicsQueueFamilyIndex()
-	presentQueueIndex := a.Context().Platform().PresentQueueFamilyIndex()
-	if graphicsQueueIndex != presentQueueIndex {
-		// Separate Present Queue Case
-		//
-		// We have to transfer ownership from the graphics queue family to the
-		// present queue family to be able to present.  Note that we don't have
-		// to transfer from present queue family back to graphics queue family at
-		// the start of the next frame because we don't care about the image's
-		// contents at that point.
-		vk.CmdPipelineBarrier(cmd,
-			vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
-			vk.PipelineStageFlags(vk.PipelineStageBottomOfPipeBit),
-			0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{{
-				SType:               vk.StructureTypeImageMemoryBarrier,
-				SrcAccessMask:       0,
-				DstAccessMask:       vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
-				OldLayout:           vk.ImageLayoutPresentSrc,
-				NewLayout:           vk.ImageLayoutPresentSrc,
-				SrcQueueFamilyIndex: graphicsQueueIndex,
-				DstQueueFamilyIndex: presentQueueIndex,
-				SubresourceRange: vk.ImageSubresourceRange{
-					AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
-					LayerCount: 1,
-					LevelCount: 1,
-				},
-				Image: res.Image(),
-			}})
-	}
-	ret = vk.EndCommandBuffer(cmd)
+	var fence vk.Fence
+	ret = vk.CreateFence(dev, &vk.FenceCreateInfo{
+		SType: vk.StructureTypeFenceCreateInfo,
+	}, nil, &fence)
+	orPanic(as.NewError(ret))
+	defer vk.DestroyFence(dev, fence, nil)
+
+	ret = vk.QueueSubmit(platform.GraphicsQueue(), 1, []vk.SubmitInfo{{
+		SType:              vk.StructureTypeSubmitInfo,
+		CommandBufferCount: 1,
+		PCommandBuffers:    cmds,
+	}}, fence)
+	orPanic(as.NewError(ret))
+	ret = vk.WaitForFences(dev, 1, []vk.Fence{fence}, vk.True, vk.MaxUint64)
 	orPanic(as.NewError(ret))
 }
 
@@ -458,90 +576,265 @@ func (a *Application) VulkanSurface(instance vk.Instance) (surface vk.Surface) {
 }
 
 func (a *Application) VulkanAppName() string {
-	return "test"
+	return a.config.Title
 }
 
+// VulkanDebug stays false even with validation on: asche would install a
+// second debug report callback next to prepareDebugReport's.
 func (a *Application) VulkanDebug() bool {
 	return false
 }
 
 func (a *Application) VulkanSwapchainDimensions() *as.SwapchainDimensions {
 	return &as.SwapchainDimensions{
-		Width: 500, Height: 500, Format: vk.FormatB8g8r8a8Unorm,
+		Width:  uint32(a.config.Width),
+		Height: uint32(a.config.Height),
+		Format: vk.FormatB8g8r8a8Unorm,
 	}
 }
 
 func (a *Application) VulkanInstanceExtensions() []string {
 	extensions := vk.GetRequiredInstanceExtensions()
-	if a.debugEnabled {
-		extensions = append(extensions, "VK_EXT_debug_report")
+	available := availableInstanceExtensions()
+	if a.debugEnabled && available[debugReportExtension] {
+		extensions = append(extensions, debugReportExtension)
+	}
+	// Capture tools expose debug utils without validation, so it is
+	// enabled whenever present.
+	if available[debugUtilsExtension] {
+		extensions = append(extensions, debugUtilsExtension)
+		a.debugUtilsEnabled = true
 	}
 	return extensions
 }
 
-func (a *Application) Destroy()  {
-	
+func (a *Application) Destroy() {
+	dev := a.Context().Device()
+	vk.DeviceWaitIdle(dev)
+	a.readCaptures(-1)
+	a.captures.Wait()
+	if a.recorder != nil {
+		a.recorder.Close()
+	}
+	if a.particles != nil {
+		a.particles.Destroy(dev)
+	}
+	a.ui.Destroy(dev)
+	a.overlay.canvas.Destroy(dev)
+	a.font.Destroy(dev)
+	a.profiler.Destroy(dev)
+	for _, buf := range a.cubeBuffers {
+		buf.Destroy(dev)
+	}
+	a.texture.Destroy(dev)
+	for _, f := range a.frames {
+		f.Destroy(dev)
+	}
+	a.present.Destroy(dev)
+	for _, d := range a.frameDescriptors {
+		d.Destroy()
+	}
+	a.descriptors.Destroy()
+	a.graph.Destroy()
+	a.uploader.Destroy()
+	log.Printf("memory: %s", a.allocator.Stats())
+	if leaked := a.allocator.Destroy(); leaked > 0 {
+		log.Printf("memory: %d allocations were never freed", leaked)
+	}
+	a.destroyDebugReport()
 }
 
-func NewApplication(debugEnabled bool) *Application {
+func NewApplication(cfg Config) *Application {
 	return &Application{
-		debugEnabled: debugEnabled,
+		debugEnabled: cfg.Validation,
+		config:       cfg,
+		settings:     cfg.Render,
+		input:        NewInput(),
+		scene:        scenes[cfg.Scene],
+		presentMode:  cfg.presentMode(),
 	}
 }
-////////////////////////////////////////////////////////////////////////////
 
+////////////////////////////////////////////////////////////////////////////
 
 func main() {
+	cfg, action, err := loadConfig(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	} else if err != nil {
+		log.Fatalln(err)
+	}
+	if action == actionPrintConfig {
+		orPanic(cfg.print(os.Stdout))
+		return
+	}
+	if action == actionInfo {
+		// The report creates its own window, if it can.
+		vk.Init()
+		if err := printInfo(os.Stdout); err != nil {
+			log.Fatalln("info:", err)
+		}
+		return
+	}
+
 	glfw.Init()
 	vk.Init()
+	devices, err := enumerateDevices()
+	if err != nil {
+		log.Fatalln("gpu:", err)
+	}
+	gpu, err := selectDevice(devices, &cfg)
+	if action == actionListGPUs {
+		printDevices(os.Stdout, devices, gpu)
+	}
+	if err != nil {
+		log.Fatalln("gpu:", err)
+	}
+	if action == actionListGPUs {
+		return
+	}
+	requestDevice(gpu)
 	defer closer.Close()
-	app := NewApplication(true)
+
+	var monitor *glfw.Monitor
+	if cfg.Fullscreen && cfg.Headless == "" {
+		monitor = glfw.GetPrimaryMonitor()
+		mode := monitor.GetVideoMode()
+		cfg.Width, cfg.Height = mode.Width, mode.Height
+	}
+	app := NewApplication(cfg)
+	if cfg.Record != "" {
+		app.recorder, err = newRecorder(cfg)
+		if err != nil {
+			log.Fatalln("record:", err)
+		}
+	}
 	reqDim := app.VulkanSwapchainDimensions()
-	glfw.WindowHint(glfw.ClientAPI,glfw.NoAPI)
-	window, _ := glfw.CreateWindow(int(reqDim.Width),int(reqDim.Height),app.VulkanAppName(),nil,nil)
+	glfw.WindowHint(glfw.ClientAPI, glfw.NoAPI)
+	if cfg.Headless != "" {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+	}
+	window, _ := glfw.CreateWindow(int(reqDim.Width), int(reqDim.Height), app.VulkanAppName(), monitor, nil)
 	app.windowHandle = window.GLFWWindow()
+	app.window = window
+	app.input.defaultBindings()
+	app.input.attachWindow(window)
+	if cfg.GamepadMappings != "" {
+		if err := app.input.LoadGamepadMappings(cfg.GamepadMappings); err != nil {
+			log.Fatalln("gamepad:", err)
+		}
+	}
+	app.input.attachGamepads()
 
 	platform, err := as.NewPlatform(app)
 	orPanic(err)
+	if err := app.checkGPU(platform, gpu); err != nil {
+		log.Fatalln("gpu:", err)
+	}
 
-	doneC := make(chan struct{},2)
-	exitC := make(chan struct{},2)
+	doneC := make(chan struct{}, 2)
+	exitC := make(chan struct{}, 2)
 	defer closer.Bind(func() {
 		exitC <- struct{}{}
 		<-doneC
 		log.Println("Bye!")
 	})
-	fpsDelay := time.Second /60
-	fpsTicker := time.NewTicker(fpsDelay)
+	// Without a cap a closed channel lets every iteration through at once.
+	uncapped := make(chan time.Time)
+	close(uncapped)
+	var fpsTick <-chan time.Time = uncapped
+	// Headless runs and recordings are not tied to real time.
+	if cfg.FPS > 0 && cfg.Headless == "" && app.recorder == nil {
+		fpsTicker := time.NewTicker(time.Second / time.Duration(cfg.FPS))
+		defer fpsTicker.Stop()
+		fpsTick = fpsTicker.C
+	}
+	lastFrame := time.Now()
+	frames := 0
 
 	for {
 		select {
 		case <-exitC:
+			app.stats.logSummary()
+			if cfg.Stats != "" {
+				if err := app.stats.Export(cfg.Stats); err != nil {
+					log.Println("stats:", err)
+				} else {
+					log.Printf("stats: wrote %s", cfg.Stats)
+				}
+			}
 			app.Destroy()
 			platform.Destroy()
 			window.Destroy()
 			glfw.Terminate()
-			fpsTicker.Stop()
+			if app.validationFatal() {
+				os.Exit(1)
+			}
 			doneC <- struct{}{}
 			return
-		case <-fpsTicker.C:
+		case <-fpsTick:
 			if window.ShouldClose() {
 				exitC <- struct{}{}
 				continue
 			}
+			frameStart := time.Now()
 			glfw.PollEvents()
+			app.input.beginFrame()
+			app.frameDelta = float32(frameStart.Sub(lastFrame).Seconds())
+			if app.recorder != nil {
+				app.frameDelta = app.recorder.timestep
+			}
+			app.simTime += app.frameDelta
+			lastFrame = frameStart
+			frames++
+			headlessDone := cfg.Headless != "" && frames >= cfg.Frames
+			app.nextCapture = frameCapture{record: app.recorder != nil && app.recorder.due()}
+			if headlessDone {
+				app.nextCapture.path, app.nextCapture.headless = cfg.Headless, true
+			}
+
+			// AcquireNextImage calls VulkanContextInvalidate once it has
+			// an image, so the update time is taken out of the acquire.
+			app.updateTime = 0
+			acquireStart := time.Now()
 			imageIdx, outdated, err := app.Context().AcquireNextImage()
 			orPanic(err)
 			if outdated {
 				imageIdx, _, err = app.Context().AcquireNextImage()
 				orPanic(err)
 			}
+			acquire := time.Since(acquireStart) - app.updateTime
+			presentStart := time.Now()
 			_, err = app.Context().PresentImage(imageIdx)
 			orPanic(err)
+			present := time.Since(presentStart)
+			if app.validationFatal() {
+				log.Println("validation: stopping after the first error")
+				exitC <- struct{}{}
+				continue
+			}
+			app.stats.add(FrameTiming{
+				CPU:     milliseconds(time.Since(frameStart)),
+				Acquire: milliseconds(acquire),
+				Update:  milliseconds(app.updateTime),
+				Present: milliseconds(present),
+			})
+
+			// The last frames are read back while shutting down.
+			if headlessDone || (app.recorder != nil && app.recorder.finished()) {
+				window.SetShouldClose(true)
+			}
 		}
 	}
 }
 
+func vkBool(b bool) vk.Bool32 {
+	if b {
+		return vk.True
+	}
+	return vk.False
+}
+
 func orPanic(err interface{}) {
 	switch v := err.(type) {
 	case error:
@@ -0,0 +1,111 @@
+package main
+
+import "math"
+
+// vec3 and mat4 are the minimal linear algebra the renderer needs. mat4 is
+// column-major to match GLSL, and the projection targets Vulkan clip space
+// (Y pointing down, depth in [0, 1]).
+type vec3 [3]float32
+
+type mat4 [16]float32
+
+func (v vec3) add(w vec3) vec3 {
+	return vec3{v[0] + w[0], v[1] + w[1], v[2] + w[2]}
+}
+
+func (v vec3) sub(w vec3) vec3 {
+	return vec3{v[0] - w[0], v[1] - w[1], v[2] - w[2]}
+}
+
+func (v vec3) scale(s float32) vec3 {
+	return vec3{v[0] * s, v[1] * s, v[2] * s}
+}
+
+func (v vec3) dot(w vec3) float32 {
+	return v[0]*w[0] + v[1]*w[1] + v[2]*w[2]
+}
+
+func (v vec3) cross(w vec3) vec3 {
+	return vec3{
+		v[1]*w[2] - v[2]*w[1],
+		v[2]*w[0] - v[0]*w[2],
+		v[0]*w[1] - v[1]*w[0],
+	}
+}
+
+func (v vec3) normalize() vec3 {
+	l := float32(math.Sqrt(float64(v.dot(v))))
+	if l == 0 {
+		return v
+	}
+	return v.scale(1 / l)
+}
+
+func identity() mat4 {
+	return mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// rotateY rotates by angle radians about the Y axis.
+func rotateY(angle float32) mat4 {
+	s, c := float32(math.Sin(float64(angle))), float32(math.Cos(float64(angle)))
+	return mat4{
+		c, 0, -s, 0,
+		0, 1, 0, 0,
+		s, 0, c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// mul returns m * n.
+func (m mat4) mul(n mat4) mat4 {
+	var r mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += m[k*4+row] * n[col*4+k]
+			}
+			r[col*4+row] = sum
+		}
+	}
+	return r
+}
+
+// perspective builds a right-handed projection with a vertical field of view
+// in radians.
+func perspective(fovy, aspect, near, far float32) mat4 {
+	f := float32(1 / math.Tan(float64(fovy)/2))
+	return mat4{
+		f / aspect, 0, 0, 0,
+		0, -f, 0, 0,
+		0, 0, far / (near - far), -1,
+		0, 0, near * far / (near - far), 0,
+	}
+}
+
+func lookAt(eye, center, up vec3) mat4 {
+	f := center.sub(eye).normalize()
+	s := f.cross(up).normalize()
+	u := s.cross(f)
+	return mat4{
+		s[0], u[0], -f[0], 0,
+		s[1], u[1], -f[1], 0,
+		s[2], u[2], -f[2], 0,
+		-s.dot(eye), -u.dot(eye), f.dot(eye), 1,
+	}
+}
+
+// viewRight and viewUp return the world-space camera axes of a view matrix,
+// used to orient billboards towards the camera.
+func (m mat4) viewRight() vec3 {
+	return vec3{m[0], m[4], m[8]}
+}
+
+func (m mat4) viewUp() vec3 {
+	return vec3{m[1], m[5], m[9]}
+}
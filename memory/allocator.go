@@ -0,0 +1,450 @@
+// Package memory sub-allocates Vulkan device memory. Resources are placed
+// in large blocks per memory type instead of getting a vk.DeviceMemory
+// each, which keeps the application far below maxMemoryAllocationCount.
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// DefaultBlockSize is the size of the blocks resources are carved from.
+// Resources of half a block or more get a dedicated allocation.
+const DefaultBlockSize = 64 << 20
+
+// Tiling tells linear resources (buffers, linear images) from optimal
+// images. Neighbours of different tiling must not share a page of
+// bufferImageGranularity bytes.
+type Tiling int
+
+const (
+	Linear Tiling = iota
+	Optimal
+)
+
+// Allocator hands out Allocations from blocks of device memory. It is safe
+// for concurrent use.
+type Allocator struct {
+	dev         vk.Device
+	props       vk.PhysicalDeviceMemoryProperties
+	granularity vk.DeviceSize
+	maxCount    uint32
+	blockSize   vk.DeviceSize
+
+	mu        sync.Mutex
+	blocks    [vk.MaxMemoryTypes][]*block
+	dedicated [vk.MaxMemoryTypes]typeUsage
+	count     uint32
+}
+
+type block struct {
+	memory vk.DeviceMemory
+	size   vk.DeviceSize
+	mapped unsafe.Pointer
+	// allocs is sorted by offset.
+	allocs []*Allocation
+}
+
+// typeUsage tracks the live dedicated allocations of a memory type.
+type typeUsage struct {
+	allocs []*Allocation
+	bytes  vk.DeviceSize
+}
+
+// Allocation is a range of device memory bound to one resource.
+type Allocation struct {
+	Memory vk.DeviceMemory
+	Offset vk.DeviceSize
+	Size   vk.DeviceSize
+
+	allocator *Allocator
+	typeIndex uint32
+	tiling    Tiling
+	block     *block
+	mapped    unsafe.Pointer
+	freed     bool
+}
+
+// New creates an allocator for dev. The properties and limits come from
+// the physical device dev was created from.
+func New(dev vk.Device, props vk.PhysicalDeviceMemoryProperties, limits vk.PhysicalDeviceLimits) *Allocator {
+	props.Deref()
+	for i := range props.MemoryTypes[:props.MemoryTypeCount] {
+		props.MemoryTypes[i].Deref()
+	}
+	for i := range props.MemoryHeaps[:props.MemoryHeapCount] {
+		props.MemoryHeaps[i].Deref()
+	}
+	limits.Deref()
+	granularity := limits.BufferImageGranularity
+	if granularity == 0 {
+		granularity = 1
+	}
+	return &Allocator{
+		dev:         dev,
+		props:       props,
+		granularity: granularity,
+		maxCount:    limits.MaxMemoryAllocationCount,
+		blockSize:   DefaultBlockSize,
+	}
+}
+
+// memoryType picks the first memory type allowed by typeBits that has all
+// of required. Failing that, host-visible requests settle for any
+// host-visible type and other requests for any allowed type. Host-visible
+// memory is always host coherent too, as allocations are never flushed or
+// invalidated; Vulkan guarantees such a type for every buffer.
+func (a *Allocator) memoryType(typeBits uint32, required vk.MemoryPropertyFlagBits) (uint32, error) {
+	if required&vk.MemoryPropertyHostVisibleBit != 0 {
+		required |= vk.MemoryPropertyHostCoherentBit
+	}
+	find := func(flags vk.MemoryPropertyFlagBits) (uint32, bool) {
+		for i := uint32(0); i < a.props.MemoryTypeCount; i++ {
+			have := vk.MemoryPropertyFlagBits(a.props.MemoryTypes[i].PropertyFlags)
+			if typeBits&(1<<i) != 0 && have&flags == flags {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+	if i, ok := find(required); ok {
+		return i, nil
+	}
+	fallback := vk.MemoryPropertyFlagBits(0)
+	if required&vk.MemoryPropertyHostVisibleBit != 0 {
+		fallback = vk.MemoryPropertyHostVisibleBit | vk.MemoryPropertyHostCoherentBit
+	}
+	if i, ok := find(fallback); ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("memory: no memory type with flags %#x in %#b", required, typeBits)
+}
+
+func (a *Allocator) hostVisible(typeIndex uint32) bool {
+	flags := vk.MemoryPropertyFlagBits(a.props.MemoryTypes[typeIndex].PropertyFlags)
+	return flags&vk.MemoryPropertyHostVisibleBit != 0
+}
+
+// allocateMemory makes one vk.DeviceMemory, mapping it persistently when
+// it is host visible.
+func (a *Allocator) allocateMemory(size vk.DeviceSize, typeIndex uint32) (vk.DeviceMemory, unsafe.Pointer, error) {
+	if a.maxCount > 0 && a.count >= a.maxCount {
+		return nil, nil, fmt.Errorf("memory: maxMemoryAllocationCount (%d) reached", a.maxCount)
+	}
+	var mem vk.DeviceMemory
+	ret := vk.AllocateMemory(a.dev, &vk.MemoryAllocateInfo{
+		SType:           vk.StructureTypeMemoryAllocateInfo,
+		AllocationSize:  size,
+		MemoryTypeIndex: typeIndex,
+	}, nil, &mem)
+	if err := vk.Error(ret); err != nil {
+		return nil, nil, fmt.Errorf("memory: allocating %d bytes of type %d: %v", size, typeIndex, err)
+	}
+	var mapped unsafe.Pointer
+	if a.hostVisible(typeIndex) {
+		ret = vk.MapMemory(a.dev, mem, 0, vk.DeviceSize(vk.WholeSize), 0, &mapped)
+		if err := vk.Error(ret); err != nil {
+			vk.FreeMemory(a.dev, mem, nil)
+			return nil, nil, fmt.Errorf("memory: mapping type %d: %v", typeIndex, err)
+		}
+	}
+	a.count++
+	return mem, mapped, nil
+}
+
+func alignUp(v, alignment vk.DeviceSize) vk.DeviceSize {
+	if alignment <= 1 {
+		return v
+	}
+	return (v + alignment - 1) / alignment * alignment
+}
+
+// samePage reports whether the byte offsets a and b fall into the same
+// bufferImageGranularity page.
+func (a *Allocator) samePage(x, y vk.DeviceSize) bool {
+	return x/a.granularity == y/a.granularity
+}
+
+// fit finds the lowest offset in b where size bytes with the given
+// alignment fit, keeping pages apart from neighbours of other tiling.
+// It returns the index in b.allocs to insert at.
+func (a *Allocator) fit(b *block, size, alignment vk.DeviceSize, tiling Tiling) (vk.DeviceSize, int, bool) {
+	start := vk.DeviceSize(0)
+	for i := 0; i <= len(b.allocs); i++ {
+		end := b.size
+		if i < len(b.allocs) {
+			end = b.allocs[i].Offset
+		}
+		offset := alignUp(start, alignment)
+		if i > 0 {
+			prev := b.allocs[i-1]
+			if prev.tiling != tiling && a.samePage(prev.Offset+prev.Size-1, offset) {
+				offset = alignUp(offset, a.granularity)
+			}
+		}
+		fits := offset+size <= end
+		if fits && i < len(b.allocs) {
+			next := b.allocs[i]
+			if next.tiling != tiling && a.samePage(offset+size-1, next.Offset) {
+				fits = false
+			}
+		}
+		if fits {
+			return offset, i, true
+		}
+		if i < len(b.allocs) {
+			start = b.allocs[i].Offset + b.allocs[i].Size
+		}
+	}
+	return 0, 0, false
+}
+
+// Allocate reserves memory satisfying reqs with the required properties.
+func (a *Allocator) Allocate(reqs vk.MemoryRequirements, required vk.MemoryPropertyFlagBits, tiling Tiling) (*Allocation, error) {
+	reqs.Deref()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	typeIndex, err := a.memoryType(reqs.MemoryTypeBits, required)
+	if err != nil {
+		return nil, err
+	}
+	alloc := &Allocation{
+		Size:      reqs.Size,
+		allocator: a,
+		typeIndex: typeIndex,
+		tiling:    tiling,
+	}
+
+	if reqs.Size >= a.blockSize/2 {
+		mem, mapped, err := a.allocateMemory(reqs.Size, typeIndex)
+		if err != nil {
+			return nil, err
+		}
+		alloc.Memory, alloc.mapped = mem, mapped
+		a.dedicated[typeIndex].allocs = append(a.dedicated[typeIndex].allocs, alloc)
+		a.dedicated[typeIndex].bytes += reqs.Size
+		return alloc, nil
+	}
+
+	for _, b := range a.blocks[typeIndex] {
+		if offset, i, ok := a.fit(b, reqs.Size, reqs.Alignment, tiling); ok {
+			a.place(b, alloc, offset, i)
+			return alloc, nil
+		}
+	}
+	mem, mapped, err := a.allocateMemory(a.blockSize, typeIndex)
+	if err != nil {
+		return nil, err
+	}
+	b := &block{memory: mem, size: a.blockSize, mapped: mapped}
+	a.blocks[typeIndex] = append(a.blocks[typeIndex], b)
+	a.place(b, alloc, 0, 0)
+	return alloc, nil
+}
+
+func (a *Allocator) place(b *block, alloc *Allocation, offset vk.DeviceSize, i int) {
+	alloc.Memory = b.memory
+	alloc.Offset = offset
+	alloc.block = b
+	if b.mapped != nil {
+		alloc.mapped = unsafe.Pointer(uintptr(b.mapped) + uintptr(offset))
+	}
+	b.allocs = append(b.allocs, nil)
+	copy(b.allocs[i+1:], b.allocs[i:])
+	b.allocs[i] = alloc
+}
+
+// AllocateBuffer allocates memory for buf and binds it.
+func (a *Allocator) AllocateBuffer(buf vk.Buffer, required vk.MemoryPropertyFlagBits) (*Allocation, error) {
+	var reqs vk.MemoryRequirements
+	vk.GetBufferMemoryRequirements(a.dev, buf, &reqs)
+	alloc, err := a.Allocate(reqs, required, Linear)
+	if err != nil {
+		return nil, err
+	}
+	if err := vk.Error(vk.BindBufferMemory(a.dev, buf, alloc.Memory, alloc.Offset)); err != nil {
+		alloc.Free()
+		return nil, fmt.Errorf("memory: binding buffer: %v", err)
+	}
+	return alloc, nil
+}
+
+// AllocateImage allocates memory for img and binds it. tiling must match
+// the tiling img was created with.
+func (a *Allocator) AllocateImage(img vk.Image, required vk.MemoryPropertyFlagBits, tiling Tiling) (*Allocation, error) {
+	var reqs vk.MemoryRequirements
+	vk.GetImageMemoryRequirements(a.dev, img, &reqs)
+	alloc, err := a.Allocate(reqs, required, tiling)
+	if err != nil {
+		return nil, err
+	}
+	if err := vk.Error(vk.BindImageMemory(a.dev, img, alloc.Memory, alloc.Offset)); err != nil {
+		alloc.Free()
+		return nil, fmt.Errorf("memory: binding image: %v", err)
+	}
+	return alloc, nil
+}
+
+// Mapped returns the host address of the allocation, or nil when its
+// memory is not host visible. Host-visible memory stays mapped for the
+// lifetime of the allocation.
+func (m *Allocation) Mapped() unsafe.Pointer {
+	return m.mapped
+}
+
+// Bytes returns the mapped allocation as a byte slice.
+func (m *Allocation) Bytes() []byte {
+	if m.mapped == nil {
+		return nil
+	}
+	return unsafe.Slice((*byte)(m.mapped), m.Size)
+}
+
+// Free returns the allocation to its allocator. Dedicated allocations
+// release their memory immediately; blocks are kept for reuse until
+// Destroy. Freeing an allocation twice panics.
+func (m *Allocation) Free() {
+	a := m.allocator
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if m.freed {
+		panic(fmt.Sprintf("memory: allocation of %d bytes at offset %d freed twice", m.Size, m.Offset))
+	}
+	m.freed = true
+
+	if m.block == nil {
+		d := &a.dedicated[m.typeIndex]
+		for i, other := range d.allocs {
+			if other == m {
+				d.allocs = append(d.allocs[:i], d.allocs[i+1:]...)
+				break
+			}
+		}
+		d.bytes -= m.Size
+		a.freeDedicated(m)
+		return
+	}
+	b := m.block
+	for i, other := range b.allocs {
+		if other == m {
+			b.allocs = append(b.allocs[:i], b.allocs[i+1:]...)
+			break
+		}
+	}
+	m.block = nil
+}
+
+func (a *Allocator) freeDedicated(m *Allocation) {
+	if m.mapped != nil {
+		vk.UnmapMemory(a.dev, m.Memory)
+	}
+	vk.FreeMemory(a.dev, m.Memory, nil)
+	a.count--
+}
+
+// TypeStats describes the memory of one memory type.
+type TypeStats struct {
+	TypeIndex      uint32
+	Flags          vk.MemoryPropertyFlags
+	Blocks         int
+	BlockBytes     vk.DeviceSize
+	UsedBytes      vk.DeviceSize
+	Allocations    int
+	Dedicated      int
+	DedicatedBytes vk.DeviceSize
+}
+
+// Stats describes everything the allocator holds.
+type Stats struct {
+	Types []TypeStats
+	// DeviceAllocations is the number of live vk.DeviceMemory objects,
+	// MaxAllocations the device's limit for it.
+	DeviceAllocations uint32
+	MaxAllocations    uint32
+}
+
+// Stats reports the memory types in use in index order.
+func (a *Allocator) Stats() Stats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := Stats{DeviceAllocations: a.count, MaxAllocations: a.maxCount}
+	for i := uint32(0); i < a.props.MemoryTypeCount; i++ {
+		t := TypeStats{
+			TypeIndex:      i,
+			Flags:          a.props.MemoryTypes[i].PropertyFlags,
+			Blocks:         len(a.blocks[i]),
+			Dedicated:      len(a.dedicated[i].allocs),
+			DedicatedBytes: a.dedicated[i].bytes,
+		}
+		for _, b := range a.blocks[i] {
+			t.BlockBytes += b.size
+			t.Allocations += len(b.allocs)
+			for _, alloc := range b.allocs {
+				t.UsedBytes += alloc.Size
+			}
+		}
+		if t.Blocks > 0 || t.Dedicated > 0 {
+			s.Types = append(s.Types, t)
+		}
+	}
+	return s
+}
+
+// Total returns the bytes reserved from the device and the bytes in use.
+func (s Stats) Total() (reserved, used vk.DeviceSize) {
+	for _, t := range s.Types {
+		reserved += t.BlockBytes + t.DedicatedBytes
+		used += t.UsedBytes + t.DedicatedBytes
+	}
+	return reserved, used
+}
+
+func (s Stats) String() string {
+	reserved, used := s.Total()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%.1f of %.1f MiB used in %d device allocations (limit %d)",
+		mib(used), mib(reserved), s.DeviceAllocations, s.MaxAllocations)
+	for _, t := range s.Types {
+		fmt.Fprintf(&sb, "\n  type %d (flags %#x): %d blocks, %d allocations, %.1f/%.1f MiB, %d dedicated %.1f MiB",
+			t.TypeIndex, t.Flags, t.Blocks, t.Allocations, mib(t.UsedBytes), mib(t.BlockBytes),
+			t.Dedicated, mib(t.DedicatedBytes))
+	}
+	return sb.String()
+}
+
+func mib(v vk.DeviceSize) float64 {
+	return float64(v) / (1 << 20)
+}
+
+// Destroy frees every block and dedicated allocation. Allocations still
+// live at this point are reported as leaks in the returned count.
+func (a *Allocator) Destroy() (leaked int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := range a.blocks {
+		for _, b := range a.blocks[i] {
+			leaked += len(b.allocs)
+			if b.mapped != nil {
+				vk.UnmapMemory(a.dev, b.memory)
+			}
+			vk.FreeMemory(a.dev, b.memory, nil)
+			a.count--
+		}
+		a.blocks[i] = nil
+		for _, m := range a.dedicated[i].allocs {
+			m.freed = true
+			a.freeDedicated(m)
+			leaked++
+		}
+		a.dedicated[i] = typeUsage{}
+	}
+	return leaked
+}
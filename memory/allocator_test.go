@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"testing"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+func TestFit(t *testing.T) {
+	type span struct {
+		offset, size vk.DeviceSize
+		tiling       Tiling
+	}
+	tests := []struct {
+		name        string
+		granularity vk.DeviceSize
+		blockSize   vk.DeviceSize
+		allocs      []span
+		size, align vk.DeviceSize
+		tiling      Tiling
+		offset      vk.DeviceSize
+		index       int
+		ok          bool
+	}{
+		{
+			name:        "empty block",
+			granularity: 1024, blockSize: 4096,
+			size: 100, align: 256, tiling: Linear,
+			offset: 0, index: 0, ok: true,
+		},
+		{
+			name:        "exact fill",
+			granularity: 1024, blockSize: 256,
+			size: 256, align: 256, tiling: Optimal,
+			offset: 0, index: 0, ok: true,
+		},
+		{
+			name:        "aligned after same tiling",
+			granularity: 1024, blockSize: 4096,
+			allocs: []span{{0, 100, Linear}},
+			size:   100, align: 64, tiling: Linear,
+			offset: 128, index: 1, ok: true,
+		},
+		{
+			name:        "next page after other tiling",
+			granularity: 1024, blockSize: 4096,
+			allocs: []span{{0, 100, Linear}},
+			size:   100, align: 64, tiling: Optimal,
+			offset: 1024, index: 1, ok: true,
+		},
+		{
+			name:        "alignment above granularity",
+			granularity: 1024, blockSize: 8192,
+			allocs: []span{{0, 100, Linear}},
+			size:   100, align: 4096, tiling: Optimal,
+			offset: 4096, index: 1, ok: true,
+		},
+		{
+			name:        "gap before other tiling on another page",
+			granularity: 1024, blockSize: 4096,
+			allocs: []span{{0, 100, Linear}, {2048, 100, Optimal}},
+			size:   100, align: 16, tiling: Linear,
+			offset: 112, index: 1, ok: true,
+		},
+		{
+			name:        "gap shares a page with the next allocation",
+			granularity: 1024, blockSize: 4096,
+			allocs: []span{{512, 100, Optimal}},
+			size:   100, align: 1, tiling: Linear,
+			offset: 1024, index: 1, ok: true,
+		},
+		{
+			name:        "gap too small",
+			granularity: 1, blockSize: 4096,
+			allocs: []span{{0, 100, Linear}, {150, 100, Linear}},
+			size:   100, align: 1, tiling: Linear,
+			offset: 250, index: 2, ok: true,
+		},
+		{
+			name:        "full block",
+			granularity: 1024, blockSize: 1024,
+			allocs: []span{{0, 1000, Linear}},
+			size:   100, align: 1, tiling: Linear,
+			ok: false,
+		},
+		{
+			name:        "alignment pushes past the end",
+			granularity: 1, blockSize: 1024,
+			allocs: []span{{0, 10, Linear}},
+			size:   600, align: 512, tiling: Linear,
+			ok: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Allocator{granularity: tt.granularity}
+			b := &block{size: tt.blockSize}
+			for _, s := range tt.allocs {
+				b.allocs = append(b.allocs, &Allocation{Offset: s.offset, Size: s.size, tiling: s.tiling})
+			}
+			offset, index, ok := a.fit(b, tt.size, tt.align, tt.tiling)
+			if ok != tt.ok || ok && (offset != tt.offset || index != tt.index) {
+				t.Errorf("fit = offset %d, index %d, %v; want offset %d, index %d, %v",
+					offset, index, ok, tt.offset, tt.index, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMemoryType(t *testing.T) {
+	const (
+		deviceLocal = vk.MemoryPropertyDeviceLocalBit
+		visible     = vk.MemoryPropertyHostVisibleBit
+		coherent    = vk.MemoryPropertyHostCoherentBit
+		cached      = vk.MemoryPropertyHostCachedBit
+	)
+	a := &Allocator{}
+	for i, flags := range []vk.MemoryPropertyFlagBits{deviceLocal, visible, visible | coherent, visible | coherent | cached} {
+		a.props.MemoryTypes[i].PropertyFlags = vk.MemoryPropertyFlags(flags)
+		a.props.MemoryTypeCount++
+	}
+	tests := []struct {
+		name     string
+		typeBits uint32
+		required vk.MemoryPropertyFlagBits
+		want     uint32
+		err      bool
+	}{
+		{"host visible is coherent", 0xf, visible, 2, false},
+		{"cached", 0xf, visible | cached, 3, false},
+		{"cached falls back to coherent", 0x7, visible | cached, 2, false},
+		{"no coherent type allowed", 0x3, visible, 0, true},
+		{"device local", 0xf, deviceLocal, 0, false},
+		{"device local falls back", 0xe, deviceLocal, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.memoryType(tt.typeBits, tt.required)
+			if (err != nil) != tt.err || err == nil && got != tt.want {
+				t.Errorf("memoryType(%#b, %#x) = %d, %v; want %d, error %v",
+					tt.typeBits, tt.required, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestFreeTwice(t *testing.T) {
+	a := &Allocator{granularity: 1}
+	b := &block{size: 1024}
+	alloc := &Allocation{Size: 64, allocator: a}
+	a.place(b, alloc, 0, 0)
+	alloc.Free()
+	if len(b.allocs) != 0 {
+		t.Fatalf("block still holds %d allocations", len(b.allocs))
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("second Free did not panic")
+		}
+	}()
+	alloc.Free()
+}
+
+func TestBytes(t *testing.T) {
+	var backing [256]byte
+	alloc := &Allocation{Size: 64, mapped: unsafe.Pointer(&backing[16])}
+	bytes := alloc.Bytes()
+	if len(bytes) != 64 || cap(bytes) != 64 {
+		t.Fatalf("Bytes() has length %d and capacity %d, want 64", len(bytes), cap(bytes))
+	}
+	bytes[0] = 1
+	if backing[16] != 1 {
+		t.Error("Bytes() does not alias the mapped memory")
+	}
+	if (&Allocation{Size: 64}).Bytes() != nil {
+		t.Error("Bytes() of unmapped memory is not nil")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const overlayScale = 2
+
+// Overlay shows frame statistics and device information in the top-left
+// corner of the window.
+type Overlay struct {
+	canvas     *Canvas
+	batch      TextBatch
+	deviceName string
+	// frameTime is an exponential moving average of the frame time in
+	// seconds, so the numbers stay readable.
+	frameTime float32
+	// notice is the last gamepad hotplug event, shown for noticeTime more
+	// seconds.
+	notice     string
+	noticeTime float32
+}
+
+// overlayNoticeDuration is how long hotplug notices stay up, in seconds.
+const overlayNoticeDuration = 3
+
+// prepareOverlay creates the font atlas and the overlay on the first call;
+// after swapchain recreation only its canvas is resized.
+func (a *Application) prepareOverlay() {
+	if a.overlay != nil {
+		a.resizeCanvas(a.overlay.canvas)
+		return
+	}
+	a.font = a.prepareFontAtlas()
+
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(a.Context().Platform().PhysicalDevice(), &props)
+	props.Deref()
+
+	a.overlay = &Overlay{
+		canvas:     a.newCanvas("overlay", a.font, 1024),
+		deviceName: vk.ToString(props.DeviceName[:]),
+	}
+	a.overlayPasses = append(a.overlayPasses, a.overlay.canvas.draw)
+}
+
+func (a *Application) updateOverlay(imageIdx int) {
+	o := a.overlay
+	if o.frameTime == 0 {
+		o.frameTime = a.frameDelta
+	} else {
+		o.frameTime += (a.frameDelta - o.frameTime) * 0.05
+	}
+	var fps float32
+	if o.frameTime > 0 {
+		fps = 1 / o.frameTime
+	}
+
+	lines := fmt.Sprintf("FPS      %.1f\nFrame    %.2f ms\nSize     %dx%d\nDevice   %s\nPresent  %s",
+		fps, o.frameTime*1000, a.width, a.height, o.deviceName, presentModeName(a.presentMode))
+	for _, scope := range a.profiler.Report() {
+		lines += "\nGPU      " + scope
+	}
+	if pads := a.input.Gamepads(); len(pads) > 0 {
+		lines += "\nGamepads " + strings.Join(pads, ", ")
+	}
+	for _, ev := range a.input.GamepadEvents() {
+		if ev.Connected {
+			o.notice = "Gamepad connected: " + ev.Name
+		} else {
+			o.notice = "Gamepad disconnected: " + ev.Name
+		}
+		o.noticeTime = overlayNoticeDuration
+	}
+	if o.noticeTime > 0 {
+		o.noticeTime -= a.frameDelta
+	}
+	mem := a.allocator.Stats()
+	reserved, used := mem.Total()
+	lines += fmt.Sprintf("\nMemory   %.1f/%.1f MiB in %d allocations",
+		float64(used)/(1<<20), float64(reserved)/(1<<20), mem.DeviceAllocations)
+	if o.noticeTime > 0 {
+		lines += "\n" + o.notice
+	}
+
+	var w float32
+	rows := strings.Split(lines, "\n")
+	for _, row := range rows {
+		if rw := textWidth(row, overlayScale); rw > w {
+			w = rw
+		}
+	}
+	o.batch.Reset()
+	if !a.settings.ShowOverlay {
+		o.canvas.upload(a.Context().Device(), imageIdx, &o.batch)
+		return
+	}
+	o.batch.Rect(4, 4, w+8, float32(len(rows)*(glyphSize+1)*overlayScale+8), rgba(0, 0, 0, 0.5))
+	o.batch.Text(8, 8, overlayScale, rgba(1, 1, 1, 1), lines)
+	o.canvas.upload(a.Context().Device(), imageIdx, &o.batch)
+}
+
+func presentModeName(mode vk.PresentMode) string {
+	switch mode {
+	case vk.PresentModeImmediate:
+		return "immediate"
+	case vk.PresentModeMailbox:
+		return "mailbox"
+	case vk.PresentModeFifo:
+		return "fifo"
+	case vk.PresentModeFifoRelaxed:
+		return "fifo-relaxed"
+	default:
+		return fmt.Sprintf("mode %d", mode)
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./bindata"
+)
+
+// EmitterParams configures one particle emitter of a scene.
+type EmitterParams struct {
+	MaxParticles int
+	// Rate is the number of particles spawned per second.
+	Rate float32
+	// Lifetime is the maximum particle age in seconds; spawned particles
+	// live between 75% and 100% of it.
+	Lifetime float32
+	Position vec3
+	Velocity vec3
+	// Spread is the maximum random velocity added in any direction.
+	Spread     float32
+	Gravity    vec3
+	StartSize  float32
+	EndSize    float32
+	StartColor [4]float32
+	EndColor   [4]float32
+}
+
+// Scene describes what gets rendered next to the cube.
+type Scene struct {
+	Emitters []EmitterParams
+}
+
+var scenes = map[string]Scene{
+	"empty": {},
+	"fountain": {Emitters: []EmitterParams{{
+		MaxParticles: 16384,
+		Rate:         4000,
+		Lifetime:     3,
+		Position:     vec3{0, -1, 0},
+		Velocity:     vec3{0, 5, 0},
+		Spread:       1.5,
+		Gravity:      vec3{0, -4, 0},
+		StartSize:    0.05,
+		EndSize:      0.02,
+		StartColor:   [4]float32{0.3, 0.6, 1.0, 1.0},
+		EndColor:     [4]float32{0.0, 0.1, 0.4, 0.0},
+	}}},
+	"fire": {Emitters: []EmitterParams{{
+		MaxParticles: 8192,
+		Rate:         3000,
+		Lifetime:     1.5,
+		Position:     vec3{-1.5, -1, 0},
+		Velocity:     vec3{0, 1.5, 0},
+		Spread:       0.6,
+		Gravity:      vec3{0, 1, 0},
+		StartSize:    0.15,
+		EndSize:      0.02,
+		StartColor:   [4]float32{1.0, 0.6, 0.1, 1.0},
+		EndColor:     [4]float32{0.6, 0.0, 0.0, 0.0},
+	}, {
+		MaxParticles: 2048,
+		Rate:         300,
+		Lifetime:     4,
+		Position:     vec3{-1.5, 0, 0},
+		Velocity:     vec3{0.2, 0.8, 0},
+		Spread:       0.3,
+		Gravity:      vec3{0, 0.2, 0},
+		StartSize:    0.1,
+		EndSize:      0.4,
+		StartColor:   [4]float32{0.1, 0.1, 0.1, 0.3},
+		EndColor:     [4]float32{0.0, 0.0, 0.0, 0.0},
+	}}},
+}
+
+const (
+	particleStride       = 48
+	particleFrameHeader  = 112
+	particleEmitterSize  = 112
+	particleWorkgroupLen = 256
+)
+
+// particleFrameData mirrors the header of the Frame block in
+// shaders/particles.comp, followed by one particleEmitterData per emitter.
+type particleFrameData struct {
+	ViewProj     mat4
+	CamRight     [4]float32
+	CamUp        [4]float32
+	Dt           float32
+	Time         float32
+	Seed         uint32
+	EmitterCount uint32
+}
+
+type particleEmitterData struct {
+	Position   [4]float32
+	Velocity   [4]float32
+	Gravity    [4]float32
+	StartColor [4]float32
+	EndColor   [4]float32
+	Size       [4]float32
+	First      uint32
+	Count      uint32
+	Spawn      int32
+	_          uint32
+}
+
+// ParticleSystem simulates every emitter of a scene in a single compute
+// dispatch over one shared particle buffer, then draws all particles as
+// instanced additive billboards.
+type ParticleSystem struct {
+	emitters   []EmitterParams
+	first      []uint32
+	spawnAccum []float32
+	total      uint32
+	// rng seeds the spawn randomness of each frame; recordings use a fixed
+	// seed so they come out the same every time.
+	rng *rand.Rand
+
+	particles *Buffer
+	// frames holds one host-visible Frame block per swapchain image, so
+	// updating the next frame never races an image still in flight.
+	frames []*Buffer
+
+	sim *ComputePipeline
+
+	descLayout     vk.DescriptorSetLayout
+	pipelineLayout vk.PipelineLayout
+	pipeline       vk.Pipeline
+	descSets       []vk.DescriptorSet
+}
+
+// prepareParticles creates the particle system on the first call and
+// registers its passes and panel. After swapchain recreation it only
+// rebuilds what depends on the swapchain: the draw pipeline, made for the
+// main render pass, and the particle buffer's place in the new graph.
+func (a *Application) prepareParticles() {
+	if len(a.scene.Emitters) == 0 {
+		return
+	}
+	if a.particles == nil {
+		a.particles = a.newParticleSystem()
+		a.computePasses = append(a.computePasses, a.particles.simulate)
+		a.scenePasses = append(a.scenePasses, a.particles.draw)
+		a.ui.RegisterPanel("Particles", a.particles.panel)
+	}
+	ps := a.particles
+	dev := a.Context().Device()
+	vk.DestroyPipeline(dev, ps.pipeline, nil)
+	ps.preparePipeline(dev, a.pipelineCache, a.renderPass, a.multisampleState())
+	a.debug.Name(ps.pipeline, "particle pipeline")
+
+	// The graph orders the simulation against the previous frame's draw
+	// and the draw against the simulation.
+	particles := a.graph.ImportBuffer("particles", ps.particles)
+	a.graph.Pass("compute").WriteBuffer(particles, vk.PipelineStageComputeShaderBit,
+		vk.AccessShaderReadBit|vk.AccessShaderWriteBit)
+	a.graph.Pass("main").ReadBuffer(particles, vk.PipelineStageVertexShaderBit,
+		vk.AccessShaderReadBit)
+}
+
+func (a *Application) newParticleSystem() *ParticleSystem {
+	dev := a.Context().Device()
+	imageCount := len(a.Context().SwapchainImageResources())
+
+	ps := &ParticleSystem{
+		// Copied so the particles panel can edit emitters without
+		// touching the scene definitions.
+		emitters:   append([]EmitterParams(nil), a.scene.Emitters...),
+		spawnAccum: make([]float32, len(a.scene.Emitters)),
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if a.recorder != nil {
+		ps.rng.Seed(1)
+	}
+	for _, em := range ps.emitters {
+		ps.first = append(ps.first, ps.total)
+		ps.total += uint32(em.MaxParticles)
+	}
+
+	ps.particles = a.newBuffer(int(ps.total)*particleStride,
+		vk.BufferUsageStorageBufferBit|vk.BufferUsageTransferDstBit,
+		vk.MemoryPropertyDeviceLocalBit)
+	// A zeroed particle has no remaining life, so every slot starts dead.
+	a.runOneShot(func(cmd vk.CommandBuffer) {
+		vk.CmdFillBuffer(cmd, ps.particles.buffer, 0, vk.DeviceSize(vk.WholeSize), 0)
+	})
+
+	frameSize := particleFrameHeader + particleEmitterSize*len(ps.emitters)
+	ps.sim = a.newComputePipeline("shaders/particles.comp.spv", []vk.DescriptorType{
+		vk.DescriptorTypeStorageBuffer,
+		vk.DescriptorTypeStorageBuffer,
+	}, 0, imageCount)
+	for i := 0; i < imageCount; i++ {
+		frame := a.newBuffer(frameSize, vk.BufferUsageStorageBufferBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		ps.frames = append(ps.frames, frame)
+		ps.sim.BindBuffer(dev, i, 0, ps.particles)
+		ps.sim.BindBuffer(dev, i, 1, frame)
+	}
+
+	ps.prepareDescriptors(dev, a.descriptors, imageCount)
+
+	a.debug.NameBuffer(ps.particles, "particles")
+	for i, frame := range ps.frames {
+		a.debug.NameBuffer(frame, fmt.Sprintf("particle frame %d", i))
+	}
+	a.debug.Name(ps.descLayout, "particle descriptor layout")
+	a.debug.Name(ps.pipelineLayout, "particle pipeline layout")
+	return ps
+}
+
+func (ps *ParticleSystem) panel(ui *UI) {
+	for i := range ps.emitters {
+		em := &ps.emitters[i]
+		ui.Label("Emitter %d (%d max)", i, em.MaxParticles)
+		ui.SliderFloat(fmt.Sprintf("rate##%d", i), &em.Rate, 0, float32(em.MaxParticles)/em.Lifetime)
+		ui.SliderFloat(fmt.Sprintf("spread##%d", i), &em.Spread, 0, 5)
+		ui.SliderFloat(fmt.Sprintf("gravity y##%d", i), &em.Gravity[1], -10, 10)
+	}
+}
+
+func (ps *ParticleSystem) prepareDescriptors(dev vk.Device, descriptors *DescriptorAllocator, imageCount int) {
+	ret := vk.CreateDescriptorSetLayout(dev, &vk.DescriptorSetLayoutCreateInfo{
+		SType:        vk.StructureTypeDescriptorSetLayoutCreateInfo,
+		BindingCount: 2,
+		PBindings: []vk.DescriptorSetLayoutBinding{{
+			Binding:         0,
+			DescriptorType:  vk.DescriptorTypeStorageBuffer,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageVertexBit),
+		}, {
+			Binding:         1,
+			DescriptorType:  vk.DescriptorTypeStorageBuffer,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageVertexBit),
+		}},
+	}, nil, &ps.descLayout)
+	orPanic(as.NewError(ret))
+
+	ret = vk.CreatePipelineLayout(dev, &vk.PipelineLayoutCreateInfo{
+		SType:          vk.StructureTypePipelineLayoutCreateInfo,
+		SetLayoutCount: 1,
+		PSetLayouts: []vk.DescriptorSetLayout{
+			ps.descLayout,
+		},
+	}, nil, &ps.pipelineLayout)
+	orPanic(as.NewError(ret))
+
+	ps.descSets = make([]vk.DescriptorSet, imageCount)
+	for i := range ps.descSets {
+		ps.descSets[i] = descriptors.MustAllocate(ps.descLayout)
+
+		vk.UpdateDescriptorSets(dev, 2, []vk.WriteDescriptorSet{{
+			SType:           vk.StructureTypeWriteDescriptorSet,
+			DstSet:          ps.descSets[i],
+			DstBinding:      0,
+			DescriptorCount: 1,
+			DescriptorType:  vk.DescriptorTypeStorageBuffer,
+			PBufferInfo: []vk.DescriptorBufferInfo{{
+				Buffer: ps.particles.buffer,
+				Range:  ps.particles.size,
+			}},
+		}, {
+			SType:           vk.StructureTypeWriteDescriptorSet,
+			DstSet:          ps.descSets[i],
+			DstBinding:      1,
+			DescriptorCount: 1,
+			DescriptorType:  vk.DescriptorTypeStorageBuffer,
+			PBufferInfo: []vk.DescriptorBufferInfo{{
+				Buffer: ps.frames[i].buffer,
+				Range:  ps.frames[i].size,
+			}},
+		}}, 0, nil)
+	}
+}
+
+func (ps *ParticleSystem) preparePipeline(dev vk.Device, cache vk.PipelineCache, renderPass vk.RenderPass,
+	multisample *vk.PipelineMultisampleStateCreateInfo) {
+	vs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/particles.vert.spv"))
+	orPanic(err)
+	fs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/particles.frag.spv"))
+	orPanic(err)
+
+	pipeline := make([]vk.Pipeline, 1)
+	ret := vk.CreateGraphicsPipelines(dev, cache, 1, []vk.GraphicsPipelineCreateInfo{{
+		SType:      vk.StructureTypeGraphicsPipelineCreateInfo,
+		Layout:     ps.pipelineLayout,
+		RenderPass: renderPass,
+
+		PDynamicState: &vk.PipelineDynamicStateCreateInfo{
+			SType:             vk.StructureTypePipelineDynamicStateCreateInfo,
+			DynamicStateCount: 2,
+			PDynamicStates: []vk.DynamicState{
+				vk.DynamicStateScissor,
+				vk.DynamicStateViewport,
+			},
+		},
+		PVertexInputState: &vk.PipelineVertexInputStateCreateInfo{
+			SType: vk.StructureTypePipelineVertexInputStateCreateInfo,
+		},
+		PInputAssemblyState: &vk.PipelineInputAssemblyStateCreateInfo{
+			SType:    vk.StructureTypePipelineInputAssemblyStateCreateInfo,
+			Topology: vk.PrimitiveTopologyTriangleList,
+		},
+		PRasterizationState: &vk.PipelineRasterizationStateCreateInfo{
+			SType:       vk.StructureTypePipelineRasterizationStateCreateInfo,
+			PolygonMode: vk.PolygonModeFill,
+			CullMode:    vk.CullModeFlags(vk.CullModeNone),
+			FrontFace:   vk.FrontFaceCounterClockwise,
+			LineWidth:   1.0,
+		},
+		// Additive blending: particles only ever brighten what is behind
+		// them, so draw order does not matter.
+		PColorBlendState: &vk.PipelineColorBlendStateCreateInfo{
+			SType:           vk.StructureTypePipelineColorBlendStateCreateInfo,
+			AttachmentCount: 1,
+			PAttachments: []vk.PipelineColorBlendAttachmentState{{
+				ColorWriteMask:      0xF,
+				BlendEnable:         vk.True,
+				SrcColorBlendFactor: vk.BlendFactorOne,
+				DstColorBlendFactor: vk.BlendFactorOne,
+				ColorBlendOp:        vk.BlendOpAdd,
+				SrcAlphaBlendFactor: vk.BlendFactorZero,
+				DstAlphaBlendFactor: vk.BlendFactorOne,
+				AlphaBlendOp:        vk.BlendOpAdd,
+			}},
+		},
+		PMultisampleState: multisample,
+		PViewportState: &vk.PipelineViewportStateCreateInfo{
+			SType:         vk.StructureTypePipelineViewportStateCreateInfo,
+			ScissorCount:  1,
+			ViewportCount: 1,
+		},
+		// Particles are occluded by the cube but never occlude each other.
+		PDepthStencilState: &vk.PipelineDepthStencilStateCreateInfo{
+			SType:            vk.StructureTypePipelineDepthStencilStateCreateInfo,
+			DepthTestEnable:  vk.True,
+			DepthWriteEnable: vk.False,
+			DepthCompareOp:   vk.CompareOpLessOrEqual,
+		},
+		StageCount: 2,
+		PStages: []vk.PipelineShaderStageCreateInfo{{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageVertexBit,
+			Module: vs,
+			PName:  "main\x00",
+		}, {
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageFragmentBit,
+			Module: fs,
+			PName:  "main\x00",
+		}},
+	}}, nil, pipeline)
+	orPanic(as.NewError(ret))
+	ps.pipeline = pipeline[0]
+	vk.DestroyShaderModule(dev, vs, nil)
+	vk.DestroyShaderModule(dev, fs, nil)
+}
+
+// update fills the Frame block of the given swapchain image with the camera,
+// the timestep and this frame's spawn budget of every emitter.
+func (ps *ParticleSystem) update(dev vk.Device, imageIdx int, dt, time float32, view, proj mat4) {
+	right, up := view.viewRight(), view.viewUp()
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, &particleFrameData{
+		ViewProj:     proj.mul(view),
+		CamRight:     [4]float32{right[0], right[1], right[2], 0},
+		CamUp:        [4]float32{up[0], up[1], up[2], 0},
+		Dt:           dt,
+		Time:         time,
+		Seed:         ps.rng.Uint32(),
+		EmitterCount: uint32(len(ps.emitters)),
+	})
+	for i, em := range ps.emitters {
+		ps.spawnAccum[i] += em.Rate * dt
+		spawn := float32(math.Floor(float64(ps.spawnAccum[i])))
+		ps.spawnAccum[i] -= spawn
+		binary.Write(&buf, binary.LittleEndian, &particleEmitterData{
+			Position:   [4]float32{em.Position[0], em.Position[1], em.Position[2], em.Spread},
+			Velocity:   [4]float32{em.Velocity[0], em.Velocity[1], em.Velocity[2], em.Lifetime},
+			Gravity:    [4]float32{em.Gravity[0], em.Gravity[1], em.Gravity[2], 0},
+			StartColor: em.StartColor,
+			EndColor:   em.EndColor,
+			Size:       [4]float32{em.StartSize, em.EndSize, 0, 0},
+			First:      ps.first[i],
+			Count:      uint32(em.MaxParticles),
+			Spawn:      int32(spawn),
+		})
+	}
+	ps.frames[imageIdx].Write(0, buf.Bytes())
+}
+
+func (ps *ParticleSystem) simulate(cmd vk.CommandBuffer, imageIdx int) {
+	groups := (ps.total + particleWorkgroupLen - 1) / particleWorkgroupLen
+	ps.sim.Dispatch(cmd, imageIdx, nil, groups, 1, 1)
+}
+
+func (ps *ParticleSystem) draw(cmd vk.CommandBuffer, imageIdx int) {
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, ps.pipeline)
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointGraphics, ps.pipelineLayout,
+		0, 1, []vk.DescriptorSet{ps.descSets[imageIdx]}, 0, nil)
+	// Dead particles have zero size and collapse to degenerate quads.
+	vk.CmdDraw(cmd, 6, ps.total, 0, 0)
+}
+
+func (ps *ParticleSystem) Destroy(dev vk.Device) {
+	vk.DestroyPipeline(dev, ps.pipeline, nil)
+	vk.DestroyPipelineLayout(dev, ps.pipelineLayout, nil)
+	vk.DestroyDescriptorSetLayout(dev, ps.descLayout, nil)
+	ps.sim.Destroy(dev)
+	for _, frame := range ps.frames {
+		frame.Destroy(dev)
+	}
+	ps.particles.Destroy(dev)
+}
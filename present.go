@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./bindata"
+)
+
+// PresentPass draws the output of a frame into its swapchain image with a
+// full-screen triangle. asche creates swapchain images for use as color
+// attachments only, so they cannot be copied into.
+type PresentPass struct {
+	renderPass     vk.RenderPass
+	descLayout     vk.DescriptorSetLayout
+	pipelineLayout vk.PipelineLayout
+	pipeline       vk.Pipeline
+	sampler        vk.Sampler
+	// sets has one descriptor set per swapchain image, sampling its output
+	// image; they are kept when the swapchain is recreated.
+	sets []vk.DescriptorSet
+}
+
+// preparePresentPass creates the present pass on first use, then points
+// its descriptor sets at the graph's output images and gives every
+// swapchain image a framebuffer, which asche destroys with the image.
+func (a *Application) preparePresentPass() {
+	dev := a.Context().Device()
+	if a.present == nil {
+		a.present = a.newPresentPass(a.Context().SwapchainDimensions().Format)
+	}
+	p := a.present
+
+	for i, res := range a.Context().SwapchainImageResources() {
+		if i == len(p.sets) {
+			set := a.descriptors.MustAllocate(p.descLayout)
+			a.debug.Name(set, fmt.Sprintf("present descriptor set %d", i))
+			p.sets = append(p.sets, set)
+		}
+		vk.UpdateDescriptorSets(dev, 1, []vk.WriteDescriptorSet{{
+			SType:           vk.StructureTypeWriteDescriptorSet,
+			DstSet:          p.sets[i],
+			DstBinding:      0,
+			DescriptorCount: 1,
+			DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+			PImageInfo: []vk.DescriptorImageInfo{{
+				Sampler:     p.sampler,
+				ImageView:   a.graph.OutputView(i),
+				ImageLayout: vk.ImageLayoutShaderReadOnlyOptimal,
+			}},
+		}}, 0, nil)
+
+		var fb vk.Framebuffer
+		ret := vk.CreateFramebuffer(dev, &vk.FramebufferCreateInfo{
+			SType:           vk.StructureTypeFramebufferCreateInfo,
+			RenderPass:      p.renderPass,
+			AttachmentCount: 1,
+			PAttachments:    []vk.ImageView{res.View()},
+			Width:           a.width,
+			Height:          a.height,
+			Layers:          1,
+		}, nil, &fb)
+		orPanic(as.NewError(ret))
+		a.debug.Name(fb, fmt.Sprintf("present framebuffer %d", i))
+		res.SetFramebuffer(fb)
+	}
+}
+
+func (a *Application) newPresentPass(format vk.Format) *PresentPass {
+	dev := a.Context().Device()
+	p := &PresentPass{}
+
+	// The swapchain image is overwritten entirely, and asche waits for it
+	// to be acquired at the color attachment output stage.
+	ret := vk.CreateRenderPass(dev, &vk.RenderPassCreateInfo{
+		SType:           vk.StructureTypeRenderPassCreateInfo,
+		AttachmentCount: 1,
+		PAttachments: []vk.AttachmentDescription{{
+			Format:         format,
+			Samples:        vk.SampleCount1Bit,
+			LoadOp:         vk.AttachmentLoadOpDontCare,
+			StoreOp:        vk.AttachmentStoreOpStore,
+			StencilLoadOp:  vk.AttachmentLoadOpDontCare,
+			StencilStoreOp: vk.AttachmentStoreOpDontCare,
+			InitialLayout:  vk.ImageLayoutUndefined,
+			FinalLayout:    vk.ImageLayoutPresentSrc,
+		}},
+		SubpassCount: 1,
+		PSubpasses: []vk.SubpassDescription{{
+			PipelineBindPoint:    vk.PipelineBindPointGraphics,
+			ColorAttachmentCount: 1,
+			PColorAttachments: []vk.AttachmentReference{{
+				Attachment: 0,
+				Layout:     vk.ImageLayoutColorAttachmentOptimal,
+			}},
+		}},
+		DependencyCount: 1,
+		PDependencies: []vk.SubpassDependency{{
+			SrcSubpass:    vk.SubpassExternal,
+			DstSubpass:    0,
+			SrcStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+			DstStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+			DstAccessMask: vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+		}},
+	}, nil, &p.renderPass)
+	orPanic(as.NewError(ret))
+
+	ret = vk.CreateSampler(dev, &vk.SamplerCreateInfo{
+		SType:        vk.StructureTypeSamplerCreateInfo,
+		MagFilter:    vk.FilterNearest,
+		MinFilter:    vk.FilterNearest,
+		MipmapMode:   vk.SamplerMipmapModeNearest,
+		AddressModeU: vk.SamplerAddressModeClampToEdge,
+		AddressModeV: vk.SamplerAddressModeClampToEdge,
+		AddressModeW: vk.SamplerAddressModeClampToEdge,
+		BorderColor:  vk.BorderColorFloatOpaqueBlack,
+	}, nil, &p.sampler)
+	orPanic(as.NewError(ret))
+
+	ret = vk.CreateDescriptorSetLayout(dev, &vk.DescriptorSetLayoutCreateInfo{
+		SType:        vk.StructureTypeDescriptorSetLayoutCreateInfo,
+		BindingCount: 1,
+		PBindings: []vk.DescriptorSetLayoutBinding{{
+			Binding:         0,
+			DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+			DescriptorCount: 1,
+			StageFlags:      vk.ShaderStageFlags(vk.ShaderStageFragmentBit),
+		}},
+	}, nil, &p.descLayout)
+	orPanic(as.NewError(ret))
+
+	ret = vk.CreatePipelineLayout(dev, &vk.PipelineLayoutCreateInfo{
+		SType:          vk.StructureTypePipelineLayoutCreateInfo,
+		SetLayoutCount: 1,
+		PSetLayouts: []vk.DescriptorSetLayout{
+			p.descLayout,
+		},
+	}, nil, &p.pipelineLayout)
+	orPanic(as.NewError(ret))
+
+	p.preparePipeline(dev, a.pipelineCache)
+
+	a.debug.Name(p.renderPass, "present render pass")
+	a.debug.Name(p.sampler, "present sampler")
+	a.debug.Name(p.descLayout, "present descriptor layout")
+	a.debug.Name(p.pipelineLayout, "present pipeline layout")
+	a.debug.Name(p.pipeline, "present pipeline")
+	return p
+}
+
+func (p *PresentPass) preparePipeline(dev vk.Device, cache vk.PipelineCache) {
+	vs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/present.vert.spv"))
+	orPanic(err)
+	fs, err := as.LoadShaderModule(dev, bindata.MustAsset("shaders/present.frag.spv"))
+	orPanic(err)
+
+	pipeline := make([]vk.Pipeline, 1)
+	ret := vk.CreateGraphicsPipelines(dev, cache, 1, []vk.GraphicsPipelineCreateInfo{{
+		SType:      vk.StructureTypeGraphicsPipelineCreateInfo,
+		Layout:     p.pipelineLayout,
+		RenderPass: p.renderPass,
+
+		PDynamicState: &vk.PipelineDynamicStateCreateInfo{
+			SType:             vk.StructureTypePipelineDynamicStateCreateInfo,
+			DynamicStateCount: 2,
+			PDynamicStates: []vk.DynamicState{
+				vk.DynamicStateScissor,
+				vk.DynamicStateViewport,
+			},
+		},
+		// The vertices are generated from their index.
+		PVertexInputState: &vk.PipelineVertexInputStateCreateInfo{
+			SType: vk.StructureTypePipelineVertexInputStateCreateInfo,
+		},
+		PInputAssemblyState: &vk.PipelineInputAssemblyStateCreateInfo{
+			SType:    vk.StructureTypePipelineInputAssemblyStateCreateInfo,
+			Topology: vk.PrimitiveTopologyTriangleList,
+		},
+		PRasterizationState: &vk.PipelineRasterizationStateCreateInfo{
+			SType:       vk.StructureTypePipelineRasterizationStateCreateInfo,
+			PolygonMode: vk.PolygonModeFill,
+			CullMode:    vk.CullModeFlags(vk.CullModeNone),
+			FrontFace:   vk.FrontFaceCounterClockwise,
+			LineWidth:   1.0,
+		},
+		PColorBlendState: &vk.PipelineColorBlendStateCreateInfo{
+			SType:           vk.StructureTypePipelineColorBlendStateCreateInfo,
+			AttachmentCount: 1,
+			PAttachments: []vk.PipelineColorBlendAttachmentState{{
+				ColorWriteMask: 0xF,
+				BlendEnable:    vk.False,
+			}},
+		},
+		PMultisampleState: &vk.PipelineMultisampleStateCreateInfo{
+			SType:                vk.StructureTypePipelineMultisampleStateCreateInfo,
+			RasterizationSamples: vk.SampleCount1Bit,
+		},
+		PViewportState: &vk.PipelineViewportStateCreateInfo{
+			SType:         vk.StructureTypePipelineViewportStateCreateInfo,
+			ScissorCount:  1,
+			ViewportCount: 1,
+		},
+		StageCount: 2,
+		PStages: []vk.PipelineShaderStageCreateInfo{{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageVertexBit,
+			Module: vs,
+			PName:  "main\x00",
+		}, {
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageFragmentBit,
+			Module: fs,
+			PName:  "main\x00",
+		}},
+	}}, nil, pipeline)
+	orPanic(as.NewError(ret))
+	p.pipeline = pipeline[0]
+	vk.DestroyShaderModule(dev, vs, nil)
+	vk.DestroyShaderModule(dev, fs, nil)
+}
+
+// recordPresentCommands records the command buffer asche submits for a
+// swapchain image: the image's graph output is made readable by shaders
+// and drawn into it by the present pass, which leaves it in the present
+// layout.
+func (a *Application) recordPresentCommands(res *as.SwapchainImageResources, imageIdx int) {
+	p := a.present
+	cmd := res.CommandBuffer()
+	ret := vk.BeginCommandBuffer(cmd, &vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageSimultaneousUseBit),
+	})
+	orPanic(as.NewError(ret))
+
+	subresource := vk.ImageSubresourceRange{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LevelCount: 1,
+		LayerCount: 1,
+	}
+	// The output was written by the frame submitted just before, which may
+	// also have copied it for reading back. The next frame of the image
+	// starts it from the undefined layout again.
+	vk.CmdPipelineBarrier(cmd,
+		vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit|vk.PipelineStageTransferBit),
+		vk.PipelineStageFlags(vk.PipelineStageFragmentShaderBit),
+		0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{{
+			SType:               vk.StructureTypeImageMemoryBarrier,
+			SrcAccessMask:       vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+			DstAccessMask:       vk.AccessFlags(vk.AccessShaderReadBit),
+			OldLayout:           vk.ImageLayoutTransferSrcOptimal,
+			NewLayout:           vk.ImageLayoutShaderReadOnlyOptimal,
+			SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+			DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+			Image:               a.graph.OutputImage(imageIdx),
+			SubresourceRange:    subresource,
+		}})
+
+	vk.CmdBeginRenderPass(cmd, &vk.RenderPassBeginInfo{
+		SType:       vk.StructureTypeRenderPassBeginInfo,
+		RenderPass:  p.renderPass,
+		Framebuffer: res.Framebuffer(),
+		RenderArea: vk.Rect2D{
+			Extent: vk.Extent2D{
+				Width:  a.width,
+				Height: a.height,
+			},
+		},
+	}, vk.SubpassContentsInline)
+	vk.CmdSetViewport(cmd, 0, 1, []vk.Viewport{{
+		Width:    float32(a.width),
+		Height:   float32(a.height),
+		MinDepth: 0.0,
+		MaxDepth: 1.0,
+	}})
+	vk.CmdSetScissor(cmd, 0, 1, []vk.Rect2D{{
+		Extent: vk.Extent2D{
+			Width:  a.width,
+			Height: a.height,
+		},
+	}})
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, p.pipeline)
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointGraphics, p.pipelineLayout,
+		0, 1, []vk.DescriptorSet{p.sets[imageIdx]}, 0, nil)
+	vk.CmdDraw(cmd, 3, 1, 0, 0)
+	vk.CmdEndRenderPass(cmd)
+
+	// With a separate present queue, ownership of the image moves to its
+	// family. The image is not moved back at the start of the next frame,
+	// as its contents are not needed then.
+	graphicsQueueIndex := a.Context().Platform().GraphicsQueueFamilyIndex()
+	presentQueueIndex := a.Context().Platform().PresentQueueFamilyIndex()
+	if graphicsQueueIndex != presentQueueIndex {
+		vk.CmdPipelineBarrier(cmd,
+			vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+			vk.PipelineStageFlags(vk.PipelineStageBottomOfPipeBit),
+			0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{{
+				SType:               vk.StructureTypeImageMemoryBarrier,
+				SrcAccessMask:       vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+				OldLayout:           vk.ImageLayoutPresentSrc,
+				NewLayout:           vk.ImageLayoutPresentSrc,
+				SrcQueueFamilyIndex: graphicsQueueIndex,
+				DstQueueFamilyIndex: presentQueueIndex,
+				Image:               res.Image(),
+				SubresourceRange:    subresource,
+			}})
+	}
+	ret = vk.EndCommandBuffer(cmd)
+	orPanic(as.NewError(ret))
+}
+
+func (p *PresentPass) Destroy(dev vk.Device) {
+	vk.DestroyPipeline(dev, p.pipeline, nil)
+	vk.DestroyPipelineLayout(dev, p.pipelineLayout, nil)
+	vk.DestroyDescriptorSetLayout(dev, p.descLayout, nil)
+	vk.DestroySampler(dev, p.sampler, nil)
+	vk.DestroyRenderPass(dev, p.renderPass, nil)
+}
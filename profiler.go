@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const (
+	// profilerMaxScopes bounds the scopes one command buffer can record.
+	profilerMaxScopes = 32
+	// profilerWindow is the number of samples in each rolling average.
+	profilerWindow = 120
+	// profilerLogInterval is how often the averages are written to the log.
+	profilerLogInterval = 5 * time.Second
+)
+
+// Profiler measures GPU time of named scopes with timestamp queries. Every
+// swapchain image owns a slice of the query pool, so the results of an
+// image are read back when it comes around again, a few frames after they
+// were written. Scopes can be recorded into several command buffers of a
+// frame at once; the times of scopes sharing a name add up. A nil Profiler
+// ignores all calls, which is what devices without timestamp support get.
+type Profiler struct {
+	// mu guards frames while command buffers are recorded in parallel.
+	mu   sync.Mutex
+	pool vk.QueryPool
+	// period is the number of nanoseconds per timestamp tick and mask
+	// covers the bits the graphics queue actually writes.
+	period float64
+	mask   uint64
+
+	frames  []profilerFrame
+	stats   map[string]*rollingAverage
+	order   []string
+	lastLog time.Time
+}
+
+type profilerFrame struct {
+	scopes []profilerScope
+	// open is the stack of scopes left open in each command buffer.
+	open map[vk.CommandBuffer][]int
+	used uint32
+	// submitted is set by Submitted once the command buffer recorded with
+	// these scopes is on its way; until then there is nothing to read.
+	submitted bool
+}
+
+type profilerScope struct {
+	name       string
+	begin, end uint32
+}
+
+type rollingAverage struct {
+	samples []float64
+	next    int
+	sum     float64
+}
+
+func (r *rollingAverage) add(v float64) {
+	if len(r.samples) < profilerWindow {
+		r.samples = append(r.samples, v)
+	} else {
+		r.sum -= r.samples[r.next]
+		r.samples[r.next] = v
+		r.next = (r.next + 1) % profilerWindow
+	}
+	r.sum += v
+}
+
+func (r *rollingAverage) average() float64 {
+	if len(r.samples) == 0 {
+		return 0
+	}
+	return r.sum / float64(len(r.samples))
+}
+
+func (a *Application) prepareProfiler() {
+	if a.profiler != nil {
+		return
+	}
+	platform := a.Context().Platform()
+	gpu := platform.PhysicalDevice()
+
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(gpu, &props)
+	props.Deref()
+	props.Limits.Deref()
+
+	var familyCount uint32
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &familyCount, nil)
+	families := make([]vk.QueueFamilyProperties, familyCount)
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &familyCount, families)
+	family := families[platform.GraphicsQueueFamilyIndex()]
+	family.Deref()
+
+	if family.TimestampValidBits == 0 || props.Limits.TimestampPeriod == 0 {
+		log.Printf("profiler: the graphics queue does not support timestamps")
+		return
+	}
+
+	imageCount := len(a.Context().SwapchainImageResources())
+	p := &Profiler{
+		period:  float64(props.Limits.TimestampPeriod),
+		mask:    ^uint64(0),
+		frames:  make([]profilerFrame, imageCount),
+		stats:   make(map[string]*rollingAverage),
+		lastLog: time.Now(),
+	}
+	if family.TimestampValidBits < 64 {
+		p.mask = 1<<family.TimestampValidBits - 1
+	}
+	ret := vk.CreateQueryPool(a.Context().Device(), &vk.QueryPoolCreateInfo{
+		SType:      vk.StructureTypeQueryPoolCreateInfo,
+		QueryType:  vk.QueryTypeTimestamp,
+		QueryCount: uint32(imageCount * profilerMaxScopes * 2),
+	}, nil, &p.pool)
+	orPanic(as.NewError(ret))
+	a.debug.Name(p.pool, "profiler queries")
+	a.profiler = p
+}
+
+func (p *Profiler) base(imageIdx int) uint32 {
+	return uint32(imageIdx * profilerMaxScopes * 2)
+}
+
+// Reset starts recording the scopes of an image's command buffer. It must
+// be recorded outside a render pass, before any Begin.
+func (p *Profiler) Reset(cmd vk.CommandBuffer, imageIdx int) {
+	if p == nil {
+		return
+	}
+	p.reset(imageIdx)
+	vk.CmdResetQueryPool(cmd, p.pool, p.base(imageIdx), profilerMaxScopes*2)
+}
+
+func (p *Profiler) reset(imageIdx int) {
+	p.mu.Lock()
+	p.frames[imageIdx] = profilerFrame{open: make(map[vk.CommandBuffer][]int)}
+	p.mu.Unlock()
+}
+
+// Begin opens a named scope; scopes nest and are closed by End.
+func (p *Profiler) Begin(cmd vk.CommandBuffer, imageIdx int, name string) {
+	if p == nil {
+		return
+	}
+	vk.CmdWriteTimestamp(cmd, vk.PipelineStageTopOfPipeBit, p.pool, p.push(cmd, imageIdx, name))
+}
+
+func (p *Profiler) End(cmd vk.CommandBuffer, imageIdx int) {
+	if p == nil {
+		return
+	}
+	vk.CmdWriteTimestamp(cmd, vk.PipelineStageBottomOfPipeBit, p.pool, p.pop(cmd, imageIdx))
+}
+
+// push opens a scope in cmd and returns its begin query.
+func (p *Profiler) push(cmd vk.CommandBuffer, imageIdx int, name string) uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f := &p.frames[imageIdx]
+	if len(f.scopes) == profilerMaxScopes {
+		log.Panicf("profiler: more than %d scopes in one frame", profilerMaxScopes)
+	}
+	query := p.base(imageIdx) + f.used
+	f.used += 2
+	f.open[cmd] = append(f.open[cmd], len(f.scopes))
+	f.scopes = append(f.scopes, profilerScope{name: name, begin: query, end: query + 1})
+	return query
+}
+
+// pop closes the innermost open scope of cmd and returns its end query.
+func (p *Profiler) pop(cmd vk.CommandBuffer, imageIdx int) uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f := &p.frames[imageIdx]
+	open := f.open[cmd]
+	scope := f.scopes[open[len(open)-1]]
+	f.open[cmd] = open[:len(open)-1]
+	return scope.end
+}
+
+// Submitted marks the scopes recorded since Reset as submitted, so the
+// next collect of the image reads them back.
+func (p *Profiler) Submitted(imageIdx int) {
+	if p == nil {
+		return
+	}
+	p.frames[imageIdx].submitted = true
+}
+
+// collect reads the results the image's previous submission left behind.
+// Results that are not available yet are skipped rather than waited for.
+func (p *Profiler) collect(dev vk.Device, imageIdx int) {
+	if p == nil {
+		return
+	}
+	if !p.pending(imageIdx) {
+		return
+	}
+	f := &p.frames[imageIdx]
+	data := make([]uint64, f.used)
+	ret := vk.GetQueryPoolResults(dev, p.pool, p.base(imageIdx), f.used,
+		uint(len(data)*8), unsafe.Pointer(&data[0]), 8,
+		vk.QueryResultFlags(vk.QueryResult64Bit))
+	if ret == vk.NotReady {
+		return
+	}
+	orPanic(as.NewError(ret))
+	p.add(imageIdx, data)
+
+	if time.Since(p.lastLog) >= profilerLogInterval {
+		p.lastLog = time.Now()
+		log.Printf("gpu: %s", strings.Join(p.Report(), ", "))
+	}
+}
+
+// pending reports whether the image has submitted scopes left to read.
+func (p *Profiler) pending(imageIdx int) bool {
+	f := &p.frames[imageIdx]
+	return f.submitted && f.used > 0
+}
+
+// add folds the query results of an image's frame into the averages. The
+// results are only read once.
+func (p *Profiler) add(imageIdx int, data []uint64) {
+	f := &p.frames[imageIdx]
+	f.submitted = false
+	base := p.base(imageIdx)
+	totals := make(map[string]uint64)
+	var names []string
+	for _, scope := range f.scopes {
+		if _, ok := totals[scope.name]; !ok {
+			names = append(names, scope.name)
+		}
+		totals[scope.name] += (data[scope.end-base] - data[scope.begin-base]) & p.mask
+	}
+	for _, name := range names {
+		stat, ok := p.stats[name]
+		if !ok {
+			stat = &rollingAverage{}
+			p.stats[name] = stat
+			p.order = append(p.order, name)
+		}
+		stat.add(float64(totals[name]) * p.period / 1e6)
+	}
+}
+
+// Report lists the rolling average of every scope in milliseconds, in the
+// order the scopes were first seen.
+func (p *Profiler) Report() []string {
+	if p == nil {
+		return nil
+	}
+	lines := make([]string, 0, len(p.order))
+	for _, name := range p.order {
+		lines = append(lines, fmt.Sprintf("%s %.3f ms", name, p.stats[name].average()))
+	}
+	return lines
+}
+
+func (p *Profiler) Destroy(dev vk.Device) {
+	if p == nil {
+		return
+	}
+	vk.DestroyQueryPool(dev, p.pool, nil)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestProfilerCollectsEveryFrame follows the order the application calls
+// the profiler in: a frame is recorded and submitted, and its results are
+// collected when the image comes around again, right before the next
+// frame of the image is recorded.
+func TestProfilerCollectsEveryFrame(t *testing.T) {
+	p := &Profiler{
+		period: 1000,
+		mask:   ^uint64(0),
+		frames: make([]profilerFrame, 2),
+		stats:  make(map[string]*rollingAverage),
+	}
+	const imageIdx = 1
+	for frame := 0; frame < 3; frame++ {
+		p.reset(imageIdx)
+		outer := p.push(nil, imageIdx, "frame")
+		inner := p.push(nil, imageIdx, "compute")
+		if end := p.pop(nil, imageIdx); end != inner+1 {
+			t.Fatalf("compute ends with query %d, want %d", end, inner+1)
+		}
+		if end := p.pop(nil, imageIdx); end != outer+1 {
+			t.Fatalf("frame ends with query %d, want %d", end, outer+1)
+		}
+		if p.pending(imageIdx) {
+			t.Fatalf("frame %d: scopes are pending before being submitted", frame)
+		}
+		p.Submitted(imageIdx)
+
+		if !p.pending(imageIdx) || p.frames[imageIdx].used != 4 {
+			t.Fatalf("frame %d: %d queries, pending %v; want 4 pending", frame,
+				p.frames[imageIdx].used, p.pending(imageIdx))
+		}
+		// Timestamps in ticks of 1 µs: the frame takes 3, compute 1.
+		p.add(imageIdx, []uint64{10, 13, 11, 12})
+		if p.pending(imageIdx) {
+			t.Errorf("frame %d: results are still pending after being read", frame)
+		}
+	}
+	want := []string{"frame 0.003 ms", "compute 0.001 ms"}
+	if got := p.Report(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Report() = %q, want %q", got, want)
+	}
+	if n := len(p.stats["frame"].samples); n != 3 {
+		t.Errorf("frame has %d samples, want 3", n)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recordQueue bounds the frames waiting to be encoded; the render loop
+// blocks once it is full, which only slows a recording down.
+const recordQueue = 8
+
+// Recorder writes a sequence of frames either as numbered PNGs into a
+// directory or as one raw Y4M stream. Frames are encoded on a separate
+// goroutine in the order they were captured.
+type Recorder struct {
+	path  string
+	y4m   bool
+	every int
+	limit int
+	// timestep is the simulated time between frames in seconds; it
+	// replaces the measured frame time while recording.
+	timestep float32
+
+	// seen counts the frames offered, kept those captured for the
+	// recording and written those read back and queued for encoding.
+	// failed is set when reading a frame back failed.
+	seen    int
+	kept    int
+	written int
+	failed  bool
+	frames  chan *image.RGBA
+	done    chan struct{}
+
+	file *os.File
+	w    *bufio.Writer
+}
+
+func newRecorder(cfg Config) (*Recorder, error) {
+	r := &Recorder{
+		path:     cfg.Record,
+		y4m:      strings.HasSuffix(cfg.Record, ".y4m"),
+		every:    cfg.RecordEvery,
+		limit:    cfg.RecordFrames,
+		timestep: 1 / float32(cfg.RecordFPS),
+		frames:   make(chan *image.RGBA, recordQueue),
+		done:     make(chan struct{}),
+	}
+	if r.y4m {
+		f, err := os.Create(r.path)
+		if err != nil {
+			return nil, err
+		}
+		r.file = f
+		r.w = bufio.NewWriter(f)
+	} else if err := os.MkdirAll(r.path, 0755); err != nil {
+		return nil, err
+	}
+	go r.run(cfg.RecordFPS)
+	log.Printf("record: writing every %d. frame to %s at %d FPS", r.every, r.path, cfg.RecordFPS)
+	return r, nil
+}
+
+// due advances the frame counter and reports whether this frame is kept.
+func (r *Recorder) due() bool {
+	r.seen++
+	if (r.seen-1)%r.every != 0 {
+		return false
+	}
+	r.kept++
+	return true
+}
+
+// finished reports whether the requested number of frames was captured,
+// or whether the recording cannot go on.
+func (r *Recorder) finished() bool {
+	return r.failed || (r.limit > 0 && r.kept >= r.limit)
+}
+
+// fail stops the recording after a frame could not be read back.
+func (r *Recorder) fail() {
+	r.failed = true
+}
+
+func (r *Recorder) add(img *image.RGBA) {
+	r.written++
+	r.frames <- img
+}
+
+func (r *Recorder) run(fps int) {
+	defer close(r.done)
+	n := 0
+	for img := range r.frames {
+		var err error
+		if r.y4m {
+			if n == 0 {
+				b := img.Bounds()
+				_, err = fmt.Fprintf(r.w, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C420jpeg\n",
+					b.Dx(), b.Dy(), fps)
+			}
+			if err == nil {
+				err = writeY4MFrame(r.w, img)
+			}
+		} else {
+			err = writePNG(filepath.Join(r.path, fmt.Sprintf("frame-%06d.png", n)), img)
+		}
+		if err != nil {
+			log.Println("record:", err)
+		}
+		n++
+	}
+}
+
+// Close waits for queued frames to be written and closes the output.
+func (r *Recorder) Close() {
+	close(r.frames)
+	<-r.done
+	if r.y4m {
+		if err := r.w.Flush(); err != nil {
+			log.Println("record:", err)
+		}
+		r.file.Close()
+	}
+	log.Printf("record: wrote %d frames to %s", r.written, r.path)
+}
+
+// writeY4MFrame converts img to full-range BT.601 YCbCr with the chroma
+// planes averaged over 2x2 blocks, as C420jpeg expects.
+func writeY4MFrame(w *bufio.Writer, img *image.RGBA) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	cw, ch := (width+1)/2, (height+1)/2
+
+	y := make([]byte, width*height)
+	cb := make([]byte, cw*ch)
+	cr := make([]byte, cw*ch)
+	cbSum := make([]float32, cw*ch)
+	crSum := make([]float32, cw*ch)
+	count := make([]float32, cw*ch)
+	for py := 0; py < height; py++ {
+		for px := 0; px < width; px++ {
+			i := py*img.Stride + px*4
+			r, g, bl := float32(img.Pix[i]), float32(img.Pix[i+1]), float32(img.Pix[i+2])
+			y[py*width+px] = clampByte(0.299*r + 0.587*g + 0.114*bl)
+			c := (py/2)*cw + px/2
+			cbSum[c] += 128 - 0.168736*r - 0.331264*g + 0.5*bl
+			crSum[c] += 128 + 0.5*r - 0.418688*g - 0.081312*bl
+			count[c]++
+		}
+	}
+	for i := range cb {
+		cb[i] = clampByte(cbSum[i] / count[i])
+		cr[i] = clampByte(crSum[i] / count[i])
+	}
+
+	if _, err := w.WriteString("FRAME\n"); err != nil {
+		return err
+	}
+	for _, plane := range [][]byte{y, cb, cr} {
+		if _, err := w.Write(plane); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func clampByte(v float32) byte {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return byte(v + 0.5)
+}
@@ -0,0 +1,968 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./memory"
+)
+
+// PassKind tells the graph whether a pass records inside a render pass.
+type PassKind int
+
+const (
+	ComputePass PassKind = iota
+	GraphicsPass
+)
+
+// GraphImage is an image the graph tracks: the output image of the frame
+// being recorded, or a transient image the graph creates at the size of
+// the swapchain. Transient images only live within a frame, so ones that
+// are never in use at the same time share memory.
+type GraphImage struct {
+	id      int
+	name    string
+	format  vk.Format
+	samples vk.SampleCountFlagBits
+	output  bool
+
+	usage vk.ImageUsageFlagBits
+	// first and last are the indices of the first and last passes using
+	// the image.
+	first, last int
+	// prev is the image that used the memory before this one, which is
+	// the image itself when nothing aliases it.
+	prev  *GraphImage
+	image vk.Image
+	view  vk.ImageView
+}
+
+// GraphBuffer is a buffer created elsewhere whose uses the graph orders.
+type GraphBuffer struct {
+	id     int
+	name   string
+	buffer *Buffer
+}
+
+type useKind int
+
+const (
+	useColor useKind = iota
+	useResolve
+	useDepth
+	useSampled
+	useStorage
+	useBuffer
+)
+
+// resourceUse is one use of an image or buffer by a pass.
+type resourceUse struct {
+	kind   useKind
+	image  *GraphImage
+	buffer *GraphBuffer
+	// resolveOf is the color attachment a resolve attachment resolves.
+	resolveOf *GraphImage
+	// clearColor is read when recording, so it can point at settings.
+	clearColor *[4]float32
+	clearDepth bool
+
+	stage  vk.PipelineStageFlagBits
+	mask   vk.AccessFlagBits
+	write  bool
+	layout vk.ImageLayout
+}
+
+func (u *resourceUse) id() int {
+	if u.image != nil {
+		return u.image.id
+	}
+	return u.buffer.id
+}
+
+func (u *resourceUse) attachment() bool {
+	return u.kind == useColor || u.kind == useResolve || u.kind == useDepth
+}
+
+// GraphPass is a compute or graphics pass. Passes run in the order they
+// were added; their declared uses decide the barriers between them and,
+// for graphics passes, the attachments of their render pass.
+type GraphPass struct {
+	name   string
+	kind   PassKind
+	index  int
+	uses   []*resourceUse
+	record func(cmd vk.CommandBuffer, imageIdx int)
+
+	// attachments are the uses backing the attachments of a graphics
+	// pass, and descs how each is loaded, stored and laid out.
+	attachments []*resourceUse
+	descs       []vk.AttachmentDescription
+
+	renderPass vk.RenderPass
+	// framebuffers has one framebuffer per swapchain image when the pass
+	// renders to the output, and a single one otherwise.
+	framebuffers []vk.Framebuffer
+	// finishesOutput is set when the pass is the last to use the output
+	// image, whose writes its render pass then makes visible to copies.
+	finishesOutput bool
+	// secondary is set when the pass is drawn in secondary command
+	// buffers, which is all its record function may then execute.
+	secondary bool
+}
+
+// RenderGraph records a frame from passes that declare which images and
+// buffers they read and write. Compile creates the output and transient
+// images, the render passes and the framebuffers; barriers and layout
+// transitions are worked out while recording, so buffer uses may still be
+// declared after compiling.
+//
+// The frame is not rendered into the swapchain image itself: frames are
+// submitted before the swapchain image is known to be free, so each
+// swapchain image has an output image of its own, which ends the frame in
+// the transfer source layout and is then drawn into the swapchain image by
+// the present pass.
+type RenderGraph struct {
+	dev       vk.Device
+	allocator *memory.Allocator
+	debug     *DebugUtils
+	width     uint32
+	height    uint32
+
+	output       *GraphImage
+	outputImages []vk.Image
+	outputViews  []vk.ImageView
+	outputCount  int
+
+	passes   []*GraphPass
+	images   []*GraphImage
+	buffers  []*GraphBuffer
+	allocs   []*memory.Allocation
+	compiled bool
+}
+
+// newRenderGraph starts a graph whose output has the given format, with
+// one output image for each of imageCount swapchain images.
+func newRenderGraph(dev vk.Device, allocator *memory.Allocator, debug *DebugUtils,
+	width, height uint32, format vk.Format, imageCount int) *RenderGraph {
+
+	g := &RenderGraph{
+		dev:         dev,
+		allocator:   allocator,
+		debug:       debug,
+		width:       width,
+		height:      height,
+		outputCount: imageCount,
+	}
+	g.output = g.newImage("output", format, vk.SampleCount1Bit)
+	g.output.output = true
+	return g
+}
+
+func (g *RenderGraph) newImage(name string, format vk.Format, samples vk.SampleCountFlagBits) *GraphImage {
+	img := &GraphImage{
+		id:      len(g.images) + len(g.buffers),
+		name:    name,
+		format:  format,
+		samples: samples,
+		first:   -1,
+	}
+	img.prev = img
+	g.images = append(g.images, img)
+	return img
+}
+
+// Output is the image drawn into the swapchain image at the end of the
+// frame.
+func (g *RenderGraph) Output() *GraphImage {
+	return g.output
+}
+
+// OutputImage returns the output image of a swapchain image, which is in
+// the transfer source layout once the frame has been recorded.
+func (g *RenderGraph) OutputImage(imageIdx int) vk.Image {
+	return g.outputImages[imageIdx]
+}
+
+// OutputView returns the view of the output image of a swapchain image.
+func (g *RenderGraph) OutputView(imageIdx int) vk.ImageView {
+	return g.outputViews[imageIdx]
+}
+
+// Transient declares an image created by Compile and discarded at the end
+// of every frame.
+func (g *RenderGraph) Transient(name string, format vk.Format, samples vk.SampleCountFlagBits) *GraphImage {
+	if g.compiled {
+		log.Panicf("render graph: transient image %q declared after compiling", name)
+	}
+	return g.newImage(name, format, samples)
+}
+
+// ImportBuffer lets passes declare uses of buf.
+func (g *RenderGraph) ImportBuffer(name string, buf *Buffer) *GraphBuffer {
+	b := &GraphBuffer{
+		id:     len(g.images) + len(g.buffers),
+		name:   name,
+		buffer: buf,
+	}
+	g.buffers = append(g.buffers, b)
+	return b
+}
+
+// AddPass appends a pass that runs record. Graphics passes record inside
+// their render pass.
+func (g *RenderGraph) AddPass(name string, kind PassKind, record func(cmd vk.CommandBuffer, imageIdx int)) *GraphPass {
+	if g.compiled {
+		log.Panicf("render graph: pass %q added after compiling", name)
+	}
+	p := &GraphPass{
+		name:   name,
+		kind:   kind,
+		index:  len(g.passes),
+		record: record,
+	}
+	g.passes = append(g.passes, p)
+	return p
+}
+
+// Pass returns the pass with the given name.
+func (g *RenderGraph) Pass(name string) *GraphPass {
+	for _, p := range g.passes {
+		if p.name == name {
+			return p
+		}
+	}
+	log.Panicf("render graph: no pass named %q", name)
+	return nil
+}
+
+// RenderPass is the render pass of a compiled graphics pass, which its
+// pipelines must be compatible with.
+func (p *GraphPass) RenderPass() vk.RenderPass {
+	return p.renderPass
+}
+
+// Secondary makes the contents of a graphics pass secondary command
+// buffers, recorded with the inheritance info of Inheritance.
+func (p *GraphPass) Secondary() *GraphPass {
+	if p.kind != GraphicsPass {
+		log.Panicf("render graph: compute pass %q cannot execute secondary command buffers", p.name)
+	}
+	p.secondary = true
+	return p
+}
+
+// Inheritance describes the render pass and framebuffer that secondary
+// command buffers executed in p for the given swapchain image continue.
+func (p *GraphPass) Inheritance(imageIdx int) vk.CommandBufferInheritanceInfo {
+	return vk.CommandBufferInheritanceInfo{
+		SType:       vk.StructureTypeCommandBufferInheritanceInfo,
+		RenderPass:  p.renderPass,
+		Framebuffer: p.framebuffer(imageIdx),
+	}
+}
+
+func (p *GraphPass) framebuffer(imageIdx int) vk.Framebuffer {
+	if len(p.framebuffers) > 1 {
+		return p.framebuffers[imageIdx]
+	}
+	return p.framebuffers[0]
+}
+
+func (p *GraphPass) add(u *resourceUse) *GraphPass {
+	if u.attachment() && p.kind != GraphicsPass {
+		log.Panicf("render graph: compute pass %q cannot have attachments", p.name)
+	}
+	p.uses = append(p.uses, u)
+	return p
+}
+
+// Color renders into img, cleared to *clear first when clear is not nil.
+func (p *GraphPass) Color(img *GraphImage, clear *[4]float32) *GraphPass {
+	return p.add(&resourceUse{
+		kind:       useColor,
+		image:      img,
+		clearColor: clear,
+		stage:      vk.PipelineStageColorAttachmentOutputBit,
+		mask:       vk.AccessColorAttachmentReadBit | vk.AccessColorAttachmentWriteBit,
+		write:      true,
+		layout:     vk.ImageLayoutColorAttachmentOptimal,
+	})
+}
+
+// Resolve resolves the multisampled color attachment src into dst at the
+// end of the pass.
+func (p *GraphPass) Resolve(src, dst *GraphImage) *GraphPass {
+	return p.add(&resourceUse{
+		kind:      useResolve,
+		image:     dst,
+		resolveOf: src,
+		stage:     vk.PipelineStageColorAttachmentOutputBit,
+		mask:      vk.AccessColorAttachmentWriteBit,
+		write:     true,
+		layout:    vk.ImageLayoutColorAttachmentOptimal,
+	})
+}
+
+// Depth uses img as the depth attachment, cleared to 1 when clear is set.
+func (p *GraphPass) Depth(img *GraphImage, clear bool) *GraphPass {
+	return p.add(&resourceUse{
+		kind:       useDepth,
+		image:      img,
+		clearDepth: clear,
+		stage:      vk.PipelineStageEarlyFragmentTestsBit | vk.PipelineStageLateFragmentTestsBit,
+		mask:       vk.AccessDepthStencilAttachmentReadBit | vk.AccessDepthStencilAttachmentWriteBit,
+		write:      true,
+		layout:     vk.ImageLayoutDepthStencilAttachmentOptimal,
+	})
+}
+
+// Sample reads img through a sampler in the given shader stages.
+func (p *GraphPass) Sample(img *GraphImage, stage vk.PipelineStageFlagBits) *GraphPass {
+	return p.add(&resourceUse{
+		kind:   useSampled,
+		image:  img,
+		stage:  stage,
+		mask:   vk.AccessShaderReadBit,
+		layout: vk.ImageLayoutShaderReadOnlyOptimal,
+	})
+}
+
+// Storage accesses img as a storage image in the given shader stages.
+func (p *GraphPass) Storage(img *GraphImage, stage vk.PipelineStageFlagBits, write bool) *GraphPass {
+	mask := vk.AccessShaderReadBit
+	if write {
+		mask |= vk.AccessShaderWriteBit
+	}
+	return p.add(&resourceUse{
+		kind:   useStorage,
+		image:  img,
+		stage:  stage,
+		mask:   mask,
+		write:  write,
+		layout: vk.ImageLayoutGeneral,
+	})
+}
+
+// ReadBuffer reads buf with the given access in the given stages.
+func (p *GraphPass) ReadBuffer(buf *GraphBuffer, stage vk.PipelineStageFlagBits, access vk.AccessFlagBits) *GraphPass {
+	return p.add(&resourceUse{
+		kind:   useBuffer,
+		buffer: buf,
+		stage:  stage,
+		mask:   access,
+	})
+}
+
+// WriteBuffer writes, and possibly reads, buf with the given access in the
+// given stages.
+func (p *GraphPass) WriteBuffer(buf *GraphBuffer, stage vk.PipelineStageFlagBits, access vk.AccessFlagBits) *GraphPass {
+	return p.add(&resourceUse{
+		kind:   useBuffer,
+		buffer: buf,
+		stage:  stage,
+		mask:   access,
+		write:  true,
+	})
+}
+
+// Compile creates the output images, the transient images with memory
+// shared between images whose passes do not overlap, then a render pass
+// and framebuffers for every graphics pass.
+func (g *RenderGraph) Compile() error {
+	if g.compiled {
+		return errors.New("render graph: already compiled")
+	}
+	for _, p := range g.passes {
+		for _, u := range p.uses {
+			if u.image == nil {
+				continue
+			}
+			if u.image.first < 0 {
+				u.image.first = p.index
+			}
+			u.image.last = p.index
+			u.image.usage |= imageUsage(u.kind)
+		}
+	}
+	if err := g.createOutputImages(); err != nil {
+		return err
+	}
+	if err := g.createImages(); err != nil {
+		return err
+	}
+	for _, p := range g.passes {
+		if p.kind != GraphicsPass {
+			continue
+		}
+		if err := g.planAttachments(p); err != nil {
+			return err
+		}
+		if err := g.createRenderPass(p); err != nil {
+			return err
+		}
+		g.createFramebuffers(p)
+	}
+	g.compiled = true
+	return nil
+}
+
+func imageUsage(kind useKind) vk.ImageUsageFlagBits {
+	switch kind {
+	case useColor, useResolve:
+		return vk.ImageUsageColorAttachmentBit
+	case useDepth:
+		return vk.ImageUsageDepthStencilAttachmentBit
+	case useSampled:
+		return vk.ImageUsageSampledBit
+	case useStorage:
+		return vk.ImageUsageStorageBit
+	}
+	return 0
+}
+
+func formatAspect(format vk.Format) vk.ImageAspectFlagBits {
+	switch format {
+	case vk.FormatD16Unorm, vk.FormatD32Sfloat, vk.FormatX8D24UnormPack32:
+		return vk.ImageAspectDepthBit
+	case vk.FormatD16UnormS8Uint, vk.FormatD24UnormS8Uint, vk.FormatD32SfloatS8Uint:
+		return vk.ImageAspectDepthBit | vk.ImageAspectStencilBit
+	case vk.FormatS8Uint:
+		return vk.ImageAspectStencilBit
+	}
+	return vk.ImageAspectColorBit
+}
+
+// aliasSlot is memory shared by transient images used one after another.
+type aliasSlot struct {
+	reqs   vk.MemoryRequirements
+	last   int
+	images []*GraphImage
+}
+
+// createOutputImages creates the output image of every swapchain image.
+// They are in use from one frame to the next, so they never share memory.
+func (g *RenderGraph) createOutputImages() error {
+	out := g.output
+	if out.first < 0 {
+		return errors.New("render graph: no pass renders the output")
+	}
+	for i := 0; i < g.outputCount; i++ {
+		name := fmt.Sprintf("%s %d", out.name, i)
+		var image vk.Image
+		ret := vk.CreateImage(g.dev, &vk.ImageCreateInfo{
+			SType:     vk.StructureTypeImageCreateInfo,
+			ImageType: vk.ImageType2d,
+			Format:    out.format,
+			Extent: vk.Extent3D{
+				Width:  g.width,
+				Height: g.height,
+				Depth:  1,
+			},
+			MipLevels:   1,
+			ArrayLayers: 1,
+			Samples:     out.samples,
+			Tiling:      vk.ImageTilingOptimal,
+			Usage:       vk.ImageUsageFlags(out.usage | vk.ImageUsageTransferSrcBit | vk.ImageUsageSampledBit),
+		}, nil, &image)
+		if err := vk.Error(ret); err != nil {
+			return fmt.Errorf("render graph: creating %s: %v", name, err)
+		}
+		g.outputImages = append(g.outputImages, image)
+		g.debug.Name(image, name)
+
+		var reqs vk.MemoryRequirements
+		vk.GetImageMemoryRequirements(g.dev, image, &reqs)
+		reqs.Deref()
+		alloc, err := g.allocator.Allocate(reqs, vk.MemoryPropertyDeviceLocalBit, memory.Optimal)
+		if err != nil {
+			return fmt.Errorf("render graph: allocating %s: %v", name, err)
+		}
+		g.allocs = append(g.allocs, alloc)
+		ret = vk.BindImageMemory(g.dev, image, alloc.Memory, alloc.Offset)
+		if err := vk.Error(ret); err != nil {
+			return fmt.Errorf("render graph: binding %s: %v", name, err)
+		}
+		var view vk.ImageView
+		ret = vk.CreateImageView(g.dev, &vk.ImageViewCreateInfo{
+			SType:    vk.StructureTypeImageViewCreateInfo,
+			Image:    image,
+			ViewType: vk.ImageViewType2d,
+			Format:   out.format,
+			SubresourceRange: vk.ImageSubresourceRange{
+				AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+				LevelCount: 1,
+				LayerCount: 1,
+			},
+		}, nil, &view)
+		if err := vk.Error(ret); err != nil {
+			return fmt.Errorf("render graph: creating %s view: %v", name, err)
+		}
+		g.outputViews = append(g.outputViews, view)
+		g.debug.Name(view, name+" view")
+	}
+	return nil
+}
+
+func (g *RenderGraph) createImages() error {
+	var transient []*GraphImage
+	for _, img := range g.images {
+		if img.output {
+			continue
+		}
+		if img.first < 0 {
+			return fmt.Errorf("render graph: transient image %q is never used", img.name)
+		}
+		transient = append(transient, img)
+	}
+	sort.SliceStable(transient, func(i, j int) bool {
+		return transient[i].first < transient[j].first
+	})
+
+	var slots []*aliasSlot
+	for _, img := range transient {
+		usage := img.usage
+		const attachments = vk.ImageUsageColorAttachmentBit | vk.ImageUsageDepthStencilAttachmentBit
+		if usage&^attachments == 0 && img.first == img.last {
+			// The contents never leave the pass, so tilers can keep
+			// them on chip.
+			usage |= vk.ImageUsageTransientAttachmentBit
+		}
+		ret := vk.CreateImage(g.dev, &vk.ImageCreateInfo{
+			SType:     vk.StructureTypeImageCreateInfo,
+			ImageType: vk.ImageType2d,
+			Format:    img.format,
+			Extent: vk.Extent3D{
+				Width:  g.width,
+				Height: g.height,
+				Depth:  1,
+			},
+			MipLevels:   1,
+			ArrayLayers: 1,
+			Samples:     img.samples,
+			Tiling:      vk.ImageTilingOptimal,
+			Usage:       vk.ImageUsageFlags(usage),
+		}, nil, &img.image)
+		if err := vk.Error(ret); err != nil {
+			return fmt.Errorf("render graph: creating %s: %v", img.name, err)
+		}
+		g.debug.Name(img.image, img.name)
+
+		var reqs vk.MemoryRequirements
+		vk.GetImageMemoryRequirements(g.dev, img.image, &reqs)
+		reqs.Deref()
+		var slot *aliasSlot
+		for _, s := range slots {
+			if s.last < img.first && s.reqs.MemoryTypeBits&reqs.MemoryTypeBits != 0 {
+				slot = s
+				break
+			}
+		}
+		if slot == nil {
+			slot = &aliasSlot{reqs: reqs}
+			slots = append(slots, slot)
+		} else {
+			if reqs.Size > slot.reqs.Size {
+				slot.reqs.Size = reqs.Size
+			}
+			if reqs.Alignment > slot.reqs.Alignment {
+				slot.reqs.Alignment = reqs.Alignment
+			}
+			slot.reqs.MemoryTypeBits &= reqs.MemoryTypeBits
+		}
+		slot.last = img.last
+		slot.images = append(slot.images, img)
+	}
+
+	for _, slot := range slots {
+		alloc, err := g.allocator.Allocate(slot.reqs, vk.MemoryPropertyDeviceLocalBit, memory.Optimal)
+		if err != nil {
+			return fmt.Errorf("render graph: allocating %s: %v", slot.images[0].name, err)
+		}
+		g.allocs = append(g.allocs, alloc)
+		for i, img := range slot.images {
+			// Each image waits for the one before it in the slot; the
+			// first waits for the last of the previous frame.
+			img.prev = slot.images[(i+len(slot.images)-1)%len(slot.images)]
+			ret := vk.BindImageMemory(g.dev, img.image, alloc.Memory, alloc.Offset)
+			if err := vk.Error(ret); err != nil {
+				return fmt.Errorf("render graph: binding %s: %v", img.name, err)
+			}
+			ret = vk.CreateImageView(g.dev, &vk.ImageViewCreateInfo{
+				SType:    vk.StructureTypeImageViewCreateInfo,
+				Image:    img.image,
+				ViewType: vk.ImageViewType2d,
+				Format:   img.format,
+				SubresourceRange: vk.ImageSubresourceRange{
+					AspectMask: vk.ImageAspectFlags(formatAspect(img.format)),
+					LevelCount: 1,
+					LayerCount: 1,
+				},
+			}, nil, &img.view)
+			if err := vk.Error(ret); err != nil {
+				return fmt.Errorf("render graph: creating %s view: %v", img.name, err)
+			}
+			g.debug.Name(img.view, img.name+" view")
+		}
+	}
+	if len(slots) < len(transient) {
+		log.Printf("render graph: %d transient images share %d allocations", len(transient), len(slots))
+	}
+	return nil
+}
+
+// planAttachments decides how p loads, stores and lays out each of its
+// attachments. Layout transitions are left to the barriers recorded before
+// the pass, so every attachment starts and ends in the layout it is used
+// in, except that the output image ends ready to be copied after its last
+// use.
+func (g *RenderGraph) planAttachments(p *GraphPass) error {
+	var depths int
+	for _, u := range p.uses {
+		if !u.attachment() {
+			continue
+		}
+		img := u.image
+		desc := vk.AttachmentDescription{
+			Format:         img.format,
+			Samples:        img.samples,
+			LoadOp:         vk.AttachmentLoadOpDontCare,
+			StoreOp:        vk.AttachmentStoreOpDontCare,
+			StencilLoadOp:  vk.AttachmentLoadOpDontCare,
+			StencilStoreOp: vk.AttachmentStoreOpDontCare,
+			InitialLayout:  u.layout,
+			FinalLayout:    u.layout,
+		}
+		switch {
+		case u.clearColor != nil || u.clearDepth:
+			desc.LoadOp = vk.AttachmentLoadOpClear
+		case u.kind != useResolve && p.index > img.first:
+			desc.LoadOp = vk.AttachmentLoadOpLoad
+		}
+		if img.output || p.index < img.last {
+			desc.StoreOp = vk.AttachmentStoreOpStore
+		}
+		if img.output && p.index == img.last {
+			desc.FinalLayout = vk.ImageLayoutTransferSrcOptimal
+			p.finishesOutput = true
+		}
+		if formatAspect(img.format)&vk.ImageAspectStencilBit != 0 {
+			desc.StencilLoadOp, desc.StencilStoreOp = desc.LoadOp, desc.StoreOp
+		}
+		if u.kind == useDepth {
+			depths++
+		}
+		p.attachments = append(p.attachments, u)
+		p.descs = append(p.descs, desc)
+	}
+	switch {
+	case len(p.attachments) == 0:
+		return fmt.Errorf("render graph: graphics pass %q has no attachments", p.name)
+	case depths > 1:
+		return fmt.Errorf("render graph: pass %q has more than one depth attachment", p.name)
+	}
+	for _, u := range p.attachments {
+		if u.kind != useResolve {
+			continue
+		}
+		resolvesColor := false
+		for _, c := range p.attachments {
+			resolvesColor = resolvesColor || (c.kind == useColor && c.image == u.resolveOf)
+		}
+		if !resolvesColor {
+			return fmt.Errorf("render graph: pass %q resolves an image it does not render to", p.name)
+		}
+	}
+	return nil
+}
+
+// createRenderPass builds a single-subpass render pass from the planned
+// attachments of p.
+func (g *RenderGraph) createRenderPass(p *GraphPass) error {
+	subpass := vk.SubpassDescription{
+		PipelineBindPoint: vk.PipelineBindPointGraphics,
+	}
+	var colors []*GraphImage
+	resolves := make(map[*GraphImage]uint32)
+	for i, u := range p.attachments {
+		ref := vk.AttachmentReference{Attachment: uint32(i), Layout: u.layout}
+		switch u.kind {
+		case useColor:
+			colors = append(colors, u.image)
+			subpass.PColorAttachments = append(subpass.PColorAttachments, ref)
+		case useResolve:
+			resolves[u.resolveOf] = uint32(i)
+		case useDepth:
+			subpass.PDepthStencilAttachment = &ref
+		}
+	}
+	subpass.ColorAttachmentCount = uint32(len(colors))
+	if len(resolves) > 0 {
+		for _, img := range colors {
+			ref := vk.AttachmentReference{
+				Attachment: vk.AttachmentUnused,
+				Layout:     vk.ImageLayoutUndefined,
+			}
+			if index, ok := resolves[img]; ok {
+				ref = vk.AttachmentReference{
+					Attachment: index,
+					Layout:     vk.ImageLayoutColorAttachmentOptimal,
+				}
+			}
+			subpass.PResolveAttachments = append(subpass.PResolveAttachments, ref)
+		}
+	}
+
+	info := vk.RenderPassCreateInfo{
+		SType:           vk.StructureTypeRenderPassCreateInfo,
+		AttachmentCount: uint32(len(p.descs)),
+		PAttachments:    p.descs,
+		SubpassCount:    1,
+		PSubpasses:      []vk.SubpassDescription{subpass},
+	}
+	if p.finishesOutput {
+		// The output is copied and read back after the pass, so its final
+		// layout transition must make the writes visible to transfers.
+		info.DependencyCount = 1
+		info.PDependencies = []vk.SubpassDependency{{
+			SrcSubpass:    0,
+			DstSubpass:    vk.SubpassExternal,
+			SrcStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+			DstStageMask:  vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+			SrcAccessMask: vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+			DstAccessMask: vk.AccessFlags(vk.AccessTransferReadBit),
+		}}
+	}
+	ret := vk.CreateRenderPass(g.dev, &info, nil, &p.renderPass)
+	if err := vk.Error(ret); err != nil {
+		return fmt.Errorf("render graph: creating render pass %q: %v", p.name, err)
+	}
+	g.debug.Name(p.renderPass, p.name+" render pass")
+	return nil
+}
+
+func (g *RenderGraph) imageView(img *GraphImage, imageIdx int) vk.ImageView {
+	if img.output {
+		return g.outputViews[imageIdx]
+	}
+	return img.view
+}
+
+func (g *RenderGraph) createFramebuffers(p *GraphPass) {
+	count := 1
+	for _, u := range p.attachments {
+		if u.image.output {
+			count = len(g.outputViews)
+		}
+	}
+	for i := 0; i < count; i++ {
+		views := make([]vk.ImageView, 0, len(p.attachments))
+		for _, u := range p.attachments {
+			views = append(views, g.imageView(u.image, i))
+		}
+		var fb vk.Framebuffer
+		ret := vk.CreateFramebuffer(g.dev, &vk.FramebufferCreateInfo{
+			SType:           vk.StructureTypeFramebufferCreateInfo,
+			RenderPass:      p.renderPass,
+			AttachmentCount: uint32(len(views)),
+			PAttachments:    views,
+			Width:           g.width,
+			Height:          g.height,
+			Layers:          1,
+		}, nil, &fb)
+		orPanic(as.NewError(ret))
+		g.debug.Name(fb, fmt.Sprintf("%s framebuffer %d", p.name, i))
+		p.framebuffers = append(p.framebuffers, fb)
+	}
+}
+
+// hazardState is what the barrier before the next use of a resource has
+// to wait for.
+type hazardState struct {
+	writeStage vk.PipelineStageFlagBits
+	writeMask  vk.AccessFlagBits
+	readStages vk.PipelineStageFlagBits
+	layout     vk.ImageLayout
+}
+
+// next moves s past u and returns the source scope of the barrier u needs,
+// with ok false when it needs none.
+func (s *hazardState) next(u *resourceUse) (stage vk.PipelineStageFlagBits, mask vk.AccessFlagBits, ok bool) {
+	transition := u.layout != s.layout
+	switch {
+	case u.write || transition:
+		stage, mask = s.writeStage|s.readStages, s.writeMask
+		ok = stage != 0 || transition
+		if u.write {
+			*s = hazardState{writeStage: u.stage, writeMask: u.mask, layout: u.layout}
+		} else {
+			// Later uses must wait for the layout transition too.
+			*s = hazardState{writeStage: u.stage, readStages: u.stage, layout: u.layout}
+		}
+	case s.writeStage != 0 && s.readStages&u.stage != u.stage:
+		stage, mask, ok = s.writeStage, s.writeMask, true
+		s.readStages |= u.stage
+	}
+	if ok && stage == 0 {
+		stage = vk.PipelineStageTopOfPipeBit
+	}
+	return stage, mask, ok
+}
+
+// initialStates returns the state of every resource when a frame starts,
+// which is where the previous frame left it. Transient contents do not
+// survive, and an aliased image starts where the image before it in the
+// same memory left off.
+func (g *RenderGraph) initialStates() []hazardState {
+	end := make([]hazardState, len(g.images)+len(g.buffers))
+	for _, p := range g.passes {
+		for _, u := range p.uses {
+			end[u.id()].next(u)
+		}
+	}
+	start := make([]hazardState, len(end))
+	for _, b := range g.buffers {
+		start[b.id] = end[b.id]
+	}
+	for _, img := range g.images {
+		if img.output {
+			// The previous frame of the image ended with the present pass
+			// sampling it; its contents are not needed.
+			start[img.id] = hazardState{readStages: vk.PipelineStageFragmentShaderBit}
+			continue
+		}
+		s := end[img.prev.id]
+		s.layout = vk.ImageLayoutUndefined
+		start[img.id] = s
+	}
+	return start
+}
+
+func (g *RenderGraph) imageHandle(img *GraphImage, imageIdx int) vk.Image {
+	if img.output {
+		return g.outputImages[imageIdx]
+	}
+	return img.image
+}
+
+func (g *RenderGraph) recordBarriers(cmd vk.CommandBuffer, p *GraphPass, states []hazardState, imageIdx int) {
+	var srcStages, dstStages vk.PipelineStageFlagBits
+	var bufferBarriers []vk.BufferMemoryBarrier
+	var imageBarriers []vk.ImageMemoryBarrier
+	for _, u := range p.uses {
+		s := &states[u.id()]
+		oldLayout := s.layout
+		stage, mask, ok := s.next(u)
+		if !ok {
+			continue
+		}
+		srcStages |= stage
+		dstStages |= u.stage
+		if u.buffer != nil {
+			bufferBarriers = append(bufferBarriers, vk.BufferMemoryBarrier{
+				SType:               vk.StructureTypeBufferMemoryBarrier,
+				SrcAccessMask:       vk.AccessFlags(mask),
+				DstAccessMask:       vk.AccessFlags(u.mask),
+				SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+				DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+				Buffer:              u.buffer.buffer.buffer,
+				Size:                vk.DeviceSize(vk.WholeSize),
+			})
+			continue
+		}
+		imageBarriers = append(imageBarriers, vk.ImageMemoryBarrier{
+			SType:               vk.StructureTypeImageMemoryBarrier,
+			SrcAccessMask:       vk.AccessFlags(mask),
+			DstAccessMask:       vk.AccessFlags(u.mask),
+			OldLayout:           oldLayout,
+			NewLayout:           u.layout,
+			SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+			DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+			Image:               g.imageHandle(u.image, imageIdx),
+			SubresourceRange: vk.ImageSubresourceRange{
+				AspectMask: vk.ImageAspectFlags(formatAspect(u.image.format)),
+				LevelCount: 1,
+				LayerCount: 1,
+			},
+		})
+	}
+	if len(bufferBarriers) == 0 && len(imageBarriers) == 0 {
+		return
+	}
+	vk.CmdPipelineBarrier(cmd,
+		vk.PipelineStageFlags(srcStages), vk.PipelineStageFlags(dstStages), 0,
+		0, nil,
+		uint32(len(bufferBarriers)), bufferBarriers,
+		uint32(len(imageBarriers)), imageBarriers)
+}
+
+// Record records every pass into cmd for the given swapchain image, each
+// preceded by the barriers its uses need.
+func (g *RenderGraph) Record(cmd vk.CommandBuffer, imageIdx int) {
+	if !g.compiled {
+		log.Panicln("render graph: recording before compiling")
+	}
+	states := g.initialStates()
+	for _, p := range g.passes {
+		g.recordBarriers(cmd, p, states, imageIdx)
+		if p.kind != GraphicsPass {
+			p.record(cmd, imageIdx)
+			continue
+		}
+		clearValues := make([]vk.ClearValue, len(p.attachments))
+		for i, u := range p.attachments {
+			switch {
+			case u.clearColor != nil:
+				clearValues[i].SetColor(u.clearColor[:])
+			case u.clearDepth:
+				clearValues[i].SetDepthStencil(1, 0)
+			}
+		}
+		contents := vk.SubpassContentsInline
+		if p.secondary {
+			contents = vk.SubpassContentsSecondaryCommandBuffers
+		}
+		vk.CmdBeginRenderPass(cmd, &vk.RenderPassBeginInfo{
+			SType:       vk.StructureTypeRenderPassBeginInfo,
+			RenderPass:  p.renderPass,
+			Framebuffer: p.framebuffer(imageIdx),
+			RenderArea: vk.Rect2D{
+				Extent: vk.Extent2D{
+					Width:  g.width,
+					Height: g.height,
+				},
+			},
+			ClearValueCount: uint32(len(clearValues)),
+			PClearValues:    clearValues,
+		}, contents)
+		p.record(cmd, imageIdx)
+		vk.CmdEndRenderPass(cmd)
+	}
+}
+
+func (g *RenderGraph) Destroy() {
+	for _, p := range g.passes {
+		for _, fb := range p.framebuffers {
+			vk.DestroyFramebuffer(g.dev, fb, nil)
+		}
+		vk.DestroyRenderPass(g.dev, p.renderPass, nil)
+	}
+	for _, img := range g.images {
+		if img.output {
+			continue
+		}
+		vk.DestroyImageView(g.dev, img.view, nil)
+		vk.DestroyImage(g.dev, img.image, nil)
+	}
+	for i, image := range g.outputImages {
+		vk.DestroyImageView(g.dev, g.outputViews[i], nil)
+		vk.DestroyImage(g.dev, image, nil)
+	}
+	for _, alloc := range g.allocs {
+		alloc.Free()
+	}
+}
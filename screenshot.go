@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// screenshotPath names a new screenshot after the current time, down to
+// the millisecond so quick successive captures do not collide.
+func (a *Application) screenshotPath() string {
+	name := "screenshot-" + time.Now().Format("20060102-150405.000") + ".png"
+	return filepath.Join(a.config.Screenshots, name)
+}
+
+// RequestScreenshot captures the next frame to path; an empty
+// path picks a timestamped name in the screenshots directory.
+func (a *Application) RequestScreenshot(path string) {
+	if path == "" {
+		path = a.screenshotPath()
+	}
+	a.screenshotPending = path
+}
+
+// outputSwizzle reports whether pixels of the output format need their red
+// and blue channels swapped to become RGBA.
+func outputSwizzle(format vk.Format) (bool, error) {
+	switch format {
+	case vk.FormatB8g8r8a8Unorm, vk.FormatB8g8r8a8Srgb:
+		return true, nil
+	case vk.FormatR8g8b8a8Unorm, vk.FormatR8g8b8a8Srgb:
+		return false, nil
+	}
+	return false, fmt.Errorf("unsupported output format %d", format)
+}
+
+// recordReadback copies the output image of a frame into the frame's
+// readback buffer, after the graph has been recorded and left the image in
+// the transfer source layout. The present pass samples it afterwards.
+func (a *Application) recordReadback(cmd vk.CommandBuffer, imageIdx int) {
+	f := a.frames[imageIdx]
+	size := vk.DeviceSize(a.width * a.height * 4)
+	if f.readback != nil && f.readback.size != size {
+		f.readback.Destroy(a.Context().Device())
+		f.readback = nil
+	}
+	if f.readback == nil {
+		f.readback = a.newBuffer(int(size), vk.BufferUsageTransferDstBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		a.debug.NameBuffer(f.readback, fmt.Sprintf("readback %d", imageIdx))
+	}
+
+	output := a.graph.OutputImage(imageIdx)
+	vk.CmdPipelineBarrier(cmd,
+		vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+		vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+		0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{{
+			SType:               vk.StructureTypeImageMemoryBarrier,
+			SrcAccessMask:       vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+			DstAccessMask:       vk.AccessFlags(vk.AccessTransferReadBit),
+			OldLayout:           vk.ImageLayoutTransferSrcOptimal,
+			NewLayout:           vk.ImageLayoutTransferSrcOptimal,
+			SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+			DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+			Image:               output,
+			SubresourceRange: vk.ImageSubresourceRange{
+				AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+				LevelCount: 1,
+				LayerCount: 1,
+			},
+		}})
+	vk.CmdCopyImageToBuffer(cmd, output, vk.ImageLayoutTransferSrcOptimal,
+		f.readback.buffer, 1, []vk.BufferImageCopy{{
+			ImageSubresource: vk.ImageSubresourceLayers{
+				AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+				LayerCount: 1,
+			},
+			ImageExtent: vk.Extent3D{
+				Width:  a.width,
+				Height: a.height,
+				Depth:  1,
+			},
+		}})
+	vk.CmdPipelineBarrier(cmd,
+		vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+		vk.PipelineStageFlags(vk.PipelineStageHostBit),
+		0, 0, nil, 1, []vk.BufferMemoryBarrier{{
+			SType:               vk.StructureTypeBufferMemoryBarrier,
+			SrcAccessMask:       vk.AccessFlags(vk.AccessTransferWriteBit),
+			DstAccessMask:       vk.AccessFlags(vk.AccessHostReadBit),
+			SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+			DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+			Buffer:              f.readback.buffer,
+			Size:                vk.DeviceSize(vk.WholeSize),
+		}}, 0, nil)
+}
+
+// frameCapture is what becomes of the output a frame copies into its
+// readback buffer: it is written to path, as the result of a headless run
+// when headless is set, and handed to the recorder when record is set.
+// width and height are the size of the output when it was copied.
+type frameCapture struct {
+	path          string
+	headless      bool
+	record        bool
+	width, height uint32
+}
+
+func (c frameCapture) wanted() bool {
+	return c.path != "" || c.record
+}
+
+// readCaptures reads back the captured frames in a.captureQueue, in the
+// order they were submitted, up to and including the one of the swapchain
+// image last; -1 reads them all. Keeping the order keeps recordings in
+// order even when images are acquired out of turn. The frames have mostly
+// run by the time their image comes around again, so this rarely waits.
+func (a *Application) readCaptures(last int) {
+	for len(a.captureQueue) > 0 {
+		imageIdx := a.captureQueue[0]
+		a.captureQueue = a.captureQueue[1:]
+		a.readCapture(imageIdx)
+		if imageIdx == last {
+			return
+		}
+	}
+}
+
+// readCapture reads back the output of a frame and passes it on: to the
+// recorder, which encodes it on its own goroutine, and to a PNG written on
+// another goroutine that a.captures tracks.
+func (a *Application) readCapture(imageIdx int) {
+	f := a.frames[imageIdx]
+	c := f.capture
+	f.capture = frameCapture{}
+	prefix := "screenshot:"
+	if c.headless {
+		prefix = "headless:"
+	}
+	img, err := a.readOutput(f, c)
+	if err != nil {
+		if c.headless {
+			log.Fatalln(prefix, err)
+		}
+		log.Println("capture:", err)
+		if c.record {
+			a.recorder.fail()
+		}
+		return
+	}
+	if c.record {
+		a.recorder.add(img)
+	}
+	if c.path == "" {
+		return
+	}
+	a.captures.Add(1)
+	go func() {
+		defer a.captures.Done()
+		if err := writePNG(c.path, img); err != nil {
+			if c.headless {
+				log.Fatalln(prefix, err)
+			}
+			log.Println(prefix, err)
+			return
+		}
+		log.Println(prefix, "wrote", c.path)
+	}()
+}
+
+// readOutput waits for a frame to finish and returns the output it copied
+// into its readback buffer as RGBA.
+func (a *Application) readOutput(f *Frame, c frameCapture) (*image.RGBA, error) {
+	dev := a.Context().Device()
+	swizzle, err := outputSwizzle(a.Context().SwapchainDimensions().Format)
+	if err != nil {
+		return nil, err
+	}
+	ret := vk.WaitForFences(dev, 1, []vk.Fence{f.fence}, vk.True, vk.MaxUint64)
+	orPanic(as.NewError(ret))
+
+	img := image.NewRGBA(image.Rect(0, 0, int(c.width), int(c.height)))
+	f.readback.Read(0, img.Pix)
+	for i := 0; i < len(img.Pix); i += 4 {
+		if swizzle {
+			img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+		}
+		// The window is opaque whatever the clear color's alpha says.
+		img.Pix[i+3] = 0xff
+	}
+	return img, nil
+}
+
+func writePNG(path string, img image.Image) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+var cullModeNames = []string{"none", "front", "back"}
+
+var cullModes = []vk.CullModeFlagBits{
+	vk.CullModeNone,
+	vk.CullModeFrontBit,
+	vk.CullModeBackBit,
+}
+
+// Settings are the values tweakable at runtime through the settings panel.
+// The clear color is read while recording and the rotation and light every
+// frame; the cull mode and depth test are baked into the cube pipeline,
+// which updatePipeline rebuilds when they change.
+type Settings struct {
+	ClearColor  [4]float32
+	CullMode    int
+	DepthTest   bool
+	ShowOverlay bool
+	// RotationSpeed spins the cube about its vertical axis, in degrees
+	// per second.
+	RotationSpeed float32
+	// LightAzimuth and LightElevation place the directional light, in
+	// degrees. LightColor's alpha scales its intensity, and Ambient is
+	// the light every face receives.
+	LightAzimuth   float32
+	LightElevation float32
+	LightColor     [4]float32
+	Ambient        float32
+}
+
+// settingsJSON is how Settings appear in config files, with the cull mode
+// spelled out by name.
+type settingsJSON struct {
+	ClearColor     [4]float32 `json:"clearColor"`
+	CullMode       string     `json:"cullMode"`
+	DepthTest      bool       `json:"depthTest"`
+	ShowOverlay    bool       `json:"showOverlay"`
+	RotationSpeed  float32    `json:"rotationSpeed"`
+	LightAzimuth   float32    `json:"lightAzimuth"`
+	LightElevation float32    `json:"lightElevation"`
+	LightColor     [4]float32 `json:"lightColor"`
+	Ambient        float32    `json:"ambient"`
+}
+
+func (s Settings) toJSON() settingsJSON {
+	return settingsJSON{
+		ClearColor:     s.ClearColor,
+		CullMode:       cullModeNames[s.CullMode],
+		DepthTest:      s.DepthTest,
+		ShowOverlay:    s.ShowOverlay,
+		RotationSpeed:  s.RotationSpeed,
+		LightAzimuth:   s.LightAzimuth,
+		LightElevation: s.LightElevation,
+		LightColor:     s.LightColor,
+		Ambient:        s.Ambient,
+	}
+}
+
+func (s Settings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toJSON())
+}
+
+// UnmarshalJSON decodes on top of the current values, so a preset only
+// needs to list what it changes.
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	v := s.toJSON()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	mode := -1
+	for i, name := range cullModeNames {
+		if name == v.CullMode {
+			mode = i
+		}
+	}
+	if mode < 0 {
+		return fmt.Errorf("unknown cull mode %q, want one of %s",
+			v.CullMode, strings.Join(cullModeNames, ", "))
+	}
+	for _, c := range v.ClearColor {
+		if c < 0 || c > 1 {
+			return fmt.Errorf("clear color components must be within [0, 1], got %v", v.ClearColor)
+		}
+	}
+	for _, c := range v.LightColor {
+		if c < 0 || c > 1 {
+			return fmt.Errorf("light color components must be within [0, 1], got %v", v.LightColor)
+		}
+	}
+	if v.LightElevation < -90 || v.LightElevation > 90 {
+		return fmt.Errorf("light elevation must be within [-90, 90] degrees, got %v", v.LightElevation)
+	}
+	if v.Ambient < 0 || v.Ambient > 1 {
+		return fmt.Errorf("ambient must be within [0, 1], got %v", v.Ambient)
+	}
+	*s = Settings{
+		ClearColor:     v.ClearColor,
+		CullMode:       mode,
+		DepthTest:      v.DepthTest,
+		ShowOverlay:    v.ShowOverlay,
+		RotationSpeed:  v.RotationSpeed,
+		LightAzimuth:   v.LightAzimuth,
+		LightElevation: v.LightElevation,
+		LightColor:     v.LightColor,
+		Ambient:        v.Ambient,
+	}
+	return nil
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		ClearColor:     [4]float32{0.2, 0.2, 0.2, 0.2},
+		CullMode:       2,
+		DepthTest:      true,
+		ShowOverlay:    true,
+		RotationSpeed:  30,
+		LightAzimuth:   30,
+		LightElevation: 45,
+		LightColor:     [4]float32{1, 1, 1, 0.8},
+		Ambient:        0.3,
+	}
+}
+
+// lightDirection is the unit vector from the scene towards the light.
+func (s Settings) lightDirection() vec3 {
+	azimuth := float64(s.LightAzimuth) * math.Pi / 180
+	elevation := float64(s.LightElevation) * math.Pi / 180
+	return vec3{
+		float32(math.Cos(elevation) * math.Sin(azimuth)),
+		float32(math.Sin(elevation)),
+		float32(math.Cos(elevation) * math.Cos(azimuth)),
+	}
+}
+
+// samePipeline reports whether s and t would build the same cube
+// pipeline.
+func (s Settings) samePipeline(t Settings) bool {
+	return s.CullMode == t.CullMode && s.DepthTest == t.DepthTest
+}
+
+func (a *Application) registerSettingsPanel() {
+	a.ui.RegisterPanel("Settings", func(ui *UI) {
+		ui.ColorEdit("Clear color", &a.settings.ClearColor)
+		ui.Combo("Cull mode", &a.settings.CullMode, cullModeNames)
+		ui.Checkbox("Depth test", &a.settings.DepthTest)
+		ui.Checkbox("Show overlay", &a.settings.ShowOverlay)
+		ui.SliderFloat("Rotation speed", &a.settings.RotationSpeed, -180, 180)
+		ui.SliderFloat("Light azimuth", &a.settings.LightAzimuth, -180, 180)
+		ui.SliderFloat("Light elevation", &a.settings.LightElevation, -90, 90)
+		ui.ColorEdit("Light color", &a.settings.LightColor)
+		ui.SliderFloat("Ambient", &a.settings.Ambient, 0, 1)
+		ui.Label("F1 toggles this UI, C the camera")
+		ui.Label("F12 saves a screenshot")
+	})
+}
@@ -0,0 +1,15 @@
+/*
+ * Tints the coverage stored in the font atlas.
+ */
+#version 450
+
+layout (binding = 0) uniform sampler2D font;
+
+layout (location = 0) in vec2 texcoord;
+layout (location = 1) in vec4 tint;
+layout (location = 0) out vec4 uFragColor;
+
+void main()
+{
+        uFragColor = vec4(tint.rgb, tint.a * texture(font, texcoord).r);
+}
@@ -0,0 +1,26 @@
+/*
+ * 2D screen-space quads for text and UI, positioned in pixels.
+ */
+#version 450
+
+layout (push_constant) uniform PushConstants {
+        vec2 screenSize;
+} pc;
+
+layout (location = 0) in vec2 pos;
+layout (location = 1) in vec2 uv;
+layout (location = 2) in vec4 color;
+
+layout (location = 0) out vec2 texcoord;
+layout (location = 1) out vec4 tint;
+
+out gl_PerVertex {
+        vec4 gl_Position;
+};
+
+void main()
+{
+        texcoord = uv;
+        tint = color;
+        gl_Position = vec4(pos / pc.screenSize * 2.0 - 1.0, 0.0, 1.0);
+}
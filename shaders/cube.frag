@@ -21,10 +21,23 @@
 #version 400
 #extension GL_ARB_separate_shader_objects : enable
 #extension GL_ARB_shading_language_420pack : enable
+layout(std140, binding = 0) uniform buf {
+        mat4 MVP;
+        mat4 model;
+        vec4 lightDir;   // towards the light, w = ambient
+        vec4 lightColor;
+        vec4 position[12*3];
+        vec4 attr[12*3];
+        vec4 normal[12*3];
+} ubuf;
 layout (binding = 1) uniform sampler2D tex;
 
 layout (location = 0) in vec4 texcoord;
+layout (location = 1) in vec3 normal;
 layout (location = 0) out vec4 uFragColor;
 void main() {
-   uFragColor = texture(tex, texcoord.xy);
+   vec4 color = texture(tex, texcoord.xy);
+   float diffuse = max(dot(normalize(normal), ubuf.lightDir.xyz), 0.0);
+   vec3 light = vec3(ubuf.lightDir.w) + ubuf.lightColor.rgb * diffuse;
+   uFragColor = vec4(color.rgb * light, color.a);
 }
@@ -23,11 +23,16 @@
 #extension GL_ARB_shading_language_420pack : enable
 layout(std140, binding = 0) uniform buf {
         mat4 MVP;
+        mat4 model;
+        vec4 lightDir;
+        vec4 lightColor;
         vec4 position[12*3];
         vec4 attr[12*3];
+        vec4 normal[12*3];
 } ubuf;
 
 layout (location = 0) out vec4 texcoord;
+layout (location = 1) out vec3 normal;
 
 out gl_PerVertex {
         vec4 gl_Position;
@@ -36,5 +41,6 @@ out gl_PerVertex {
 void main() 
 {
    texcoord = ubuf.attr[gl_VertexIndex];
+   normal = mat3(ubuf.model) * ubuf.normal[gl_VertexIndex].xyz;
    gl_Position = ubuf.MVP * ubuf.position[gl_VertexIndex];
 }
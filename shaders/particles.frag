@@ -0,0 +1,17 @@
+/*
+ * Soft round sprite, meant for additive blending.
+ */
+#version 450
+
+layout (location = 0) in vec4 color;
+layout (location = 1) in vec2 corner;
+layout (location = 0) out vec4 uFragColor;
+
+void main()
+{
+        float d = dot(corner, corner);
+        if (d > 1.0) {
+                discard;
+        }
+        uFragColor = color * color.a * (1.0 - d);
+}
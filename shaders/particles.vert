@@ -0,0 +1,41 @@
+/*
+ * Expands every particle instance into a camera-facing quad.
+ */
+#version 450
+
+struct Particle {
+        vec4 position; // xyz, w = billboard size
+        vec4 velocity; // xyz, w = remaining life in seconds
+        vec4 color;
+};
+
+layout (std430, binding = 0) readonly buffer Particles {
+        Particle particles[];
+};
+
+layout (std430, binding = 1) readonly buffer Frame {
+        mat4 viewProj;
+        vec4 camRight;
+        vec4 camUp;
+} frame;
+
+layout (location = 0) out vec4 color;
+layout (location = 1) out vec2 corner;
+
+const vec2 corners[6] = vec2[](
+        vec2(-1.0, -1.0), vec2(1.0, -1.0), vec2(1.0, 1.0),
+        vec2(-1.0, -1.0), vec2(1.0, 1.0), vec2(-1.0, 1.0)
+);
+
+out gl_PerVertex {
+        vec4 gl_Position;
+};
+
+void main()
+{
+        Particle p = particles[gl_InstanceIndex];
+        corner = corners[gl_VertexIndex];
+        vec3 offset = frame.camRight.xyz * corner.x + frame.camUp.xyz * corner.y;
+        gl_Position = frame.viewProj * vec4(p.position.xyz + offset * p.position.w, 1.0);
+        color = p.color;
+}
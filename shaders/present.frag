@@ -0,0 +1,14 @@
+/*
+ * Copies the rendered frame into the swapchain image.
+ */
+#version 450
+
+layout (binding = 0) uniform sampler2D frame;
+
+layout (location = 0) in vec2 texcoord;
+layout (location = 0) out vec4 uFragColor;
+
+void main()
+{
+        uFragColor = texture(frame, texcoord);
+}
@@ -0,0 +1,16 @@
+/*
+ * A triangle covering the screen, drawn without vertex buffers.
+ */
+#version 450
+
+layout (location = 0) out vec2 texcoord;
+
+out gl_PerVertex {
+        vec4 gl_Position;
+};
+
+void main()
+{
+        texcoord = vec2((gl_VertexIndex << 1) & 2, gl_VertexIndex & 2);
+        gl_Position = vec4(texcoord * 2.0 - 1.0, 0.0, 1.0);
+}
@@ -0,0 +1,159 @@
+package texfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// ktx2File writes a KTX2 file with the levels stored one after another
+// behind the level index.
+func ktx2File(h ktx2Header, levels ...[]byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(ktx2Identifier)
+	binary.Write(&buf, binary.LittleEndian, &h)
+	offset := uint64(buf.Len() + len(levels)*binary.Size(ktx2Level{}))
+	for _, l := range levels {
+		binary.Write(&buf, binary.LittleEndian, &ktx2Level{ByteOffset: offset, ByteLength: uint64(len(l))})
+		offset += uint64(len(l))
+	}
+	for _, l := range levels {
+		buf.Write(l)
+	}
+	return buf.Bytes()
+}
+
+// ddsFile writes a DDS file, with a DX10 header when dx10 is not nil.
+func ddsFile(h ddsHeader, dx10 *ddsHeaderDX10, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(ddsMagic)
+	binary.Write(&buf, binary.LittleEndian, &h)
+	if dx10 != nil {
+		binary.Write(&buf, binary.LittleEndian, dx10)
+	}
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestDecodeKTX2(t *testing.T) {
+	bc1 := ktx2Header{VkFormat: uint32(vk.FormatBc1RgbaUnormBlock), PixelWidth: 8, PixelHeight: 8, LevelCount: 2}
+	valid := ktx2File(bc1, make([]byte, 32), make([]byte, 8))
+
+	img, err := DecodeKTX2(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Format != vk.FormatBc1RgbaUnormBlock || img.Width != 8 || img.Height != 8 ||
+		len(img.Levels) != 2 || len(img.Levels[0]) != 32 || len(img.Levels[1]) != 8 {
+		t.Errorf("decoded %s %dx%d with %d levels", FormatName(img.Format), img.Width, img.Height, len(img.Levels))
+	}
+
+	with := func(change func(h *ktx2Header)) ktx2Header {
+		h := bc1
+		change(&h)
+		return h
+	}
+	// The level index starts right after the header.
+	indexStart := len(ktx2Identifier) + binary.Size(ktx2Header{})
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"not KTX2", []byte("KTX 11"), "not a KTX2 file"},
+		{"truncated header", valid[:indexStart-1], "reading header"},
+		{"Basis Universal", ktx2File(with(func(h *ktx2Header) { h.VkFormat = 0 })), "Basis Universal"},
+		{"supercompressed", ktx2File(with(func(h *ktx2Header) { h.SupercompressionScheme = 2 })), "supercompression"},
+		{"1D", ktx2File(with(func(h *ktx2Header) { h.PixelHeight = 0 })), "only 2D"},
+		{"3D", ktx2File(with(func(h *ktx2Header) { h.PixelDepth = 4 })), "only 2D"},
+		{"unknown format", ktx2File(with(func(h *ktx2Header) { h.VkFormat = 1000 })), "unsupported format"},
+		{"truncated level index", ktx2File(with(func(h *ktx2Header) { h.LevelCount = 1 })), "reading level index"},
+		{"level outside the file", valid[:len(valid)-1], "lies outside the file"},
+		{"level too short", ktx2File(bc1, make([]byte, 16), make([]byte, 8)), "level 0 is truncated"},
+		{"empty", ktx2File(with(func(h *ktx2Header) { h.PixelWidth, h.LevelCount = 0, 1 }), make([]byte, 16)), "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeKTX2(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("DecodeKTX2 = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDecodeDDS(t *testing.T) {
+	fourCC := func(code string) ddsPixelFormat {
+		pf := ddsPixelFormat{Size: 32, Flags: ddsPixelFormatFourCC}
+		copy(pf.FourCC[:], code)
+		return pf
+	}
+	dxt5 := ddsHeader{Size: 124, Width: 8, Height: 4, MipMapCount: 2, PixelFormat: fourCC("DXT5")}
+	bgra := ddsHeader{Size: 124, Width: 2, Height: 2, PixelFormat: ddsPixelFormat{
+		Size: 32, Flags: ddsPixelFormatRGB, RGBBitCount: 32,
+		RBitMask: 0xff0000, GBitMask: 0xff00, BBitMask: 0xff, ABitMask: 0xff000000,
+	}}
+	dx10 := ddsHeader{Size: 124, Width: 4, Height: 4, PixelFormat: fourCC("DX10")}
+
+	valid := []struct {
+		name   string
+		data   []byte
+		format vk.Format
+		levels []int
+	}{
+		{"FourCC", ddsFile(dxt5, nil, make([]byte, 48)), vk.FormatBc3UnormBlock, []int{32, 16}},
+		{"BGRA", ddsFile(bgra, nil, make([]byte, 16)), vk.FormatB8g8r8a8Unorm, []int{16}},
+		{"DX10", ddsFile(dx10, &ddsHeaderDX10{DXGIFormat: 72}, make([]byte, 8)), vk.FormatBc1RgbaSrgbBlock, []int{8}},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := DecodeDDS(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Format != tt.format || len(img.Levels) != len(tt.levels) {
+				t.Fatalf("decoded %s with %d levels, want %s with %d",
+					FormatName(img.Format), len(img.Levels), FormatName(tt.format), len(tt.levels))
+			}
+			for i, size := range tt.levels {
+				if len(img.Levels[i]) != size {
+					t.Errorf("level %d holds %d bytes, want %d", i, len(img.Levels[i]), size)
+				}
+			}
+		})
+	}
+
+	with := func(h ddsHeader, change func(h *ddsHeader)) ddsHeader {
+		change(&h)
+		return h
+	}
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"bad magic", []byte("DDSX"), "not a DDS file"},
+		{"truncated header", ddsFile(dxt5, nil, nil)[:100], "reading header"},
+		{"wrong header size", ddsFile(with(dxt5, func(h *ddsHeader) { h.Size = 128 }), nil, make([]byte, 48)), "malformed header"},
+		{"cubemap", ddsFile(with(dxt5, func(h *ddsHeader) { h.Caps2 = ddsCubemap }), nil, make([]byte, 48)), "only 2D"},
+		{"volume", ddsFile(with(dxt5, func(h *ddsHeader) { h.Depth = 2 }), nil, make([]byte, 48)), "only 2D"},
+		{"unknown FourCC", ddsFile(with(dxt5, func(h *ddsHeader) { h.PixelFormat = fourCC("ETC2") }), nil, make([]byte, 48)), "unsupported FourCC"},
+		{"truncated DX10 header", ddsFile(dx10, nil, make([]byte, 8)), "reading DX10 header"},
+		{"unknown DXGI format", ddsFile(dx10, &ddsHeaderDX10{DXGIFormat: 2}, make([]byte, 64)), "unsupported DXGI format"},
+		{"no pixel format", ddsFile(with(bgra, func(h *ddsHeader) { h.PixelFormat.Flags = 0 }), nil, make([]byte, 16)), "unsupported pixel format"},
+		{"24-bit RGB", ddsFile(with(bgra, func(h *ddsHeader) { h.PixelFormat.RGBBitCount = 24 }), nil, make([]byte, 16)), "unsupported pixel format"},
+		{"truncated level", ddsFile(dxt5, nil, make([]byte, 40)), "level 1 is truncated"},
+		{"empty", ddsFile(with(bgra, func(h *ddsHeader) { h.Width = 0 }), nil, make([]byte, 8)), "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeDDS(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("DecodeDDS = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package texfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const (
+	ddsMagic = "DDS "
+
+	ddsPixelFormatFourCC = 0x4
+	ddsPixelFormatRGB    = 0x40
+	ddsCubemap           = 0x200
+)
+
+type ddsPixelFormat struct {
+	Size        uint32
+	Flags       uint32
+	FourCC      [4]byte
+	RGBBitCount uint32
+	RBitMask    uint32
+	GBitMask    uint32
+	BBitMask    uint32
+	ABitMask    uint32
+}
+
+type ddsHeader struct {
+	Size              uint32
+	Flags             uint32
+	Height            uint32
+	Width             uint32
+	PitchOrLinearSize uint32
+	Depth             uint32
+	MipMapCount       uint32
+	Reserved1         [11]uint32
+	PixelFormat       ddsPixelFormat
+	Caps              uint32
+	Caps2             uint32
+	Caps3             uint32
+	Caps4             uint32
+	Reserved2         uint32
+}
+
+type ddsHeaderDX10 struct {
+	DXGIFormat        uint32
+	ResourceDimension uint32
+	MiscFlag          uint32
+	ArraySize         uint32
+	MiscFlags2        uint32
+}
+
+var ddsFourCCFormats = map[string]vk.Format{
+	"DXT1": vk.FormatBc1RgbaUnormBlock,
+	"DXT2": vk.FormatBc2UnormBlock,
+	"DXT3": vk.FormatBc2UnormBlock,
+	"DXT4": vk.FormatBc3UnormBlock,
+	"DXT5": vk.FormatBc3UnormBlock,
+	"ATI1": vk.FormatBc4UnormBlock,
+	"BC4U": vk.FormatBc4UnormBlock,
+	"BC4S": vk.FormatBc4SnormBlock,
+	"ATI2": vk.FormatBc5UnormBlock,
+	"BC5U": vk.FormatBc5UnormBlock,
+	"BC5S": vk.FormatBc5SnormBlock,
+}
+
+var ddsDXGIFormats = map[uint32]vk.Format{
+	28: vk.FormatR8g8b8a8Unorm,
+	29: vk.FormatR8g8b8a8Srgb,
+	71: vk.FormatBc1RgbaUnormBlock,
+	72: vk.FormatBc1RgbaSrgbBlock,
+	74: vk.FormatBc2UnormBlock,
+	75: vk.FormatBc2SrgbBlock,
+	77: vk.FormatBc3UnormBlock,
+	78: vk.FormatBc3SrgbBlock,
+	80: vk.FormatBc4UnormBlock,
+	81: vk.FormatBc4SnormBlock,
+	83: vk.FormatBc5UnormBlock,
+	84: vk.FormatBc5SnormBlock,
+	87: vk.FormatB8g8r8a8Unorm,
+	91: vk.FormatB8g8r8a8Srgb,
+	95: vk.FormatBc6hUfloatBlock,
+	96: vk.FormatBc6hSfloatBlock,
+	98: vk.FormatBc7UnormBlock,
+	99: vk.FormatBc7SrgbBlock,
+}
+
+// IsDDS reports whether data starts with the DDS magic number.
+func IsDDS(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ddsMagic))
+}
+
+// DecodeDDS reads the first array element of a 2D DDS texture, described
+// either by a FourCC code, a 32-bit RGBA pixel format or a DX10 header.
+func DecodeDDS(data []byte) (*Image, error) {
+	if !IsDDS(data) {
+		return nil, errors.New("dds: not a DDS file")
+	}
+	r := bytes.NewReader(data[len(ddsMagic):])
+	var h ddsHeader
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, fmt.Errorf("dds: reading header: %v", err)
+	}
+	if h.Size != 124 || h.PixelFormat.Size != 32 {
+		return nil, errors.New("dds: malformed header")
+	}
+	if h.Caps2&ddsCubemap != 0 || h.Depth > 1 {
+		return nil, errors.New("dds: only 2D textures are supported")
+	}
+
+	var format vk.Format
+	pf := h.PixelFormat
+	fourCC := string(pf.FourCC[:])
+	switch {
+	case pf.Flags&ddsPixelFormatFourCC != 0 && fourCC == "DX10":
+		var dx10 ddsHeaderDX10
+		if err := binary.Read(r, binary.LittleEndian, &dx10); err != nil {
+			return nil, fmt.Errorf("dds: reading DX10 header: %v", err)
+		}
+		f, ok := ddsDXGIFormats[dx10.DXGIFormat]
+		if !ok {
+			return nil, fmt.Errorf("dds: unsupported DXGI format %d", dx10.DXGIFormat)
+		}
+		format = f
+	case pf.Flags&ddsPixelFormatFourCC != 0:
+		f, ok := ddsFourCCFormats[fourCC]
+		if !ok {
+			return nil, fmt.Errorf("dds: unsupported FourCC %q", fourCC)
+		}
+		format = f
+	case pf.Flags&ddsPixelFormatRGB != 0 && pf.RGBBitCount == 32 &&
+		pf.GBitMask == 0xff00 && pf.ABitMask == 0xff000000:
+		switch {
+		case pf.RBitMask == 0xff && pf.BBitMask == 0xff0000:
+			format = vk.FormatR8g8b8a8Unorm
+		case pf.RBitMask == 0xff0000 && pf.BBitMask == 0xff:
+			format = vk.FormatB8g8r8a8Unorm
+		}
+	}
+	if format == vk.FormatUndefined {
+		return nil, errors.New("dds: unsupported pixel format")
+	}
+
+	img := &Image{
+		Format: format,
+		Width:  h.Width,
+		Height: h.Height,
+	}
+	info, _ := Info(format)
+	levels := int(h.MipMapCount)
+	if levels == 0 {
+		levels = 1
+	}
+	offset := len(data) - r.Len()
+	for i := 0; i < levels; i++ {
+		size := info.LevelSize(MipSize(img.Width, i), MipSize(img.Height, i))
+		if offset+size > len(data) {
+			return nil, fmt.Errorf("dds: mip level %d is truncated", i)
+		}
+		img.Levels = append(img.Levels, data[offset:offset+size])
+		offset += size
+	}
+	if err := img.validate(); err != nil {
+		return nil, fmt.Errorf("dds: %v", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,435 @@
+package texfile
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// blockDecoder expands one compressed block into 4x4 RGBA8 texels, row by
+// row.
+type blockDecoder func(block []byte, out *[64]byte)
+
+var decoders = map[vk.Format]blockDecoder{
+	vk.FormatBc1RgbUnormBlock:  decodeBC1Opaque,
+	vk.FormatBc1RgbSrgbBlock:   decodeBC1Opaque,
+	vk.FormatBc1RgbaUnormBlock: decodeBC1,
+	vk.FormatBc1RgbaSrgbBlock:  decodeBC1,
+	vk.FormatBc2UnormBlock:     decodeBC2,
+	vk.FormatBc2SrgbBlock:      decodeBC2,
+	vk.FormatBc3UnormBlock:     decodeBC3,
+	vk.FormatBc3SrgbBlock:      decodeBC3,
+	vk.FormatBc4UnormBlock:     decodeBC4,
+	vk.FormatBc5UnormBlock:     decodeBC5,
+
+	vk.FormatEtc2R8g8b8UnormBlock:   decodeETC2RGB,
+	vk.FormatEtc2R8g8b8SrgbBlock:    decodeETC2RGB,
+	vk.FormatEtc2R8g8b8a1UnormBlock: decodeETC2PunchThrough,
+	vk.FormatEtc2R8g8b8a1SrgbBlock:  decodeETC2PunchThrough,
+	vk.FormatEtc2R8g8b8a8UnormBlock: decodeETC2RGBA,
+	vk.FormatEtc2R8g8b8a8SrgbBlock:  decodeETC2RGBA,
+	vk.FormatEacR11UnormBlock:       decodeEACR11,
+	vk.FormatEacR11g11UnormBlock:    decodeEACRG11,
+}
+
+// CanDecompress reports whether Decompress handles format. Signed formats,
+// BC6H, BC7 and ASTC are left to the device, and Decompress returns an
+// error for them.
+func CanDecompress(format vk.Format) bool {
+	_, ok := decoders[format]
+	return ok
+}
+
+// Decompress expands every level of a block compressed image into RGBA8,
+// keeping the sRGB encoding of the source format. Single-channel formats
+// decode to red, two-channel ones to red and green, like the device would
+// sample them.
+func Decompress(img *Image) (*Image, error) {
+	decode, ok := decoders[img.Format]
+	if !ok {
+		return nil, fmt.Errorf("texfile: cannot decompress %s, it needs device support", FormatName(img.Format))
+	}
+	info := formats[img.Format]
+	out := &Image{
+		Format: vk.FormatR8g8b8a8Unorm,
+		Width:  img.Width,
+		Height: img.Height,
+	}
+	if info.SRGB {
+		out.Format = vk.FormatR8g8b8a8Srgb
+	}
+	var texels [64]byte
+	for i, level := range img.Levels {
+		w, h := MipSize(img.Width, i), MipSize(img.Height, i)
+		pixels := make([]byte, w*h*4)
+		bw := (w + 3) / 4
+		for by := uint32(0); by < (h+3)/4; by++ {
+			for bx := uint32(0); bx < bw; bx++ {
+				offset := (by*bw + bx) * info.BlockBytes
+				decode(level[offset:offset+info.BlockBytes], &texels)
+				for y := uint32(0); y < 4 && by*4+y < h; y++ {
+					for x := uint32(0); x < 4 && bx*4+x < w; x++ {
+						dst := ((by*4+y)*w + bx*4 + x) * 4
+						src := (y*4 + x) * 4
+						copy(pixels[dst:dst+4], texels[src:src+4])
+					}
+				}
+			}
+		}
+		out.Levels = append(out.Levels, pixels)
+	}
+	return out, nil
+}
+
+// rgb565 expands a packed 5:6:5 color to 8 bits per channel.
+func rgb565(c uint16) [3]int {
+	r, g, b := int(c>>11&0x1f), int(c>>5&0x3f), int(c&0x1f)
+	return [3]int{r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2}
+}
+
+// decodeBC1Color decodes the color half shared by BC1, BC2 and BC3. The
+// three-color mode with transparent black only exists in BC1 proper.
+func decodeBC1Color(block []byte, out *[64]byte, threeColor, punchThrough bool) {
+	c0 := binary.LittleEndian.Uint16(block[0:])
+	c1 := binary.LittleEndian.Uint16(block[2:])
+	indices := binary.LittleEndian.Uint32(block[4:])
+
+	var palette [4][4]int
+	p0, p1 := rgb565(c0), rgb565(c1)
+	for ch := 0; ch < 3; ch++ {
+		palette[0][ch] = p0[ch]
+		palette[1][ch] = p1[ch]
+		if c0 > c1 || !threeColor {
+			palette[2][ch] = (2*p0[ch] + p1[ch] + 1) / 3
+			palette[3][ch] = (p0[ch] + 2*p1[ch] + 1) / 3
+		} else {
+			palette[2][ch] = (p0[ch] + p1[ch]) / 2
+		}
+	}
+	for i := range palette {
+		palette[i][3] = 255
+	}
+	if threeColor && c0 <= c1 && punchThrough {
+		palette[3][3] = 0
+	}
+	for i := 0; i < 16; i++ {
+		c := palette[indices>>(2*uint(i))&3]
+		for ch := 0; ch < 4; ch++ {
+			out[i*4+ch] = byte(c[ch])
+		}
+	}
+}
+
+func decodeBC1(block []byte, out *[64]byte) {
+	decodeBC1Color(block, out, true, true)
+}
+
+func decodeBC1Opaque(block []byte, out *[64]byte) {
+	decodeBC1Color(block, out, true, false)
+}
+
+func decodeBC2(block []byte, out *[64]byte) {
+	decodeBC1Color(block[8:], out, false, false)
+	alpha := binary.LittleEndian.Uint64(block)
+	for i := 0; i < 16; i++ {
+		a := byte(alpha >> (4 * uint(i)) & 0xf)
+		out[i*4+3] = a<<4 | a
+	}
+}
+
+// decodeBC4Channel decodes an 8-byte BC4 block into one channel of out.
+func decodeBC4Channel(block []byte, out *[64]byte, channel int) {
+	r0, r1 := int(block[0]), int(block[1])
+	var palette [8]int
+	palette[0], palette[1] = r0, r1
+	if r0 > r1 {
+		for i := 1; i < 7; i++ {
+			palette[i+1] = ((7-i)*r0 + i*r1 + 3) / 7
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			palette[i+1] = ((5-i)*r0 + i*r1 + 2) / 5
+		}
+		palette[6], palette[7] = 0, 255
+	}
+	var bits uint64
+	for i := 7; i >= 2; i-- {
+		bits = bits<<8 | uint64(block[i])
+	}
+	for i := 0; i < 16; i++ {
+		out[i*4+channel] = byte(palette[bits>>(3*uint(i))&7])
+	}
+}
+
+func decodeBC3(block []byte, out *[64]byte) {
+	decodeBC1Color(block[8:], out, false, false)
+	decodeBC4Channel(block, out, 3)
+}
+
+func decodeBC4(block []byte, out *[64]byte) {
+	decodeBC4Channel(block, out, 0)
+	for i := 0; i < 16; i++ {
+		out[i*4+1], out[i*4+2], out[i*4+3] = 0, 0, 255
+	}
+}
+
+func decodeBC5(block []byte, out *[64]byte) {
+	decodeBC4Channel(block, out, 0)
+	decodeBC4Channel(block[8:], out, 1)
+	for i := 0; i < 16; i++ {
+		out[i*4+2], out[i*4+3] = 0, 255
+	}
+}
+
+var etc1Modifiers = [8][4]int{
+	{2, 8, -2, -8},
+	{5, 17, -5, -17},
+	{9, 29, -9, -29},
+	{13, 42, -13, -42},
+	{18, 60, -18, -60},
+	{24, 80, -24, -80},
+	{33, 106, -33, -106},
+	{47, 183, -47, -183},
+}
+
+var etc2Distances = [8]int{3, 6, 11, 16, 23, 32, 41, 64}
+
+func clamp255(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return v
+}
+
+func extend4(v uint64) int { return int(v<<4 | v) }
+func extend5(v uint64) int { return int(v<<3 | v>>2) }
+func extend6(v uint64) int { return int(v<<2 | v>>4) }
+func extend7(v uint64) int { return int(v<<1 | v>>6) }
+
+// decodeETC2Color decodes an 8-byte ETC2 color block. With punchThrough
+// the differential bit is the opaque bit of RGB8A1 instead.
+func decodeETC2Color(block []byte, out *[64]byte, punchThrough bool) {
+	b := binary.BigEndian.Uint64(block)
+	bit := func(n uint) uint64 { return b >> n & 1 }
+	bits := func(hi, lo uint) uint64 { return b >> lo & (1<<(hi-lo+1) - 1) }
+
+	// texel returns the 2-bit index of texel (x, y); indices are stored
+	// column by column.
+	texel := func(x, y int) int {
+		i := uint(x*4 + y)
+		return int(bit(i+16)<<1 | bit(i))
+	}
+	set := func(x, y int, c [3]int, alpha int) {
+		o := (y*4 + x) * 4
+		if alpha == 0 {
+			// Transparent texels are black, as the specification says.
+			c = [3]int{}
+		}
+		for ch := 0; ch < 3; ch++ {
+			out[o+ch] = byte(clamp255(c[ch]))
+		}
+		out[o+3] = byte(alpha)
+	}
+
+	diff := bit(33) == 1
+	opaque := true
+	if punchThrough {
+		opaque = diff
+		diff = true
+	}
+
+	if !diff {
+		c0 := [3]int{extend4(bits(63, 60)), extend4(bits(55, 52)), extend4(bits(47, 44))}
+		c1 := [3]int{extend4(bits(59, 56)), extend4(bits(51, 48)), extend4(bits(43, 40))}
+		decodeETC1Subblocks(b, c0, c1, opaque, texel, set)
+		return
+	}
+
+	r, g, bl := int(bits(63, 59)), int(bits(55, 51)), int(bits(47, 43))
+	dr, dg, db := signed3(bits(58, 56)), signed3(bits(50, 48)), signed3(bits(42, 40))
+	switch {
+	case r+dr < 0 || r+dr > 31:
+		// T mode.
+		c0 := [3]int{extend4(bits(60, 59)<<2 | bits(57, 56)), extend4(bits(55, 52)), extend4(bits(51, 48))}
+		c1 := [3]int{extend4(bits(47, 44)), extend4(bits(43, 40)), extend4(bits(39, 36))}
+		d := etc2Distances[bits(35, 34)<<1|bit(32)]
+		paint := [4][3]int{c0, add(c1, d), c1, add(c1, -d)}
+		decodePaint(paint, opaque, texel, set)
+	case g+dg < 0 || g+dg > 31:
+		// H mode.
+		c0 := [3]int{extend4(bits(62, 59)), extend4(bits(58, 56)<<1 | bit(52)), extend4(bit(51)<<3 | bits(49, 47))}
+		c1 := [3]int{extend4(bits(46, 43)), extend4(bits(42, 39)), extend4(bits(38, 35))}
+		index := bit(34)<<2 | bit(32)<<1
+		if c0[0]<<16|c0[1]<<8|c0[2] >= c1[0]<<16|c1[1]<<8|c1[2] {
+			index |= 1
+		}
+		d := etc2Distances[index]
+		paint := [4][3]int{add(c0, d), add(c0, -d), add(c1, d), add(c1, -d)}
+		decodePaint(paint, opaque, texel, set)
+	case bl+db < 0 || bl+db > 31:
+		// Planar mode, which has no alpha.
+		o := [3]int{extend6(bits(62, 57)), extend7(bit(56)<<6 | bits(54, 49)),
+			extend6(bit(48)<<5 | bits(44, 43)<<3 | bits(41, 39))}
+		h := [3]int{extend6(bits(38, 34)<<1 | bit(32)), extend7(bits(31, 25)), extend6(bits(24, 19))}
+		v := [3]int{extend6(bits(18, 13)), extend7(bits(12, 6)), extend6(bits(5, 0))}
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				var c [3]int
+				for ch := 0; ch < 3; ch++ {
+					c[ch] = (x*(h[ch]-o[ch]) + y*(v[ch]-o[ch]) + 4*o[ch] + 2) >> 2
+				}
+				set(x, y, c, 255)
+			}
+		}
+	default:
+		c0 := [3]int{extend5(uint64(r)), extend5(uint64(g)), extend5(uint64(bl))}
+		c1 := [3]int{extend5(uint64(r + dr)), extend5(uint64(g + dg)), extend5(uint64(bl + db))}
+		decodeETC1Subblocks(b, c0, c1, opaque, texel, set)
+	}
+}
+
+func signed3(v uint64) int {
+	if v >= 4 {
+		return int(v) - 8
+	}
+	return int(v)
+}
+
+func add(c [3]int, d int) [3]int {
+	return [3]int{c[0] + d, c[1] + d, c[2] + d}
+}
+
+// decodeETC1Subblocks applies the per-subblock modifier tables of the
+// individual and differential modes.
+func decodeETC1Subblocks(b uint64, c0, c1 [3]int, opaque bool,
+	texel func(x, y int) int, set func(x, y int, c [3]int, alpha int)) {
+
+	flip := b>>32&1 == 1
+	tables := [2]int{int(b >> 37 & 7), int(b >> 34 & 7)}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			sub := x / 2
+			if flip {
+				sub = y / 2
+			}
+			base := c0
+			if sub == 1 {
+				base = c1
+			}
+			index := texel(x, y)
+			modifier := etc1Modifiers[tables[sub]][index]
+			alpha := 255
+			if !opaque {
+				// Without the opaque bit the small modifiers vanish and
+				// index 2 is transparent.
+				if index == 0 || index == 2 {
+					modifier = 0
+				}
+				if index == 2 {
+					alpha = 0
+				}
+			}
+			set(x, y, add(base, modifier), alpha)
+		}
+	}
+}
+
+// decodePaint writes the T and H modes, whose indices pick a paint color.
+func decodePaint(paint [4][3]int, opaque bool,
+	texel func(x, y int) int, set func(x, y int, c [3]int, alpha int)) {
+
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			index := texel(x, y)
+			alpha := 255
+			if !opaque && index == 2 {
+				alpha = 0
+			}
+			set(x, y, paint[index], alpha)
+		}
+	}
+}
+
+func decodeETC2RGB(block []byte, out *[64]byte) {
+	decodeETC2Color(block, out, false)
+}
+
+func decodeETC2PunchThrough(block []byte, out *[64]byte) {
+	decodeETC2Color(block, out, true)
+}
+
+var eacModifiers = [16][8]int{
+	{-3, -6, -9, -15, 2, 5, 8, 14},
+	{-3, -7, -10, -13, 2, 6, 9, 12},
+	{-2, -5, -8, -13, 1, 4, 7, 12},
+	{-2, -4, -6, -13, 1, 3, 5, 12},
+	{-3, -6, -8, -12, 2, 5, 7, 11},
+	{-3, -7, -9, -11, 2, 6, 8, 10},
+	{-4, -7, -8, -11, 3, 6, 7, 10},
+	{-3, -5, -8, -11, 2, 4, 7, 10},
+	{-2, -6, -8, -10, 1, 5, 7, 9},
+	{-2, -5, -8, -10, 1, 4, 7, 9},
+	{-2, -4, -8, -10, 1, 3, 7, 9},
+	{-2, -5, -7, -10, 1, 4, 6, 9},
+	{-3, -4, -7, -10, 2, 3, 6, 9},
+	{-1, -2, -3, -10, 0, 1, 2, 9},
+	{-4, -6, -8, -9, 3, 5, 7, 8},
+	{-3, -5, -7, -9, 2, 4, 6, 8},
+}
+
+// decodeEAC decodes an 8-byte EAC block into one channel of out. Alpha
+// blocks of ETC2 RGBA8 use 8-bit precision, the R11 formats 11-bit, which
+// is rounded back to 8 bits here.
+func decodeEAC(block []byte, out *[64]byte, channel int, eleven bool) {
+	b := binary.BigEndian.Uint64(block)
+	base := int(b >> 56)
+	multiplier := int(b >> 52 & 0xf)
+	table := eacModifiers[b>>48&0xf]
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			i := uint(x*4 + y)
+			modifier := table[b>>(45-3*i)&7]
+			var v int
+			if eleven {
+				m := multiplier * 8
+				if m == 0 {
+					m = 1
+				}
+				v = base*8 + 4 + modifier*m
+				if v < 0 {
+					v = 0
+				} else if v > 2047 {
+					v = 2047
+				}
+				v = (v*255 + 1023) / 2047
+			} else {
+				v = clamp255(base + modifier*multiplier)
+			}
+			out[(y*4+x)*4+channel] = byte(v)
+		}
+	}
+}
+
+func decodeETC2RGBA(block []byte, out *[64]byte) {
+	decodeETC2Color(block[8:], out, false)
+	decodeEAC(block, out, 3, false)
+}
+
+func decodeEACR11(block []byte, out *[64]byte) {
+	decodeEAC(block, out, 0, true)
+	for i := 0; i < 16; i++ {
+		out[i*4+1], out[i*4+2], out[i*4+3] = 0, 0, 255
+	}
+}
+
+func decodeEACRG11(block []byte, out *[64]byte) {
+	decodeEAC(block, out, 0, true)
+	decodeEAC(block[8:], out, 1, true)
+	for i := 0; i < 16; i++ {
+		out[i*4+2], out[i*4+3] = 0, 255
+	}
+}
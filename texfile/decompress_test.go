@@ -0,0 +1,221 @@
+package texfile
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+type rgba [4]byte
+
+// texels builds the expected 4x4 block from the color of each texel.
+func texels(color func(x, y int) rgba) [16]rgba {
+	var out [16]rgba
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			out[y*4+x] = color(x, y)
+		}
+	}
+	return out
+}
+
+func uniform(c rgba) [16]rgba {
+	return texels(func(x, y int) rgba { return c })
+}
+
+// byColumn colors texels by their column.
+func byColumn(c0, c1, c2, c3 rgba) [16]rgba {
+	columns := [4]rgba{c0, c1, c2, c3}
+	return texels(func(x, y int) rgba { return columns[x] })
+}
+
+// eacBlock packs an EAC block; indices are stored column by column after
+// the base, multiplier and table.
+func eacBlock(base, multiplier, table uint64, index func(x, y int) uint64) []byte {
+	b := base<<56 | multiplier<<52 | table<<48
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			b |= index(x, y) << (45 - 3*uint(x*4+y))
+		}
+	}
+	block := make([]byte, 8)
+	binary.BigEndian.PutUint64(block, b)
+	return block
+}
+
+func join(blocks ...[]byte) []byte {
+	var out []byte
+	for _, b := range blocks {
+		out = append(out, b...)
+	}
+	return out
+}
+
+func TestDecompress(t *testing.T) {
+	var (
+		red         = rgba{255, 0, 0, 255}
+		blue        = rgba{0, 0, 255, 255}
+		transparent = rgba{0, 0, 0, 0}
+	)
+	// Red and blue endpoints with the texels of column x using index x.
+	redBlue := []byte{0x00, 0xf8, 0x1f, 0x00, 0xe4, 0xe4, 0xe4, 0xe4}
+	blueRed := []byte{0x1f, 0x00, 0x00, 0xf8, 0xe4, 0xe4, 0xe4, 0xe4}
+	// ETC1 individual mode, both subblocks 0x888 with table 0.
+	grey := []byte{0x88, 0x88, 0x88, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	tests := []struct {
+		name   string
+		format vk.Format
+		block  []byte
+		want   [16]rgba
+	}{
+		{
+			name:   "BC1 four colors",
+			format: vk.FormatBc1RgbaUnormBlock,
+			block:  redBlue,
+			want:   byColumn(red, blue, rgba{170, 0, 85, 255}, rgba{85, 0, 170, 255}),
+		},
+		{
+			name:   "BC1 three colors and transparent black",
+			format: vk.FormatBc1RgbaUnormBlock,
+			block:  blueRed,
+			want:   byColumn(blue, red, rgba{127, 0, 127, 255}, transparent),
+		},
+		{
+			name:   "BC1 RGB has no transparency",
+			format: vk.FormatBc1RgbUnormBlock,
+			block:  blueRed,
+			want:   byColumn(blue, red, rgba{127, 0, 127, 255}, rgba{0, 0, 0, 255}),
+		},
+		{
+			name:   "BC3 eight alphas, colors always interpolated",
+			format: vk.FormatBc3UnormBlock,
+			// Every alpha index is 1, picking the second endpoint.
+			block: join([]byte{255, 0, 0x49, 0x92, 0x24, 0x49, 0x92, 0x24}, blueRed),
+			want: byColumn(rgba{0, 0, 255, 0}, rgba{255, 0, 0, 0},
+				rgba{85, 0, 170, 0}, rgba{170, 0, 85, 0}),
+		},
+		{
+			name:   "BC3 six alphas with opaque index",
+			format: vk.FormatBc3UnormBlock,
+			block:  join([]byte{10, 20, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, redBlue),
+			want:   byColumn(red, blue, rgba{170, 0, 85, 255}, rgba{85, 0, 170, 255}),
+		},
+		{
+			name:   "ETC2 individual mode",
+			format: vk.FormatEtc2R8g8b8UnormBlock,
+			block:  grey,
+			want:   uniform(rgba{138, 138, 138, 255}),
+		},
+		{
+			name:   "ETC2 index order is column by column",
+			format: vk.FormatEtc2R8g8b8UnormBlock,
+			// The low index bit of texel (1, 0) is bit 4.
+			block: []byte{0x88, 0x88, 0x88, 0x00, 0x00, 0x00, 0x00, 0x10},
+			want: texels(func(x, y int) rgba {
+				if x == 1 && y == 0 {
+					return rgba{144, 144, 144, 255}
+				}
+				return rgba{138, 138, 138, 255}
+			}),
+		},
+		{
+			name:   "ETC2 subblocks side by side",
+			format: vk.FormatEtc2R8g8b8UnormBlock,
+			block:  []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			want: byColumn(rgba{138, 2, 2, 255}, rgba{138, 2, 2, 255},
+				rgba{2, 2, 2, 255}, rgba{2, 2, 2, 255}),
+		},
+		{
+			name:   "ETC2 differential mode",
+			format: vk.FormatEtc2R8g8b8UnormBlock,
+			block:  []byte{0x80, 0x80, 0x80, 0x02, 0x00, 0x00, 0x00, 0x00},
+			want:   uniform(rgba{134, 134, 134, 255}),
+		},
+		{
+			name:   "ETC2 RGBA alpha",
+			format: vk.FormatEtc2R8g8b8a8UnormBlock,
+			block: join(eacBlock(128, 1, 0, func(x, y int) uint64 {
+				if x == 1 && y == 0 {
+					return 0
+				}
+				return 4
+			}), grey),
+			want: texels(func(x, y int) rgba {
+				if x == 1 && y == 0 {
+					return rgba{138, 138, 138, 125}
+				}
+				return rgba{138, 138, 138, 130}
+			}),
+		},
+		{
+			name:   "EAC R11",
+			format: vk.FormatEacR11UnormBlock,
+			block:  eacBlock(128, 1, 0, func(x, y int) uint64 { return 4 }),
+			want:   uniform(rgba{130, 0, 0, 255}),
+		},
+		{
+			name:   "EAC R11 without multiplier",
+			format: vk.FormatEacR11UnormBlock,
+			block:  eacBlock(128, 0, 0, func(x, y int) uint64 { return 3 }),
+			want:   uniform(rgba{126, 0, 0, 255}),
+		},
+		{
+			name:   "EAC RG11 clamps",
+			format: vk.FormatEacR11g11UnormBlock,
+			block: join(eacBlock(255, 15, 0, func(x, y int) uint64 { return 7 }),
+				eacBlock(0, 15, 0, func(x, y int) uint64 { return 3 })),
+			want: uniform(rgba{255, 0, 0, 255}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Decompress(&Image{Format: tt.format, Width: 4, Height: 4, Levels: [][]byte{tt.block}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				got := out.Levels[0][i*4 : i*4+4]
+				if (rgba{got[0], got[1], got[2], got[3]}) != want {
+					t.Errorf("texel (%d, %d) = %v, want %v", i%4, i/4, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecompressCropsAndKeepsSRGB(t *testing.T) {
+	// A 2x2 image still takes a whole block; only its top-left texels are
+	// kept.
+	block := []byte{0x00, 0xf8, 0x1f, 0x00, 0xe4, 0xe4, 0xe4, 0xe4}
+	out, err := Decompress(&Image{Format: vk.FormatBc1RgbaSrgbBlock, Width: 2, Height: 2, Levels: [][]byte{block}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Format != vk.FormatR8g8b8a8Srgb {
+		t.Errorf("format = %s, want RGBA8 sRGB", FormatName(out.Format))
+	}
+	want := []byte{255, 0, 0, 255, 0, 0, 255, 255, 255, 0, 0, 255, 0, 0, 255, 255}
+	if string(out.Levels[0]) != string(want) {
+		t.Errorf("pixels = %v, want %v", out.Levels[0], want)
+	}
+}
+
+func TestDecompressUnsupported(t *testing.T) {
+	for _, format := range []vk.Format{
+		vk.FormatBc6hUfloatBlock,
+		vk.FormatBc7UnormBlock,
+		vk.FormatAstc4x4UnormBlock,
+		vk.FormatBc4SnormBlock,
+	} {
+		if CanDecompress(format) {
+			t.Errorf("CanDecompress(%s) = true", FormatName(format))
+		}
+		_, err := Decompress(&Image{Format: format, Width: 4, Height: 4, Levels: [][]byte{make([]byte, 16)}})
+		if err == nil || !strings.Contains(err.Error(), "needs device support") {
+			t.Errorf("Decompress(%s) = %v, want an error", FormatName(format), err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package texfile reads KTX2 and DDS texture containers holding block
+// compressed or plain RGBA images, and decompresses the block formats a
+// device may lack into RGBA8.
+package texfile
+
+import (
+	"fmt"
+	"sort"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// Image is the first layer and face of a texture, with its mip levels from
+// full size down.
+type Image struct {
+	Format vk.Format
+	Width  uint32
+	Height uint32
+	Levels [][]byte
+}
+
+// FormatInfo describes the block layout of a format. Uncompressed formats
+// have 1x1 blocks of one texel.
+type FormatInfo struct {
+	Name        string
+	BlockWidth  uint32
+	BlockHeight uint32
+	BlockBytes  uint32
+	SRGB        bool
+}
+
+var formats = map[vk.Format]FormatInfo{
+	vk.FormatR8g8b8a8Unorm: {"RGBA8", 1, 1, 4, false},
+	vk.FormatR8g8b8a8Srgb:  {"RGBA8 sRGB", 1, 1, 4, true},
+	vk.FormatB8g8r8a8Unorm: {"BGRA8", 1, 1, 4, false},
+	vk.FormatB8g8r8a8Srgb:  {"BGRA8 sRGB", 1, 1, 4, true},
+
+	vk.FormatBc1RgbUnormBlock:  {"BC1 RGB", 4, 4, 8, false},
+	vk.FormatBc1RgbSrgbBlock:   {"BC1 RGB sRGB", 4, 4, 8, true},
+	vk.FormatBc1RgbaUnormBlock: {"BC1 RGBA", 4, 4, 8, false},
+	vk.FormatBc1RgbaSrgbBlock:  {"BC1 RGBA sRGB", 4, 4, 8, true},
+	vk.FormatBc2UnormBlock:     {"BC2", 4, 4, 16, false},
+	vk.FormatBc2SrgbBlock:      {"BC2 sRGB", 4, 4, 16, true},
+	vk.FormatBc3UnormBlock:     {"BC3", 4, 4, 16, false},
+	vk.FormatBc3SrgbBlock:      {"BC3 sRGB", 4, 4, 16, true},
+	vk.FormatBc4UnormBlock:     {"BC4", 4, 4, 8, false},
+	vk.FormatBc4SnormBlock:     {"BC4 signed", 4, 4, 8, false},
+	vk.FormatBc5UnormBlock:     {"BC5", 4, 4, 16, false},
+	vk.FormatBc5SnormBlock:     {"BC5 signed", 4, 4, 16, false},
+	vk.FormatBc6hUfloatBlock:   {"BC6H", 4, 4, 16, false},
+	vk.FormatBc6hSfloatBlock:   {"BC6H signed", 4, 4, 16, false},
+	vk.FormatBc7UnormBlock:     {"BC7", 4, 4, 16, false},
+	vk.FormatBc7SrgbBlock:      {"BC7 sRGB", 4, 4, 16, true},
+
+	vk.FormatEtc2R8g8b8UnormBlock:   {"ETC2 RGB", 4, 4, 8, false},
+	vk.FormatEtc2R8g8b8SrgbBlock:    {"ETC2 RGB sRGB", 4, 4, 8, true},
+	vk.FormatEtc2R8g8b8a1UnormBlock: {"ETC2 RGB A1", 4, 4, 8, false},
+	vk.FormatEtc2R8g8b8a1SrgbBlock:  {"ETC2 RGB A1 sRGB", 4, 4, 8, true},
+	vk.FormatEtc2R8g8b8a8UnormBlock: {"ETC2 RGBA", 4, 4, 16, false},
+	vk.FormatEtc2R8g8b8a8SrgbBlock:  {"ETC2 RGBA sRGB", 4, 4, 16, true},
+	vk.FormatEacR11UnormBlock:       {"EAC R11", 4, 4, 8, false},
+	vk.FormatEacR11SnormBlock:       {"EAC R11 signed", 4, 4, 8, false},
+	vk.FormatEacR11g11UnormBlock:    {"EAC RG11", 4, 4, 16, false},
+	vk.FormatEacR11g11SnormBlock:    {"EAC RG11 signed", 4, 4, 16, false},
+
+	vk.FormatAstc4x4UnormBlock:   {"ASTC 4x4", 4, 4, 16, false},
+	vk.FormatAstc4x4SrgbBlock:    {"ASTC 4x4 sRGB", 4, 4, 16, true},
+	vk.FormatAstc5x4UnormBlock:   {"ASTC 5x4", 5, 4, 16, false},
+	vk.FormatAstc5x4SrgbBlock:    {"ASTC 5x4 sRGB", 5, 4, 16, true},
+	vk.FormatAstc5x5UnormBlock:   {"ASTC 5x5", 5, 5, 16, false},
+	vk.FormatAstc5x5SrgbBlock:    {"ASTC 5x5 sRGB", 5, 5, 16, true},
+	vk.FormatAstc6x5UnormBlock:   {"ASTC 6x5", 6, 5, 16, false},
+	vk.FormatAstc6x5SrgbBlock:    {"ASTC 6x5 sRGB", 6, 5, 16, true},
+	vk.FormatAstc6x6UnormBlock:   {"ASTC 6x6", 6, 6, 16, false},
+	vk.FormatAstc6x6SrgbBlock:    {"ASTC 6x6 sRGB", 6, 6, 16, true},
+	vk.FormatAstc8x5UnormBlock:   {"ASTC 8x5", 8, 5, 16, false},
+	vk.FormatAstc8x5SrgbBlock:    {"ASTC 8x5 sRGB", 8, 5, 16, true},
+	vk.FormatAstc8x6UnormBlock:   {"ASTC 8x6", 8, 6, 16, false},
+	vk.FormatAstc8x6SrgbBlock:    {"ASTC 8x6 sRGB", 8, 6, 16, true},
+	vk.FormatAstc8x8UnormBlock:   {"ASTC 8x8", 8, 8, 16, false},
+	vk.FormatAstc8x8SrgbBlock:    {"ASTC 8x8 sRGB", 8, 8, 16, true},
+	vk.FormatAstc10x5UnormBlock:  {"ASTC 10x5", 10, 5, 16, false},
+	vk.FormatAstc10x5SrgbBlock:   {"ASTC 10x5 sRGB", 10, 5, 16, true},
+	vk.FormatAstc10x6UnormBlock:  {"ASTC 10x6", 10, 6, 16, false},
+	vk.FormatAstc10x6SrgbBlock:   {"ASTC 10x6 sRGB", 10, 6, 16, true},
+	vk.FormatAstc10x8UnormBlock:  {"ASTC 10x8", 10, 8, 16, false},
+	vk.FormatAstc10x8SrgbBlock:   {"ASTC 10x8 sRGB", 10, 8, 16, true},
+	vk.FormatAstc10x10UnormBlock: {"ASTC 10x10", 10, 10, 16, false},
+	vk.FormatAstc10x10SrgbBlock:  {"ASTC 10x10 sRGB", 10, 10, 16, true},
+	vk.FormatAstc12x10UnormBlock: {"ASTC 12x10", 12, 10, 16, false},
+	vk.FormatAstc12x10SrgbBlock:  {"ASTC 12x10 sRGB", 12, 10, 16, true},
+	vk.FormatAstc12x12UnormBlock: {"ASTC 12x12", 12, 12, 16, false},
+	vk.FormatAstc12x12SrgbBlock:  {"ASTC 12x12 sRGB", 12, 12, 16, true},
+}
+
+// Info returns the layout of a format this package knows.
+func Info(format vk.Format) (FormatInfo, bool) {
+	info, ok := formats[format]
+	return info, ok
+}
+
+// Formats lists every format this package knows, in enum order.
+func Formats() []vk.Format {
+	list := make([]vk.Format, 0, len(formats))
+	for f := range formats {
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// FormatName names a format for messages.
+func FormatName(format vk.Format) string {
+	if info, ok := formats[format]; ok {
+		return info.Name
+	}
+	return fmt.Sprintf("format %d", format)
+}
+
+// LevelSize returns the bytes of one image of a level, for a level of
+// width x height texels.
+func (info FormatInfo) LevelSize(width, height uint32) int {
+	bw := (width + info.BlockWidth - 1) / info.BlockWidth
+	bh := (height + info.BlockHeight - 1) / info.BlockHeight
+	return int(bw * bh * info.BlockBytes)
+}
+
+// MipSize is the size of a dimension at a mip level, at least 1.
+func MipSize(size uint32, level int) uint32 {
+	if size >>= uint(level); size == 0 {
+		return 1
+	}
+	return size
+}
+
+// validate checks that every level holds at least the data its size needs
+// and trims the excess.
+func (img *Image) validate() error {
+	info, ok := formats[img.Format]
+	if !ok {
+		return fmt.Errorf("unsupported format %d", img.Format)
+	}
+	if img.Width == 0 || img.Height == 0 {
+		return fmt.Errorf("empty %dx%d image", img.Width, img.Height)
+	}
+	for i, level := range img.Levels {
+		size := info.LevelSize(MipSize(img.Width, i), MipSize(img.Height, i))
+		if len(level) < size {
+			return fmt.Errorf("mip level %d has %d bytes, want %d", i, len(level), size)
+		}
+		img.Levels[i] = level[:size]
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package texfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// ktx2Header is the fixed part of a KTX2 file after the identifier,
+// including the index of the data format descriptor, key/value data and
+// supercompression global data.
+type ktx2Header struct {
+	VkFormat               uint32
+	TypeSize               uint32
+	PixelWidth             uint32
+	PixelHeight            uint32
+	PixelDepth             uint32
+	LayerCount             uint32
+	FaceCount              uint32
+	LevelCount             uint32
+	SupercompressionScheme uint32
+
+	DFDByteOffset uint32
+	DFDByteLength uint32
+	KVDByteOffset uint32
+	KVDByteLength uint32
+	SGDByteOffset uint64
+	SGDByteLength uint64
+}
+
+type ktx2Level struct {
+	ByteOffset             uint64
+	ByteLength             uint64
+	UncompressedByteLength uint64
+}
+
+// IsKTX2 reports whether data starts with the KTX2 identifier.
+func IsKTX2(data []byte) bool {
+	return bytes.HasPrefix(data, ktx2Identifier)
+}
+
+// DecodeKTX2 reads the first layer and face of a 2D KTX2 texture.
+// Supercompressed files, including Basis Universal, are not supported.
+func DecodeKTX2(data []byte) (*Image, error) {
+	if !IsKTX2(data) {
+		return nil, errors.New("ktx2: not a KTX2 file")
+	}
+	r := bytes.NewReader(data[len(ktx2Identifier):])
+	var h ktx2Header
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, fmt.Errorf("ktx2: reading header: %v", err)
+	}
+	switch {
+	case h.VkFormat == 0:
+		return nil, errors.New("ktx2: Basis Universal textures are not supported")
+	case h.SupercompressionScheme != 0:
+		return nil, fmt.Errorf("ktx2: supercompression scheme %d is not supported", h.SupercompressionScheme)
+	case h.PixelHeight == 0 || h.PixelDepth > 1:
+		return nil, errors.New("ktx2: only 2D textures are supported")
+	}
+	levelCount := h.LevelCount
+	if levelCount == 0 {
+		// Zero asks the loader to generate the mip chain itself.
+		levelCount = 1
+	}
+	layers := h.LayerCount
+	if layers == 0 {
+		layers = 1
+	}
+	faces := h.FaceCount
+	if faces == 0 {
+		faces = 1
+	}
+
+	img := &Image{
+		Format: vk.Format(h.VkFormat),
+		Width:  h.PixelWidth,
+		Height: h.PixelHeight,
+	}
+	info, ok := Info(img.Format)
+	if !ok {
+		return nil, fmt.Errorf("ktx2: unsupported format %d", h.VkFormat)
+	}
+	for i := 0; i < int(levelCount); i++ {
+		var level ktx2Level
+		if err := binary.Read(r, binary.LittleEndian, &level); err != nil {
+			return nil, fmt.Errorf("ktx2: reading level index: %v", err)
+		}
+		end := level.ByteOffset + level.ByteLength
+		if end > uint64(len(data)) || end < level.ByteOffset {
+			return nil, fmt.Errorf("ktx2: mip level %d lies outside the file", i)
+		}
+		// A level holds every layer and face; the first image comes first.
+		size := uint64(info.LevelSize(MipSize(img.Width, i), MipSize(img.Height, i)))
+		if size*uint64(layers)*uint64(faces) > level.ByteLength {
+			return nil, fmt.Errorf("ktx2: mip level %d is truncated", i)
+		}
+		img.Levels = append(img.Levels, data[level.ByteOffset:level.ByteOffset+size])
+	}
+	if err := img.validate(); err != nil {
+		return nil, fmt.Errorf("ktx2: %v", err)
+	}
+	return img, nil
+}
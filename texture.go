@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/png"
+	"io/ioutil"
+	"log"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+
+	"./bindata"
+	"./memory"
+	"./texfile"
+)
+
+// SamplerConfig describes how a texture is sampled.
+type SamplerConfig struct {
+	// Filter is "linear" or "nearest" and applies to magnification and
+	// minification alike.
+	Filter string `json:"filter"`
+	// MipFilter is "linear" or "nearest" between mip levels, or "none" to
+	// sample only the full-size level.
+	MipFilter   string  `json:"mipFilter"`
+	AddressMode string  `json:"addressMode"`
+	LODBias     float64 `json:"lodBias"`
+}
+
+var samplerFilters = map[string]vk.Filter{
+	"linear":  vk.FilterLinear,
+	"nearest": vk.FilterNearest,
+}
+
+var samplerMipFilters = map[string]vk.SamplerMipmapMode{
+	"linear":  vk.SamplerMipmapModeLinear,
+	"nearest": vk.SamplerMipmapModeNearest,
+	"none":    vk.SamplerMipmapModeNearest,
+}
+
+var samplerAddressModes = map[string]vk.SamplerAddressMode{
+	"repeat":          vk.SamplerAddressModeRepeat,
+	"mirrored-repeat": vk.SamplerAddressModeMirroredRepeat,
+	"clamp-to-edge":   vk.SamplerAddressModeClampToEdge,
+	"clamp-to-border": vk.SamplerAddressModeClampToBorder,
+}
+
+func defaultSamplerConfig() SamplerConfig {
+	return SamplerConfig{
+		Filter:      "linear",
+		MipFilter:   "linear",
+		AddressMode: "repeat",
+	}
+}
+
+func (s SamplerConfig) validate() error {
+	if _, ok := samplerFilters[s.Filter]; !ok {
+		return fmt.Errorf("unknown sampler filter %q, want one of %s", s.Filter, joinKeys(samplerFilters))
+	}
+	if _, ok := samplerMipFilters[s.MipFilter]; !ok {
+		return fmt.Errorf("unknown sampler mip filter %q, want one of %s", s.MipFilter, joinKeys(samplerMipFilters))
+	}
+	if _, ok := samplerAddressModes[s.AddressMode]; !ok {
+		return fmt.Errorf("unknown sampler address mode %q, want one of %s", s.AddressMode, joinKeys(samplerAddressModes))
+	}
+	return nil
+}
+
+// joinKeys lists the keys of a name table for error messages.
+func joinKeys(table interface{}) string {
+	var names []string
+	for _, key := range reflect.ValueOf(table).MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Texture is a sampled 2D image with its mip chain and its own sampler.
+type Texture struct {
+	format    vk.Format
+	width     uint32
+	height    uint32
+	mipLevels uint32
+	image     vk.Image
+	alloc     *memory.Allocation
+	view      vk.ImageView
+	sampler   vk.Sampler
+}
+
+// mipLevelCount is the length of a full mip chain down to 1x1.
+func mipLevelCount(width, height uint32) uint32 {
+	levels := uint32(1)
+	for width > 1 || height > 1 {
+		width, height = width/2, height/2
+		levels++
+	}
+	return levels
+}
+
+// readAsset returns an embedded asset, or the file of that name when
+// nothing is embedded under it.
+func readAsset(name string) ([]byte, error) {
+	if data, err := bindata.Asset(name); err == nil {
+		return data, nil
+	}
+	return ioutil.ReadFile(name)
+}
+
+func decodeRGBA(data []byte) (*image.RGBA, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba, nil
+	}
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, src, b.Min, draw.Src)
+	return rgba, nil
+}
+
+// loadTexture reads a KTX2, DDS or common image file and uploads it with
+// mipmaps. The upload is only flushed, not waited for; the texture must
+// not be sampled before the returned ticket is done. Files that cannot be
+// read or uploaded return an error before any resource is created.
+func (a *Application) loadTexture(name string, sampler SamplerConfig) (*Texture, *UploadTicket, error) {
+	data, err := readAsset(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if texfile.IsKTX2(data) || texfile.IsDDS(data) {
+		return a.loadContainerTexture(name, data, sampler)
+	}
+	img, err := decodeRGBA(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("texture %s: %v", name, err)
+	}
+	t, ticket := a.textureFromRGBA(name, img, vk.FormatR8g8b8a8Unorm, sampler)
+	return t, ticket, nil
+}
+
+// loadContainerTexture uploads the mip levels stored in a KTX2 or DDS
+// file. Formats the device cannot sample are decompressed on the CPU when
+// possible, which BC6H, BC7 and ASTC are not; a single uncompressed level
+// gets its mipmaps generated.
+func (a *Application) loadContainerTexture(name string, data []byte, sampler SamplerConfig) (*Texture, *UploadTicket, error) {
+	decode := texfile.DecodeDDS
+	if texfile.IsKTX2(data) {
+		decode = texfile.DecodeKTX2
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("texture %s: %v", name, err)
+	}
+	if !a.formatSampleable(img.Format) {
+		if !texfile.CanDecompress(img.Format) {
+			return nil, nil, fmt.Errorf("texture %s: the device cannot sample %s and it cannot be decompressed",
+				name, texfile.FormatName(img.Format))
+		}
+		log.Printf("texture %s: the device cannot sample %s, decompressing it on the CPU",
+			name, texfile.FormatName(img.Format))
+		img, err = texfile.Decompress(img)
+		if err != nil {
+			return nil, nil, fmt.Errorf("texture %s: %v", name, err)
+		}
+	}
+	if info, _ := texfile.Info(img.Format); info.BlockWidth == 1 && len(img.Levels) == 1 {
+		rgba := &image.RGBA{
+			Pix:    img.Levels[0],
+			Stride: int(img.Width) * 4,
+			Rect:   image.Rect(0, 0, int(img.Width), int(img.Height)),
+		}
+		t, ticket := a.textureFromRGBA(name, rgba, img.Format, sampler)
+		return t, ticket, nil
+	}
+
+	t := a.newTexture(img.Format, img.Width, img.Height, uint32(len(img.Levels)), 0)
+	var pixels []byte
+	var copies []vk.BufferImageCopy
+	for i, level := range img.Levels {
+		copies = append(copies, t.levelCopy(uint32(i), len(pixels)))
+		pixels = append(pixels, level...)
+	}
+	a.uploader.Image(t.image, t.subresource(), copies, pixels, vk.ImageLayoutShaderReadOnlyOptimal)
+	ticket := a.uploader.Flush()
+	a.finishTexture(name, t, sampler)
+	return t, ticket, nil
+}
+
+// textureFromRGBA uploads an image of 4-byte texels in format and fills
+// the rest of the mip chain, with blits where the device can filter the
+// format linearly and on the CPU otherwise.
+func (a *Application) textureFromRGBA(name string, img *image.RGBA, format vk.Format, sampler SamplerConfig) (*Texture, *UploadTicket) {
+	width, height := uint32(img.Rect.Dx()), uint32(img.Rect.Dy())
+	blit := a.linearBlitSupported(format)
+	var usage vk.ImageUsageFlagBits
+	if blit {
+		usage = vk.ImageUsageTransferSrcBit
+	}
+	t := a.newTexture(format, width, height, mipLevelCount(width, height), usage)
+
+	if blit {
+		a.uploader.Image(t.image, t.subresource(), []vk.BufferImageCopy{t.levelCopy(0, 0)},
+			img.Pix, vk.ImageLayoutTransferDstOptimal)
+		a.uploader.Then(t.generateMipmaps)
+	} else {
+		log.Printf("texture %s: %s cannot be blitted linearly, building mipmaps on the CPU",
+			name, texfile.FormatName(format))
+		var pixels []byte
+		var copies []vk.BufferImageCopy
+		level := img
+		for i := uint32(0); i < t.mipLevels; i++ {
+			if i > 0 {
+				level = downsample(level)
+			}
+			copies = append(copies, t.levelCopy(i, len(pixels)))
+			pixels = append(pixels, level.Pix...)
+		}
+		a.uploader.Image(t.image, t.subresource(), copies, pixels, vk.ImageLayoutShaderReadOnlyOptimal)
+	}
+	ticket := a.uploader.Flush()
+	a.finishTexture(name, t, sampler)
+	return t, ticket
+}
+
+// newTexture creates the image of a texture and allocates its memory.
+// Every texture is sampled and uploaded to; usage adds to that.
+func (a *Application) newTexture(format vk.Format, width, height, mipLevels uint32, usage vk.ImageUsageFlagBits) *Texture {
+	t := &Texture{
+		format:    format,
+		width:     width,
+		height:    height,
+		mipLevels: mipLevels,
+	}
+	usage |= vk.ImageUsageSampledBit | vk.ImageUsageTransferDstBit
+	ret := vk.CreateImage(a.Context().Device(), &vk.ImageCreateInfo{
+		SType:     vk.StructureTypeImageCreateInfo,
+		ImageType: vk.ImageType2d,
+		Format:    format,
+		Extent: vk.Extent3D{
+			Width:  width,
+			Height: height,
+			Depth:  1,
+		},
+		MipLevels:     mipLevels,
+		ArrayLayers:   1,
+		Samples:       vk.SampleCount1Bit,
+		Tiling:        vk.ImageTilingOptimal,
+		Usage:         vk.ImageUsageFlags(usage),
+		InitialLayout: vk.ImageLayoutUndefined,
+	}, nil, &t.image)
+	orPanic(as.NewError(ret))
+	var err error
+	t.alloc, err = a.allocator.AllocateImage(t.image, vk.MemoryPropertyDeviceLocalBit, memory.Optimal)
+	orPanic(err)
+	return t
+}
+
+// finishTexture creates the view and sampler once the upload is recorded.
+func (a *Application) finishTexture(name string, t *Texture, sampler SamplerConfig) {
+	ret := vk.CreateImageView(a.Context().Device(), &vk.ImageViewCreateInfo{
+		SType:            vk.StructureTypeImageViewCreateInfo,
+		Format:           t.format,
+		SubresourceRange: t.subresource(),
+		ViewType:         vk.ImageViewType2d,
+		Image:            t.image,
+	}, nil, &t.view)
+	orPanic(as.NewError(ret))
+	t.sampler = a.createSampler(sampler, t.mipLevels)
+
+	base := path.Base(name)
+	a.debug.Name(t.image, "texture "+base)
+	a.debug.Name(t.view, "texture view "+base)
+	a.debug.Name(t.sampler, "sampler "+base)
+}
+
+func (t *Texture) subresource() vk.ImageSubresourceRange {
+	return vk.ImageSubresourceRange{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LevelCount: t.mipLevels,
+		LayerCount: 1,
+	}
+}
+
+func (t *Texture) levelCopy(level uint32, offset int) vk.BufferImageCopy {
+	return vk.BufferImageCopy{
+		BufferOffset: vk.DeviceSize(offset),
+		ImageSubresource: vk.ImageSubresourceLayers{
+			AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			MipLevel:   level,
+			LayerCount: 1,
+		},
+		ImageExtent: vk.Extent3D{
+			Width:  texfile.MipSize(t.width, int(level)),
+			Height: texfile.MipSize(t.height, int(level)),
+			Depth:  1,
+		},
+	}
+}
+
+// formatSampleable reports whether optimally tiled images of format can be
+// sampled.
+func (a *Application) formatSampleable(format vk.Format) bool {
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(a.Context().Platform().PhysicalDevice(), format, &props)
+	props.Deref()
+	return vk.FormatFeatureFlagBits(props.OptimalTilingFeatures)&vk.FormatFeatureSampledImageBit != 0
+}
+
+// linearBlitSupported reports whether optimally tiled images of format can
+// be both source and destination of a linearly filtered blit.
+func (a *Application) linearBlitSupported(format vk.Format) bool {
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(a.Context().Platform().PhysicalDevice(), format, &props)
+	props.Deref()
+	need := vk.FormatFeatureBlitSrcBit | vk.FormatFeatureBlitDstBit |
+		vk.FormatFeatureSampledImageFilterLinearBit
+	return vk.FormatFeatureFlagBits(props.OptimalTilingFeatures)&need == need
+}
+
+// generateMipmaps fills every level from the one above it with blits. It
+// expects all levels in TransferDstOptimal, level 0 holding the image,
+// and leaves them in ShaderReadOnlyOptimal.
+func (t *Texture) generateMipmaps(cmd vk.CommandBuffer) {
+	barrier := func(level uint32, oldLayout, newLayout vk.ImageLayout,
+		srcAccess, dstAccess vk.AccessFlagBits, dstStage vk.PipelineStageFlagBits) {
+
+		vk.CmdPipelineBarrier(cmd,
+			vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+			vk.PipelineStageFlags(dstStage),
+			0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{{
+				SType:               vk.StructureTypeImageMemoryBarrier,
+				SrcAccessMask:       vk.AccessFlags(srcAccess),
+				DstAccessMask:       vk.AccessFlags(dstAccess),
+				OldLayout:           oldLayout,
+				NewLayout:           newLayout,
+				SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+				DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+				Image:               t.image,
+				SubresourceRange: vk.ImageSubresourceRange{
+					AspectMask:   vk.ImageAspectFlags(vk.ImageAspectColorBit),
+					BaseMipLevel: level,
+					LevelCount:   1,
+					LayerCount:   1,
+				},
+			}})
+	}
+
+	for level := uint32(1); level < t.mipLevels; level++ {
+		barrier(level-1, vk.ImageLayoutTransferDstOptimal, vk.ImageLayoutTransferSrcOptimal,
+			vk.AccessTransferWriteBit, vk.AccessTransferReadBit, vk.PipelineStageTransferBit)
+		vk.CmdBlitImage(cmd,
+			t.image, vk.ImageLayoutTransferSrcOptimal,
+			t.image, vk.ImageLayoutTransferDstOptimal,
+			1, []vk.ImageBlit{{
+				SrcSubresource: vk.ImageSubresourceLayers{
+					AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+					MipLevel:   level - 1,
+					LayerCount: 1,
+				},
+				SrcOffsets: [2]vk.Offset3D{{}, {
+					X: int32(texfile.MipSize(t.width, int(level-1))),
+					Y: int32(texfile.MipSize(t.height, int(level-1))),
+					Z: 1,
+				}},
+				DstSubresource: vk.ImageSubresourceLayers{
+					AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+					MipLevel:   level,
+					LayerCount: 1,
+				},
+				DstOffsets: [2]vk.Offset3D{{}, {
+					X: int32(texfile.MipSize(t.width, int(level))),
+					Y: int32(texfile.MipSize(t.height, int(level))),
+					Z: 1,
+				}},
+			}}, vk.FilterLinear)
+		barrier(level-1, vk.ImageLayoutTransferSrcOptimal, vk.ImageLayoutShaderReadOnlyOptimal,
+			vk.AccessTransferReadBit, vk.AccessShaderReadBit, vk.PipelineStageFragmentShaderBit)
+	}
+	barrier(t.mipLevels-1, vk.ImageLayoutTransferDstOptimal, vk.ImageLayoutShaderReadOnlyOptimal,
+		vk.AccessTransferWriteBit, vk.AccessShaderReadBit, vk.PipelineStageFragmentShaderBit)
+}
+
+// downsample halves img with a box filter; odd edges fold their last row
+// or column into the previous output texel.
+func downsample(img *image.RGBA) *image.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	dw, dh := w/2, h/2
+	if dw == 0 {
+		dw = 1
+	}
+	if dh == 0 {
+		dh = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, (y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, (x+1)*w/dw
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := sy*img.Stride + sx*4
+					for c := 0; c < 4; c++ {
+						sum[c] += int(img.Pix[i+c])
+					}
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			i := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8((sum[c] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
+
+// createSampler builds a sampler for a texture with mipLevels levels. It
+// never filters anisotropically, as asche creates the device without the
+// samplerAnisotropy feature.
+func (a *Application) createSampler(cfg SamplerConfig, mipLevels uint32) vk.Sampler {
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(a.Context().Platform().PhysicalDevice(), &props)
+	props.Deref()
+	props.Limits.Deref()
+
+	bias := float32(cfg.LODBias)
+	if max := props.Limits.MaxSamplerLodBias; bias > max {
+		bias = max
+	} else if bias < -max {
+		bias = -max
+	}
+	maxLod := float32(mipLevels)
+	if cfg.MipFilter == "none" {
+		maxLod = 0
+	}
+
+	filter := samplerFilters[cfg.Filter]
+	address := samplerAddressModes[cfg.AddressMode]
+	var sampler vk.Sampler
+	ret := vk.CreateSampler(a.Context().Device(), &vk.SamplerCreateInfo{
+		SType:        vk.StructureTypeSamplerCreateInfo,
+		MagFilter:    filter,
+		MinFilter:    filter,
+		MipmapMode:   samplerMipFilters[cfg.MipFilter],
+		AddressModeU: address,
+		AddressModeV: address,
+		AddressModeW: address,
+		MipLodBias:   bias,
+		CompareOp:    vk.CompareOpNever,
+		MinLod:       0,
+		MaxLod:       maxLod,
+		BorderColor:  vk.BorderColorFloatOpaqueBlack,
+	}, nil, &sampler)
+	orPanic(as.NewError(ret))
+	return sampler
+}
+
+func (t *Texture) Destroy(dev vk.Device) {
+	vk.DestroySampler(dev, t.sampler, nil)
+	vk.DestroyImageView(dev, t.view, nil)
+	vk.DestroyImage(dev, t.image, nil)
+	t.alloc.Free()
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const (
+	uiScale      = 1
+	uiPad        = 6
+	uiRowHeight  = 16
+	uiPanelWidth = 240
+	uiLabelWidth = 96
+)
+
+var (
+	uiColorPanel   = rgba(0.08, 0.08, 0.1, 0.85)
+	uiColorTitle   = rgba(0.2, 0.3, 0.5, 1)
+	uiColorWidget  = rgba(0.25, 0.25, 0.3, 1)
+	uiColorHot     = rgba(0.35, 0.35, 0.45, 1)
+	uiColorActive  = rgba(0.3, 0.5, 0.8, 1)
+	uiColorText    = rgba(0.95, 0.95, 0.95, 1)
+	uiColorTextDim = rgba(0.65, 0.65, 0.7, 1)
+)
+
+// UI is an immediate-mode widget layer: registered panels rebuild their
+// widgets every frame from the values they edit, and each widget reports
+// whether the user changed that value this frame.
+type UI struct {
+	canvas  *Canvas
+	batch   TextBatch
+	popups  TextBatch
+	panels  []*uiPanel
+	visible bool
+
+	mouseX, mouseY float32
+	mouseDown      bool
+	mousePressed   bool
+	mouseReleased  bool
+
+	// active is the widget holding the mouse, hot the one under it.
+	active    string
+	hot       string
+	openCombo string
+	dragPanel *uiPanel
+	dragX     float32
+	dragY     float32
+	wantMouse bool
+
+	panel  *uiPanel
+	cursor float32
+}
+
+type uiPanel struct {
+	title     string
+	x, y      float32
+	height    float32
+	collapsed bool
+	build     func(ui *UI)
+}
+
+// prepareUI creates the UI and its settings panel on the first call. After
+// swapchain recreation only its canvas is resized, and panels that would
+// now be off the right edge are moved back in.
+func (a *Application) prepareUI() {
+	if a.ui != nil {
+		a.resizeCanvas(a.ui.canvas)
+		maxX := float32(a.width) - uiPanelWidth - uiPad
+		for _, p := range a.ui.panels {
+			if p.x > maxX {
+				p.x = maxX
+				if p.x < uiPad {
+					p.x = uiPad
+				}
+			}
+		}
+		return
+	}
+	a.ui = &UI{
+		canvas:  a.newCanvas("ui", a.font, 4096),
+		visible: true,
+	}
+	a.overlayPasses = append(a.overlayPasses, a.ui.canvas.draw)
+	a.registerSettingsPanel()
+}
+
+// RegisterPanel adds a window whose contents build emits every frame.
+// Panels are stacked down the right edge of the window until moved.
+func (ui *UI) RegisterPanel(title string, build func(ui *UI)) {
+	y := float32(uiPad)
+	for _, p := range ui.panels {
+		y += p.height + uiPad
+	}
+	ui.panels = append(ui.panels, &uiPanel{
+		title:  title,
+		x:      float32(ui.canvas.width) - uiPanelWidth - uiPad,
+		y:      y,
+		height: uiRowHeight,
+		build:  build,
+	})
+}
+
+// update runs every panel against this frame's input and uploads the result
+// for the given swapchain image. Afterwards wantMouse tells whether the
+// cursor was over the UI, so other consumers should ignore the mouse.
+func (ui *UI) update(dev vk.Device, imageIdx int, in *Input) {
+	ui.mouseX, ui.mouseY = in.Cursor()
+	ui.mouseDown = in.Down("ui.click")
+	ui.mousePressed = in.Pressed("ui.click")
+	ui.mouseReleased = in.Released("ui.click")
+	if in.Pressed("ui.toggle") {
+		ui.visible = !ui.visible
+	}
+
+	ui.batch.Reset()
+	ui.popups.Reset()
+	ui.hot = ""
+	ui.wantMouse = false
+	if ui.visible {
+		for _, p := range ui.panels {
+			ui.runPanel(p)
+		}
+		if ui.active != "" || ui.dragPanel != nil {
+			ui.wantMouse = true
+		}
+	}
+	if ui.mouseReleased {
+		ui.active = ""
+		ui.dragPanel = nil
+	}
+
+	ui.batch.vertices = append(ui.batch.vertices, ui.popups.vertices...)
+	ui.canvas.upload(dev, imageIdx, &ui.batch)
+}
+
+func (ui *UI) hovered(x, y, w, h float32) bool {
+	return ui.mouseX >= x && ui.mouseX < x+w && ui.mouseY >= y && ui.mouseY < y+h
+}
+
+func (ui *UI) runPanel(p *uiPanel) {
+	if ui.dragPanel == p && ui.mouseDown {
+		p.x = ui.mouseX - ui.dragX
+		p.y = ui.mouseY - ui.dragY
+	}
+	if ui.hovered(p.x, p.y, uiPanelWidth, p.height) {
+		ui.wantMouse = true
+	}
+
+	// The background uses last frame's height, since the widgets that
+	// determine it have not run yet.
+	ui.batch.Rect(p.x, p.y, uiPanelWidth, p.height, uiColorPanel)
+	ui.batch.Rect(p.x, p.y, uiPanelWidth, uiRowHeight, uiColorTitle)
+	marker := "-"
+	if p.collapsed {
+		marker = "+"
+	}
+	ui.batch.Text(p.x+uiPad, p.y+4, uiScale, uiColorText, marker+" "+p.title)
+	if ui.mousePressed && ui.hovered(p.x, p.y, uiPanelWidth, uiRowHeight) {
+		if ui.mouseX < p.x+uiPad+2*glyphSize*uiScale {
+			p.collapsed = !p.collapsed
+		} else {
+			ui.dragPanel = p
+			ui.dragX, ui.dragY = ui.mouseX-p.x, ui.mouseY-p.y
+		}
+	}
+	if p.collapsed {
+		p.height = uiRowHeight
+		return
+	}
+
+	ui.panel = p
+	ui.cursor = p.y + uiRowHeight + uiPad
+	p.build(ui)
+	p.height = ui.cursor - p.y
+	ui.panel = nil
+}
+
+// row reserves the next line of the current panel and returns its bounds.
+func (ui *UI) row() (x, y, w float32) {
+	x, y = ui.panel.x+uiPad, ui.cursor
+	ui.cursor += uiRowHeight + 2
+	return x, y, uiPanelWidth - 2*uiPad
+}
+
+// id derives a widget identifier from its label. As in Dear ImGui, anything
+// after "##" is part of the identifier but not displayed, which keeps
+// widgets with the same visible label apart.
+func (ui *UI) id(label string) (id, text string) {
+	text = label
+	if i := strings.Index(label, "##"); i >= 0 {
+		text = label[:i]
+	}
+	return ui.panel.title + "/" + label, text
+}
+
+// Label adds a line of text.
+func (ui *UI) Label(format string, args ...interface{}) {
+	x, y, _ := ui.row()
+	ui.batch.Text(x, y+4, uiScale, uiColorTextDim, fmt.Sprintf(format, args...))
+}
+
+// Button returns true when the button is clicked.
+func (ui *UI) Button(label string) bool {
+	x, y, w := ui.row()
+	id, label := ui.id(label)
+	ui.batch.Rect(x, y, w, uiRowHeight, ui.widgetColor(id, x, y, w, uiRowHeight))
+	ui.batch.Text(x+(w-textWidth(label, uiScale))/2, y+4, uiScale, uiColorText, label)
+	return ui.mouseReleased && ui.active == id && ui.hot == id
+}
+
+// Checkbox toggles *v when clicked and reports whether it changed.
+func (ui *UI) Checkbox(label string, v *bool) bool {
+	x, y, w := ui.row()
+	id, label := ui.id(label)
+	color := ui.widgetColor(id, x, y, w, uiRowHeight)
+	ui.batch.Rect(x, y+2, 12, 12, color)
+	if *v {
+		ui.batch.Rect(x+3, y+5, 6, 6, uiColorText)
+	}
+	ui.batch.Text(x+20, y+4, uiScale, uiColorText, label)
+	if ui.mouseReleased && ui.active == id && ui.hot == id {
+		*v = !*v
+		return true
+	}
+	return false
+}
+
+// SliderFloat drags *v between min and max and reports whether it changed.
+func (ui *UI) SliderFloat(label string, v *float32, min, max float32) bool {
+	x, y, w := ui.row()
+	id, label := ui.id(label)
+	ui.batch.Text(x, y+4, uiScale, uiColorTextDim, label)
+	bx, bw := x+uiLabelWidth, w-uiLabelWidth
+	ui.batch.Rect(bx, y, bw, uiRowHeight, ui.widgetColor(id, bx, y, bw, uiRowHeight))
+
+	changed := false
+	if ui.active == id && ui.mouseDown {
+		t := (ui.mouseX - bx) / bw
+		if t < 0 {
+			t = 0
+		} else if t > 1 {
+			t = 1
+		}
+		if nv := min + t*(max-min); nv != *v {
+			*v = nv
+			changed = true
+		}
+	}
+	t := (*v - min) / (max - min)
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	ui.batch.Rect(bx+t*(bw-4), y, 4, uiRowHeight, uiColorActive)
+	text := fmt.Sprintf("%.3g", *v)
+	ui.batch.Text(bx+(bw-textWidth(text, uiScale))/2, y+4, uiScale, uiColorText, text)
+	return changed
+}
+
+// ColorEdit edits the four channels of an RGBA color with sliders.
+func (ui *UI) ColorEdit(label string, c *[4]float32) bool {
+	ui.Label("%s", label)
+	changed := false
+	for i, ch := range []string{"  r", "  g", "  b", "  a"} {
+		if ui.SliderFloat(ch+"##"+label, &c[i], 0, 1) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Combo picks one of items into *current and reports whether it changed.
+// The open list is drawn after all panels so it covers them.
+func (ui *UI) Combo(label string, current *int, items []string) bool {
+	x, y, w := ui.row()
+	id, label := ui.id(label)
+	ui.batch.Text(x, y+4, uiScale, uiColorTextDim, label)
+	bx, bw := x+uiLabelWidth, w-uiLabelWidth
+	ui.batch.Rect(bx, y, bw, uiRowHeight, ui.widgetColor(id, bx, y, bw, uiRowHeight))
+	if *current >= 0 && *current < len(items) {
+		ui.batch.Text(bx+4, y+4, uiScale, uiColorText, items[*current])
+	}
+	ui.batch.Text(bx+bw-12, y+4, uiScale, uiColorText, "v")
+
+	if ui.mouseReleased && ui.active == id && ui.hot == id {
+		if ui.openCombo == id {
+			ui.openCombo = ""
+		} else {
+			ui.openCombo = id
+		}
+		return false
+	}
+	if ui.openCombo != id {
+		return false
+	}
+
+	changed := false
+	ly := y + uiRowHeight
+	listHeight := float32(len(items)) * uiRowHeight
+	if ui.mousePressed && !ui.hovered(bx, ly, bw, listHeight) {
+		// Clicking anywhere else closes the list.
+		ui.openCombo = ""
+		return false
+	}
+	ui.popups.Rect(bx, ly, bw, listHeight, uiColorPanel)
+	for i, item := range items {
+		iy := ly + float32(i)*uiRowHeight
+		if ui.hovered(bx, iy, bw, uiRowHeight) {
+			ui.wantMouse = true
+			ui.popups.Rect(bx, iy, bw, uiRowHeight, uiColorHot)
+			if ui.mousePressed {
+				*current = i
+				changed = true
+				ui.openCombo = ""
+				// Keep the click from reaching widgets underneath.
+				ui.active = id + "/popup"
+			}
+		}
+		ui.popups.Text(bx+4, iy+4, uiScale, uiColorText, item)
+	}
+	return changed
+}
+
+// widgetColor tracks hot and active state of the widget occupying the given
+// rectangle and returns its background color.
+func (ui *UI) widgetColor(id string, x, y, w, h float32) uint32 {
+	if ui.hovered(x, y, w, h) && ui.dragPanel == nil {
+		ui.hot = id
+		if ui.mousePressed && ui.active == "" {
+			ui.active = id
+		}
+	}
+	switch {
+	case ui.active == id:
+		return uiColorActive
+	case ui.hot == id && ui.active == "":
+		return uiColorHot
+	default:
+		return uiColorWidget
+	}
+}
+
+func (ui *UI) Destroy(dev vk.Device) {
+	ui.canvas.Destroy(dev)
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+const (
+	// uploadRingSize is the size of the staging ring. Uploads larger than
+	// the ring get a staging buffer of their own.
+	uploadRingSize = 16 << 20
+	// uploadAlignment keeps staging offsets valid for buffer-to-image
+	// copies of every texel and block size up to 16 bytes.
+	uploadAlignment = 16
+)
+
+// Uploader copies data into device-local buffers and images. Data is
+// written into a persistently mapped staging ring and the copies are
+// recorded into a batch, which Flush submits. A batch's staging space is
+// reused once its fence has signaled, so loading never waits for the
+// frame being rendered.
+//
+// Copies run on the graphics queue. A dedicated transfer queue is not
+// possible with asche, which creates the device with graphics and present
+// queues only.
+type Uploader struct {
+	dev vk.Device
+
+	queue     vk.Queue
+	pool      vk.CommandPool
+	alignment vk.DeviceSize
+	// newStaging creates host-visible buffers for uploads too large for
+	// the ring.
+	newStaging func(size int) *Buffer
+
+	ring *Buffer
+	// head and tail are positions in an endless stream of staging bytes;
+	// modulo the ring size they are offsets into the ring. Bytes between
+	// tail and head belong to batches that have not completed.
+	head, tail vk.DeviceSize
+
+	batch    *uploadBatch
+	inFlight []*uploadBatch
+}
+
+type uploadBatch struct {
+	cmd  vk.CommandBuffer
+	then []func(cmd vk.CommandBuffer)
+
+	fence   vk.Fence
+	end     vk.DeviceSize
+	scratch []*Buffer
+	done    bool
+}
+
+// UploadTicket tracks a flushed batch of uploads.
+type UploadTicket struct {
+	u     *Uploader
+	batch *uploadBatch
+}
+
+// Done reports without blocking whether the uploads have completed.
+func (t *UploadTicket) Done() bool {
+	if t == nil || t.batch.done {
+		return true
+	}
+	if vk.GetFenceStatus(t.u.dev, t.batch.fence) != vk.Success {
+		return false
+	}
+	t.u.Poll()
+	return true
+}
+
+// Wait blocks until the uploads have completed.
+func (t *UploadTicket) Wait() {
+	if t == nil || t.batch.done {
+		return
+	}
+	ret := vk.WaitForFences(t.u.dev, 1, []vk.Fence{t.batch.fence}, vk.True, vk.MaxUint64)
+	orPanic(as.NewError(ret))
+	t.u.Poll()
+}
+
+func (a *Application) prepareUploader() {
+	if a.uploader != nil {
+		return
+	}
+	dev := a.Context().Device()
+	platform := a.Context().Platform()
+
+	var props vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(platform.PhysicalDevice(), &props)
+	props.Deref()
+	props.Limits.Deref()
+
+	u := &Uploader{
+		dev:       dev,
+		queue:     platform.GraphicsQueue(),
+		alignment: uploadAlignment,
+		newStaging: func(size int) *Buffer {
+			return a.newBuffer(size, vk.BufferUsageTransferSrcBit,
+				vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		},
+	}
+	if align := props.Limits.OptimalBufferCopyOffsetAlignment; align > u.alignment {
+		u.alignment = align
+	}
+	ret := vk.CreateCommandPool(dev, &vk.CommandPoolCreateInfo{
+		SType:            vk.StructureTypeCommandPoolCreateInfo,
+		Flags:            vk.CommandPoolCreateFlags(vk.CommandPoolCreateTransientBit),
+		QueueFamilyIndex: platform.GraphicsQueueFamilyIndex(),
+	}, nil, &u.pool)
+	orPanic(as.NewError(ret))
+	u.ring = a.newBuffer(uploadRingSize, vk.BufferUsageTransferSrcBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	a.debug.NameBuffer(u.ring, "upload ring")
+	a.debug.Name(u.pool, "upload command pool")
+	a.uploader = u
+}
+
+func (u *Uploader) beginCommandBuffer() vk.CommandBuffer {
+	cmds := make([]vk.CommandBuffer, 1)
+	ret := vk.AllocateCommandBuffers(u.dev, &vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        u.pool,
+		Level:              vk.CommandBufferLevelPrimary,
+		CommandBufferCount: 1,
+	}, cmds)
+	orPanic(as.NewError(ret))
+	ret = vk.BeginCommandBuffer(cmds[0], &vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit),
+	})
+	orPanic(as.NewError(ret))
+	return cmds[0]
+}
+
+// current returns the batch being recorded, starting one if needed.
+func (u *Uploader) current() *uploadBatch {
+	if u.batch == nil {
+		u.batch = &uploadBatch{cmd: u.beginCommandBuffer()}
+	}
+	return u.batch
+}
+
+// stage copies data into staging memory and returns the buffer and offset
+// it landed at, waiting for earlier batches if the ring is full.
+func (u *Uploader) stage(data []byte) (*Buffer, vk.DeviceSize) {
+	ringSize := u.ring.size
+	size := (vk.DeviceSize(len(data)) + u.alignment - 1) / u.alignment * u.alignment
+	if size > ringSize {
+		scratch := u.newStaging(len(data))
+		scratch.Write(0, data)
+		b := u.current()
+		b.scratch = append(b.scratch, scratch)
+		return scratch, 0
+	}
+	for {
+		start := u.head
+		if offset := start % ringSize; offset+size > ringSize {
+			// Uploads do not wrap around the end of the ring.
+			start += ringSize - offset
+			if u.head == u.tail {
+				u.tail = start
+			}
+		}
+		if start+size-u.tail <= ringSize {
+			u.head = start + size
+			offset := start % ringSize
+			u.ring.Write(int(offset), data)
+			return u.ring, offset
+		}
+		if len(u.inFlight) == 0 {
+			// The space is held by the batch being recorded.
+			u.Flush()
+		}
+		u.waitOldest()
+	}
+}
+
+// Buffer copies data into dst at offset. dst needs TransferDst usage.
+func (u *Uploader) Buffer(dst *Buffer, offset int, data []byte) {
+	src, srcOffset := u.stage(data)
+	b := u.current()
+	vk.CmdCopyBuffer(b.cmd, src.buffer, dst.buffer, 1, []vk.BufferCopy{{
+		SrcOffset: srcOffset,
+		DstOffset: vk.DeviceSize(offset),
+		Size:      vk.DeviceSize(len(data)),
+	}})
+	barrier := vk.BufferMemoryBarrier{
+		SType:               vk.StructureTypeBufferMemoryBarrier,
+		SrcAccessMask:       vk.AccessFlags(vk.AccessTransferWriteBit),
+		DstAccessMask:       vk.AccessFlags(vk.AccessMemoryReadBit),
+		SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+		DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+		Buffer:              dst.buffer,
+		Offset:              vk.DeviceSize(offset),
+		Size:                vk.DeviceSize(len(data)),
+	}
+	u.makeVisible(b, nil, []vk.BufferMemoryBarrier{barrier})
+}
+
+// Image copies data into dst and leaves the subresources in layout. The
+// regions' buffer offsets are relative to data. A layout of
+// TransferDstOptimal keeps the image writable for commands passed to Then.
+func (u *Uploader) Image(dst vk.Image, subresource vk.ImageSubresourceRange,
+	regions []vk.BufferImageCopy, data []byte, layout vk.ImageLayout) {
+
+	src, srcOffset := u.stage(data)
+	b := u.current()
+	vk.CmdPipelineBarrier(b.cmd,
+		vk.PipelineStageFlags(vk.PipelineStageTopOfPipeBit),
+		vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+		0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{{
+			SType:               vk.StructureTypeImageMemoryBarrier,
+			DstAccessMask:       vk.AccessFlags(vk.AccessTransferWriteBit),
+			OldLayout:           vk.ImageLayoutUndefined,
+			NewLayout:           vk.ImageLayoutTransferDstOptimal,
+			SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+			DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+			Image:               dst,
+			SubresourceRange:    subresource,
+		}})
+	copies := make([]vk.BufferImageCopy, len(regions))
+	for i, region := range regions {
+		region.BufferOffset += srcOffset
+		copies[i] = region
+	}
+	vk.CmdCopyBufferToImage(b.cmd, src.buffer, dst,
+		vk.ImageLayoutTransferDstOptimal, uint32(len(copies)), copies)
+
+	barrier := vk.ImageMemoryBarrier{
+		SType:               vk.StructureTypeImageMemoryBarrier,
+		SrcAccessMask:       vk.AccessFlags(vk.AccessTransferWriteBit),
+		DstAccessMask:       vk.AccessFlags(vk.AccessMemoryReadBit),
+		OldLayout:           vk.ImageLayoutTransferDstOptimal,
+		NewLayout:           layout,
+		SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+		DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+		Image:               dst,
+		SubresourceRange:    subresource,
+	}
+	if layout == vk.ImageLayoutTransferDstOptimal {
+		barrier.DstAccessMask = vk.AccessFlags(vk.AccessTransferReadBit | vk.AccessTransferWriteBit)
+	}
+	u.makeVisible(b, []vk.ImageMemoryBarrier{barrier}, nil)
+}
+
+// makeVisible makes the copies visible to the commands that follow.
+func (u *Uploader) makeVisible(b *uploadBatch, images []vk.ImageMemoryBarrier, buffers []vk.BufferMemoryBarrier) {
+	vk.CmdPipelineBarrier(b.cmd,
+		vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+		vk.PipelineStageFlags(vk.PipelineStageAllCommandsBit),
+		0, 0, nil, uint32(len(buffers)), buffers, uint32(len(images)), images)
+}
+
+// Then records fn after the batch's copies, for work that needs them to
+// have completed such as blits.
+func (u *Uploader) Then(fn func(cmd vk.CommandBuffer)) {
+	b := u.current()
+	b.then = append(b.then, fn)
+}
+
+// Flush submits the batch recorded so far. The returned ticket is nil
+// when nothing was recorded.
+func (u *Uploader) Flush() *UploadTicket {
+	b := u.batch
+	if b == nil {
+		return nil
+	}
+	u.batch = nil
+	b.end = u.head
+
+	ret := vk.CreateFence(u.dev, &vk.FenceCreateInfo{
+		SType: vk.StructureTypeFenceCreateInfo,
+	}, nil, &b.fence)
+	orPanic(as.NewError(ret))
+
+	for _, fn := range b.then {
+		fn(b.cmd)
+	}
+	ret = vk.EndCommandBuffer(b.cmd)
+	orPanic(as.NewError(ret))
+	ret = vk.QueueSubmit(u.queue, 1, []vk.SubmitInfo{{
+		SType:              vk.StructureTypeSubmitInfo,
+		CommandBufferCount: 1,
+		PCommandBuffers:    []vk.CommandBuffer{b.cmd},
+	}}, b.fence)
+	orPanic(as.NewError(ret))
+	u.inFlight = append(u.inFlight, b)
+	return &UploadTicket{u: u, batch: b}
+}
+
+// Poll retires completed batches, releasing their staging space. It is
+// called once per frame.
+func (u *Uploader) Poll() {
+	for len(u.inFlight) > 0 {
+		b := u.inFlight[0]
+		if vk.GetFenceStatus(u.dev, b.fence) != vk.Success {
+			return
+		}
+		u.retire(b)
+		u.inFlight = u.inFlight[1:]
+	}
+}
+
+// waitOldest blocks until the oldest batch in flight completes.
+func (u *Uploader) waitOldest() {
+	b := u.inFlight[0]
+	ret := vk.WaitForFences(u.dev, 1, []vk.Fence{b.fence}, vk.True, vk.MaxUint64)
+	orPanic(as.NewError(ret))
+	u.Poll()
+}
+
+func (u *Uploader) retire(b *uploadBatch) {
+	b.done = true
+	if b.end > u.tail {
+		u.tail = b.end
+	}
+	vk.DestroyFence(u.dev, b.fence, nil)
+	vk.FreeCommandBuffers(u.dev, u.pool, 1, []vk.CommandBuffer{b.cmd})
+	for _, scratch := range b.scratch {
+		scratch.Destroy(u.dev)
+	}
+}
+
+// Destroy submits any batch still being recorded, waits for every upload
+// and frees the uploader.
+func (u *Uploader) Destroy() {
+	if u.batch != nil {
+		u.Flush()
+	}
+	for len(u.inFlight) > 0 {
+		u.waitOldest()
+	}
+	u.ring.Destroy(u.dev)
+	vk.DestroyCommandPool(u.dev, u.pool, nil)
+}
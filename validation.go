@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"unsafe"
+
+	as "github.com/vulkan-go/asche"
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// validationLayers are tried in order; the Khronos layer replaced the
+// LunarG meta layer, which older SDKs still ship.
+var validationLayers = []string{
+	"VK_LAYER_KHRONOS_validation",
+	"VK_LAYER_LUNARG_standard_validation",
+}
+
+const debugReportExtension = "VK_EXT_debug_report"
+
+func availableInstanceLayers() map[string]bool {
+	var count uint32
+	ret := vk.EnumerateInstanceLayerProperties(&count, nil)
+	orPanic(as.NewError(ret))
+	props := make([]vk.LayerProperties, count)
+	ret = vk.EnumerateInstanceLayerProperties(&count, props)
+	orPanic(as.NewError(ret))
+
+	layers := make(map[string]bool, count)
+	for _, p := range props {
+		p.Deref()
+		layers[vk.ToString(p.LayerName[:])] = true
+	}
+	return layers
+}
+
+func availableInstanceExtensions() map[string]bool {
+	var count uint32
+	ret := vk.EnumerateInstanceExtensionProperties("", &count, nil)
+	orPanic(as.NewError(ret))
+	props := make([]vk.ExtensionProperties, count)
+	ret = vk.EnumerateInstanceExtensionProperties("", &count, props)
+	orPanic(as.NewError(ret))
+
+	extensions := make(map[string]bool, count)
+	for _, p := range props {
+		p.Deref()
+		extensions[vk.ToString(p.ExtensionName[:])] = true
+	}
+	return extensions
+}
+
+// VulkanLayers enables the first available validation layer when
+// validation was requested. Running without one is not an error, since
+// end users rarely have the SDK installed.
+func (a *Application) VulkanLayers() []string {
+	if !a.debugEnabled {
+		return nil
+	}
+	available := availableInstanceLayers()
+	for _, layer := range validationLayers {
+		if available[layer] {
+			log.Printf("validation: using %s", layer)
+			return []string{layer}
+		}
+	}
+	log.Printf("validation: no validation layer is installed, continuing without")
+	return nil
+}
+
+// prepareDebugReport installs the callback that routes layer messages into
+// the log. asche only installs its own when VulkanDebug is true; this one
+// is used instead so messages carry their severity and errors can be fatal.
+func (a *Application) prepareDebugReport() {
+	if !a.debugEnabled || a.debugCallback != vk.NullDebugReportCallback {
+		return
+	}
+	if !availableInstanceExtensions()[debugReportExtension] {
+		log.Printf("validation: %s is not available, layer messages will not be logged",
+			debugReportExtension)
+		return
+	}
+	flags := vk.DebugReportErrorBit | vk.DebugReportWarningBit |
+		vk.DebugReportPerformanceWarningBit
+	if a.config.ValidationVerbose {
+		flags |= vk.DebugReportInformationBit | vk.DebugReportDebugBit
+	}
+	ret := vk.CreateDebugReportCallback(a.Context().Platform().Instance(),
+		&vk.DebugReportCallbackCreateInfo{
+			SType:       vk.StructureTypeDebugReportCallbackCreateInfo,
+			Flags:       vk.DebugReportFlags(flags),
+			PfnCallback: a.debugReport,
+		}, nil, &a.debugCallback)
+	orPanic(as.NewError(ret))
+}
+
+func (a *Application) debugReport(flags vk.DebugReportFlags, objectType vk.DebugReportObjectType,
+	object uint64, location uint, messageCode int32, pLayerPrefix string,
+	pMessage string, pUserData unsafe.Pointer) vk.Bool32 {
+
+	severity := "info"
+	switch {
+	case flags&vk.DebugReportFlags(vk.DebugReportErrorBit) != 0:
+		severity = "error"
+	case flags&vk.DebugReportFlags(vk.DebugReportWarningBit) != 0:
+		severity = "warning"
+	case flags&vk.DebugReportFlags(vk.DebugReportPerformanceWarningBit) != 0:
+		severity = "performance"
+	case flags&vk.DebugReportFlags(vk.DebugReportDebugBit) != 0:
+		severity = "debug"
+	}
+	msg := fmt.Sprintf("vulkan %s: [%s %d] %s", severity, pLayerPrefix, messageCode, pMessage)
+	log.Println(msg)
+	// Layers may call back from any thread the application calls Vulkan
+	// on, and a panic must not unwind through C, so a fatal error is only
+	// recorded here for the main loop to act on.
+	if severity == "error" {
+		atomic.AddInt32(&a.validationErrors, 1)
+		if a.config.ValidationFatal {
+			atomic.StoreInt32(&a.validationFailed, 1)
+		}
+	}
+	// Returning false lets the call that triggered the message proceed.
+	return vk.False
+}
+
+// validationFatal reports whether a validation error was reported with
+// -validation-fatal set, so the application should stop.
+func (a *Application) validationFatal() bool {
+	return atomic.LoadInt32(&a.validationFailed) != 0
+}
+
+func (a *Application) destroyDebugReport() {
+	if a.debugCallback == vk.NullDebugReportCallback {
+		return
+	}
+	vk.DestroyDebugReportCallback(a.Context().Platform().Instance(), a.debugCallback, nil)
+	a.debugCallback = vk.NullDebugReportCallback
+	if n := atomic.LoadInt32(&a.validationErrors); n > 0 {
+		log.Printf("validation: %d errors reported", n)
+	}
+}